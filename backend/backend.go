@@ -63,6 +63,7 @@ type ProverConfig struct {
 	ChallengeHash  hash.Hash
 	KZGFoldingHash hash.Hash
 	Accelerator    string
+	MSMBackend     any
 }
 
 // NewProverConfig returns a default ProverConfig with given prover options opts
@@ -136,6 +137,21 @@ func WithIcicleAcceleration() ProverOption {
 	}
 }
 
+// WithMSMBackend sets the backend computing the multi-scalar multiplications
+// of the prover, for example to offload them to a GPU or a remote worker. The
+// backend must implement the MSMBackend interface of the proving system for
+// the curve of the constraint system, for example
+// [github.com/consensys/gnark/backend/groth16/bn254.MSMBackend]. If not set,
+// the multi-scalar multiplications are computed on the CPU.
+//
+// This option is currently supported by the Groth16 prover only.
+func WithMSMBackend(msm any) ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.MSMBackend = msm
+		return nil
+	}
+}
+
 // VerifierOption defines option for altering the behavior of the verifier. See
 // the descriptions of functions returning instances of this type for
 // implemented options.
@@ -59,6 +59,35 @@ func (proof *Proof) CurveID() ecc.ID {
 	return curve.ID
 }
 
+// MSMBackend computes the multi-scalar multiplications of the prover. A custom
+// backend, for example offloading the computations to a GPU or a remote
+// worker, can be set with [backend.WithMSMBackend].
+//
+// The methods are called concurrently and must return the same results as
+// the CPU implementation. config is the configuration used by the CPU
+// implementation and may be ignored.
+type MSMBackend interface {
+	// MultiExpG1 returns ∑ scalars[i]⋅bases[i] in G1.
+	MultiExpG1(bases []curve.G1Affine, scalars []fr.Element, config ecc.MultiExpConfig) (curve.G1Jac, error)
+	// MultiExpG2 returns ∑ scalars[i]⋅bases[i] in G2.
+	MultiExpG2(bases []curve.G2Affine, scalars []fr.Element, config ecc.MultiExpConfig) (curve.G2Jac, error)
+}
+
+// cpuMSM is the default MSMBackend.
+type cpuMSM struct{}
+
+func (cpuMSM) MultiExpG1(bases []curve.G1Affine, scalars []fr.Element, config ecc.MultiExpConfig) (curve.G1Jac, error) {
+	var res curve.G1Jac
+	_, err := res.MultiExp(bases, scalars, config)
+	return res, err
+}
+
+func (cpuMSM) MultiExpG2(bases []curve.G2Affine, scalars []fr.Element, config ecc.MultiExpConfig) (curve.G2Jac, error) {
+	var res curve.G2Jac
+	_, err := res.MultiExp(bases, scalars, config)
+	return res, err
+}
+
 // Prove generates the proof of knowledge of a r1cs with full witness (secret + public part).
 func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
 	opt, err := backend.NewProverConfig(opts...)
@@ -68,6 +97,13 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	if opt.HashToFieldFn == nil {
 		opt.HashToFieldFn = hash_to_field.New([]byte(constraint.CommitmentDst))
 	}
+	var msm MSMBackend = cpuMSM{}
+	if opt.MSMBackend != nil {
+		var ok bool
+		if msm, ok = opt.MSMBackend.(MSMBackend); !ok {
+			return nil, fmt.Errorf("MSM backend %T is not a groth16 MSMBackend for %s", opt.MSMBackend, curve.ID)
+		}
+	}
 
 	log := logger.Logger().With().Str("curve", r1cs.CurveID().String()).Str("acceleration", "none").Int("nbConstraints", r1cs.GetNbConstraints()).Str("backend", "groth16").Logger()
 
@@ -198,7 +234,8 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	chBs1Done := make(chan error, 1)
 	computeBS1 := func() {
 		<-chWireValuesB
-		if _, err := bs1.MultiExp(pk.G1.B, wireValuesB, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		var err error
+		if bs1, err = msm.MultiExpG1(pk.G1.B, wireValuesB, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chBs1Done <- err
 			close(chBs1Done)
 			return
@@ -211,7 +248,8 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	chArDone := make(chan error, 1)
 	computeAR1 := func() {
 		<-chWireValuesA
-		if _, err := ar.MultiExp(pk.G1.A, wireValuesA, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		var err error
+		if ar, err = msm.MultiExpG1(pk.G1.A, wireValuesA, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chArDone <- err
 			close(chArDone)
 			return
@@ -227,11 +265,12 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		// we could NOT split the Krs multiExp in 2, and just append pk.G1.K and pk.G1.Z
 		// however, having similar lengths for our tasks helps with parallelism
 
-		var krs, krs2, p1 curve.G1Jac
+		var krs2, p1 curve.G1Jac
 		chKrs2Done := make(chan error, 1)
 		sizeH := int(pk.Domain.Cardinality - 1) // comes from the fact the deg(H)=(n-1)+(n-1)-n=n-2
 		go func() {
-			_, err := krs2.MultiExp(pk.G1.Z, h[:sizeH], ecc.MultiExpConfig{NbTasks: n / 2})
+			var err error
+			krs2, err = msm.MultiExpG1(pk.G1.Z, h[:sizeH], ecc.MultiExpConfig{NbTasks: n / 2})
 			chKrs2Done <- err
 		}()
 
@@ -241,7 +280,8 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		toRemove = append(toRemove, commitmentInfo.CommitmentIndexes())
 		_wireValues := filterHeap(wireValues[r1cs.GetNbPublicVariables():], r1cs.GetNbPublicVariables(), internal.ConcatAll(toRemove...))
 
-		if _, err := krs.MultiExp(pk.G1.K, _wireValues, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		krs, err := msm.MultiExpG1(pk.G1.K, _wireValues, ecc.MultiExpConfig{NbTasks: n / 2})
+		if err != nil {
 			chKrsDone <- err
 			return
 		}
@@ -279,7 +319,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 
 	computeBS2 := func() error {
 		// Bs2 (1 multi exp G2 - size = len(wires))
-		var Bs, deltaS curve.G2Jac
+		var deltaS curve.G2Jac
 
 		nbTasks := n
 		if nbTasks <= 16 {
@@ -287,7 +327,8 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 			nbTasks *= 2
 		}
 		<-chWireValuesB
-		if _, err := Bs.MultiExp(pk.G2.B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+		Bs, err := msm.MultiExpG2(pk.G2.B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks})
+		if err != nil {
 			return err
 		}
 
@@ -1,5 +1,13 @@
 package groth16
 
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"golang.org/x/crypto/sha3"
+)
+
 // solidityTemplate
 // this is an experimental feature and gnark solidity generator as not been thoroughly tested
 const solidityTemplate = `
@@ -543,3 +551,43 @@ contract Verifier {
     }
 }
 `
+
+// ExportCalldata returns the ABI-encoded calldata of a call to the verifyProof
+// function of the Solidity verifier exported by [VerifyingKey.ExportSolidity],
+// with the proof and the public inputs, in the order of the public witness.
+//
+// The proof points (A, B, C) are encoded in the EIP-197 format expected by the
+// verifier, the coordinates of the G2 point B starting with their imaginary
+// part. The public inputs are converted with [fr.Element.SetInterface], which
+// reduces them. The exported verifier does not support commitments, and an
+// error is returned if the proof has some.
+func ExportCalldata(proof *Proof, publicInputs []interface{}) ([]byte, error) {
+	if len(proof.Commitments) > 0 {
+		return nil, errors.New("the solidity verifier does not support commitments")
+	}
+
+	// function selector
+	h := sha3.NewLegacyKeccak256()
+	fmt.Fprintf(h, "verifyProof(uint256[8],uint256[%d])", len(publicInputs))
+	res := make([]byte, 0, 4+(8+len(publicInputs))*fr.Bytes)
+	res = append(res, h.Sum(nil)[:4]...)
+
+	// uint256[8] proof, a static array encoded in place
+	ar := proof.Ar.RawBytes()
+	res = append(res, ar[:]...)
+	bs := proof.Bs.RawBytes()
+	res = append(res, bs[:]...)
+	krs := proof.Krs.RawBytes()
+	res = append(res, krs[:]...)
+
+	// uint256[n] input
+	for i, v := range publicInputs {
+		var e fr.Element
+		if _, err := e.SetInterface(v); err != nil {
+			return nil, fmt.Errorf("public input %d: %w", i, err)
+		}
+		b := e.Bytes()
+		res = append(res, b[:]...)
+	}
+	return res, nil
+}
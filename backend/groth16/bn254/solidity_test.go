@@ -0,0 +1,53 @@
+package groth16
+
+import (
+	"math/big"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestExportCalldata(t *testing.T) {
+	assert := require.New(t)
+
+	_, _, g1, g2 := curve.Generators()
+	var proof Proof
+	proof.Ar.ScalarMultiplication(&g1, big.NewInt(3))
+	proof.Bs.ScalarMultiplication(&g2, big.NewInt(5))
+	proof.Krs.ScalarMultiplication(&g1, big.NewInt(7))
+
+	calldata, err := ExportCalldata(&proof, []interface{}{42, "-1"})
+	assert.NoError(err)
+	assert.Len(calldata, 4+(8+2)*32)
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte("verifyProof(uint256[8],uint256[2])"))
+	assert.Equal(h.Sum(nil)[:4], calldata[:4])
+
+	word := func(i int) *big.Int {
+		return new(big.Int).SetBytes(calldata[4+32*i : 4+32*(i+1)])
+	}
+	var x big.Int
+	expected := []*big.Int{
+		proof.Ar.X.BigInt(new(big.Int)), proof.Ar.Y.BigInt(new(big.Int)),
+		// the G2 coordinates start with their imaginary part
+		proof.Bs.X.A1.BigInt(new(big.Int)), proof.Bs.X.A0.BigInt(new(big.Int)),
+		proof.Bs.Y.A1.BigInt(new(big.Int)), proof.Bs.Y.A0.BigInt(new(big.Int)),
+		proof.Krs.X.BigInt(new(big.Int)), proof.Krs.Y.BigInt(new(big.Int)),
+		big.NewInt(42),
+		// the inputs are reduced
+		x.Sub(curve.ID.ScalarField(), big.NewInt(1)),
+	}
+	for i := range expected {
+		assert.Equal(expected[i], word(i), "word %d", i)
+	}
+
+	_, err = ExportCalldata(&proof, []interface{}{struct{}{}})
+	assert.Error(err)
+
+	proof.Commitments = []curve.G1Affine{g1}
+	_, err = ExportCalldata(&proof, nil)
+	assert.Error(err)
+}
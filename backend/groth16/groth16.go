@@ -20,6 +20,8 @@
 package groth16
 
 import (
+	"context"
+	"fmt"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -52,6 +54,8 @@ import (
 	icicle_bn254 "github.com/consensys/gnark/backend/groth16/bn254/icicle"
 	groth16_bw6633 "github.com/consensys/gnark/backend/groth16/bw6-633"
 	groth16_bw6761 "github.com/consensys/gnark/backend/groth16/bw6-761"
+
+	"golang.org/x/sync/errgroup"
 )
 
 type groth16Object interface {
@@ -198,6 +202,54 @@ func Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.
 	}
 }
 
+// BatchProve runs the groth16.Prove algorithm for each of the full witnesses
+// and returns the proofs in the same order.
+//
+// At most nbConcurrent calls to [Prove] run at the same time. The stages of
+// the proofs are not scheduled separately: each call solves the constraint
+// system and then computes its MSMs, but as the solving is mostly sequential
+// and the MSMs are multi-threaded, running a few proofs concurrently (for
+// example 2) improves the throughput on multi-core machines, at the cost of
+// the memory of the proofs in flight. The proofs are identical in
+// distribution to the ones returned by sequential calls to [Prove].
+//
+// On the first error, the proofs which are not started yet are cancelled and
+// the error is returned once the proofs in flight are done.
+//
+// The options are shared by all the proofs, so a custom hash-to-field function
+// given with [backend.WithProverHashToFieldFunction] must be safe for
+// concurrent use.
+func BatchProve(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitnesses []witness.Witness, nbConcurrent int, opts ...backend.ProverOption) ([]Proof, error) {
+	if nbConcurrent < 1 {
+		return nil, fmt.Errorf("invalid number of concurrent proofs %d", nbConcurrent)
+	}
+	proofs := make([]Proof, len(fullWitnesses))
+
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(nbConcurrent)
+	for i := range fullWitnesses {
+		if ctx.Err() != nil {
+			break
+		}
+		i := i
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			proof, err := Prove(r1cs, pk, fullWitnesses[i], opts...)
+			if err != nil {
+				return fmt.Errorf("proof %d: %w", i, err)
+			}
+			proofs[i] = proof
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return proofs, nil
+}
+
 // Setup runs groth16.Setup with provided R1CS and outputs a key pair associated with the circuit.
 //
 // Note that careful consideration must be given to this step in a production environment.
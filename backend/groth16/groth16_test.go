@@ -1,15 +1,22 @@
 package groth16_test
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"testing"
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
+	curve_bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/test"
@@ -51,6 +58,144 @@ func TestCustomHashToField(t *testing.T) {
 	}
 }
 
+func TestBatchProve(t *testing.T) {
+	assert := test.NewAssert(t)
+	const batchSize = 5
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &batchCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	witnesses := make([]witness.Witness, batchSize)
+	for i := range witnesses {
+		witnesses[i], err = frontend.NewWitness(&batchCircuit{X: i, Y: i * i}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+	proofs, err := groth16.BatchProve(ccs, pk, witnesses, 2)
+	assert.NoError(err)
+	assert.Equal(batchSize, len(proofs))
+	for i := range proofs {
+		pubWitness, err := witnesses[i].Public()
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proofs[i], vk, pubWitness), "proof %d", i)
+		// proofs are in the order of the witnesses
+		if i > 0 {
+			assert.Error(groth16.Verify(proofs[i-1], vk, pubWitness))
+		}
+	}
+
+	invalid, err := frontend.NewWitness(&batchCircuit{X: 2, Y: 5}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = groth16.BatchProve(ccs, pk, append(witnesses, invalid), 2)
+	assert.ErrorContains(err, "proof 5")
+	// the first error cancels the remaining proofs
+	_, err = groth16.BatchProve(ccs, pk, append([]witness.Witness{invalid}, witnesses...), 1)
+	assert.ErrorContains(err, "proof 0")
+
+	// sequential
+	proofs, err = groth16.BatchProve(ccs, pk, witnesses, 1)
+	assert.NoError(err)
+	assert.Equal(batchSize, len(proofs))
+	_, err = groth16.BatchProve(ccs, pk, witnesses, 0)
+	assert.Error(err)
+}
+
+// mockMSM records the multi-scalar multiplications of the prover, keyed by
+// their first base, and computes them on the CPU.
+type mockMSM struct {
+	mu sync.Mutex
+	g1 map[*curve_bn254.G1Affine][]fr_bn254.Element
+	g2 map[*curve_bn254.G2Affine][]fr_bn254.Element
+}
+
+func (m *mockMSM) MultiExpG1(bases []curve_bn254.G1Affine, scalars []fr_bn254.Element, config ecc.MultiExpConfig) (curve_bn254.G1Jac, error) {
+	m.mu.Lock()
+	m.g1[&bases[0]] = scalars
+	m.mu.Unlock()
+	var res curve_bn254.G1Jac
+	_, err := res.MultiExp(bases, scalars, config)
+	return res, err
+}
+
+func (m *mockMSM) MultiExpG2(bases []curve_bn254.G2Affine, scalars []fr_bn254.Element, config ecc.MultiExpConfig) (curve_bn254.G2Jac, error) {
+	m.mu.Lock()
+	m.g2[&bases[0]] = scalars
+	m.mu.Unlock()
+	var res curve_bn254.G2Jac
+	_, err := res.MultiExp(bases, scalars, config)
+	return res, err
+}
+
+type failingMSM struct{}
+
+func (failingMSM) MultiExpG1([]curve_bn254.G1Affine, []fr_bn254.Element, ecc.MultiExpConfig) (curve_bn254.G1Jac, error) {
+	return curve_bn254.G1Jac{}, errors.New("worker unavailable")
+}
+
+func (failingMSM) MultiExpG2([]curve_bn254.G2Affine, []fr_bn254.Element, ecc.MultiExpConfig) (curve_bn254.G2Jac, error) {
+	return curve_bn254.G2Jac{}, errors.New("worker unavailable")
+}
+
+func TestMSMBackend(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &batchCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&batchCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pubWitness, err := w.Public()
+	assert.NoError(err)
+
+	msm := &mockMSM{
+		g1: make(map[*curve_bn254.G1Affine][]fr_bn254.Element),
+		g2: make(map[*curve_bn254.G2Affine][]fr_bn254.Element),
+	}
+	proof, err := groth16.Prove(ccs, pk, w, backend.WithMSMBackend(groth16_bn254.MSMBackend(msm)))
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, pubWitness))
+
+	// the wire values, without the ones of the points at infinity
+	solution, err := ccs.Solve(w)
+	assert.NoError(err)
+	wireValues := solution.(*cs_bn254.R1CSSolution).W
+	filter := func(infinity []bool) []fr_bn254.Element {
+		var res []fr_bn254.Element
+		for i := range wireValues {
+			if !infinity[i] {
+				res = append(res, wireValues[i])
+			}
+		}
+		return res
+	}
+	_pk := pk.(*groth16_bn254.ProvingKey)
+	assert.Equal(4, len(msm.g1))
+	assert.Equal(1, len(msm.g2))
+	assert.Equal(filter(_pk.InfinityA), msm.g1[&_pk.G1.A[0]])
+	assert.Equal(filter(_pk.InfinityB), msm.g1[&_pk.G1.B[0]])
+	assert.Equal([]fr_bn254.Element(wireValues[ccs.GetNbPublicVariables():]), msm.g1[&_pk.G1.K[0]])
+	assert.Contains(msm.g1, &_pk.G1.Z[0])
+	assert.Equal(filter(_pk.InfinityB), msm.g2[&_pk.G2.B[0]])
+
+	// errors of the backend are returned
+	_, err = groth16.Prove(ccs, pk, w, backend.WithMSMBackend(failingMSM{}))
+	assert.Error(err)
+
+	// the backend must be for the curve of the constraint system
+	_, err = groth16.Prove(ccs, pk, w, backend.WithMSMBackend(struct{}{}))
+	assert.Error(err)
+}
+
+type batchCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *batchCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -87,6 +232,35 @@ func BenchmarkProver(b *testing.B) {
 	}
 }
 
+func BenchmarkBatchProver(b *testing.B) {
+	const batchSize = 100
+	r1cs, _solution := referenceCircuit(ecc.BN254)
+	fullWitness, err := frontend.NewWitness(_solution, ecc.BN254.ScalarField())
+	if err != nil {
+		b.Fatal(err)
+	}
+	pk, err := groth16.DummySetup(r1cs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	witnesses := make([]witness.Witness, batchSize)
+	for i := range witnesses {
+		witnesses[i] = fullWitness
+	}
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range witnesses {
+				_, _ = groth16.Prove(r1cs, pk, witnesses[j])
+			}
+		}
+	})
+	b.Run("concurrent", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = groth16.BatchProve(r1cs, pk, witnesses, 2)
+		}
+	})
+}
+
 func BenchmarkVerifier(b *testing.B) {
 	for _, curve := range getCurves() {
 		b.Run(curve.String(), func(b *testing.B) {
@@ -20,6 +20,7 @@
 package plonk
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -122,6 +123,87 @@ func Setup(ccs constraint.ConstraintSystem, srs, srsLagrange kzg.SRS) (ProvingKe
 
 }
 
+// SetupWithSRS is like [Setup], but only takes the kzg SRS in canonical form
+// and derives its lagrange form for the size of the constraint system. As the
+// SRS is universal, the same SRS can be used for the setup of any constraint
+// system which is small enough, with the number of constraints plus the
+// number of public inputs at most the size of the SRS minus 3.
+//
+// For test purposes, see unsafekzg.NewSRSWithSize in the test/unsafekzg
+// package.
+func SetupWithSRS(ccs constraint.ConstraintSystem, srs kzg.SRS) (ProvingKey, VerifyingKey, error) {
+	size := ecc.NextPowerOfTwo(uint64(ccs.GetNbConstraints() + ccs.GetNbPublicVariables()))
+	var srsLagrange kzg.SRS
+	switch tsrs := srs.(type) {
+	case *kzg_bn254.SRS:
+		if len(tsrs.Pk.G1) < int(size)+3 {
+			return nil, nil, fmt.Errorf("kzg srs is too small: got %d, need %d", len(tsrs.Pk.G1), size+3)
+		}
+		lagrange, err := kzg_bn254.ToLagrangeG1(tsrs.Pk.G1[:size])
+		if err != nil {
+			return nil, nil, err
+		}
+		srsLagrange = &kzg_bn254.SRS{Pk: kzg_bn254.ProvingKey{G1: lagrange}, Vk: tsrs.Vk}
+	case *kzg_bls12381.SRS:
+		if len(tsrs.Pk.G1) < int(size)+3 {
+			return nil, nil, fmt.Errorf("kzg srs is too small: got %d, need %d", len(tsrs.Pk.G1), size+3)
+		}
+		lagrange, err := kzg_bls12381.ToLagrangeG1(tsrs.Pk.G1[:size])
+		if err != nil {
+			return nil, nil, err
+		}
+		srsLagrange = &kzg_bls12381.SRS{Pk: kzg_bls12381.ProvingKey{G1: lagrange}, Vk: tsrs.Vk}
+	case *kzg_bls12377.SRS:
+		if len(tsrs.Pk.G1) < int(size)+3 {
+			return nil, nil, fmt.Errorf("kzg srs is too small: got %d, need %d", len(tsrs.Pk.G1), size+3)
+		}
+		lagrange, err := kzg_bls12377.ToLagrangeG1(tsrs.Pk.G1[:size])
+		if err != nil {
+			return nil, nil, err
+		}
+		srsLagrange = &kzg_bls12377.SRS{Pk: kzg_bls12377.ProvingKey{G1: lagrange}, Vk: tsrs.Vk}
+	case *kzg_bw6761.SRS:
+		if len(tsrs.Pk.G1) < int(size)+3 {
+			return nil, nil, fmt.Errorf("kzg srs is too small: got %d, need %d", len(tsrs.Pk.G1), size+3)
+		}
+		lagrange, err := kzg_bw6761.ToLagrangeG1(tsrs.Pk.G1[:size])
+		if err != nil {
+			return nil, nil, err
+		}
+		srsLagrange = &kzg_bw6761.SRS{Pk: kzg_bw6761.ProvingKey{G1: lagrange}, Vk: tsrs.Vk}
+	case *kzg_bls24317.SRS:
+		if len(tsrs.Pk.G1) < int(size)+3 {
+			return nil, nil, fmt.Errorf("kzg srs is too small: got %d, need %d", len(tsrs.Pk.G1), size+3)
+		}
+		lagrange, err := kzg_bls24317.ToLagrangeG1(tsrs.Pk.G1[:size])
+		if err != nil {
+			return nil, nil, err
+		}
+		srsLagrange = &kzg_bls24317.SRS{Pk: kzg_bls24317.ProvingKey{G1: lagrange}, Vk: tsrs.Vk}
+	case *kzg_bls24315.SRS:
+		if len(tsrs.Pk.G1) < int(size)+3 {
+			return nil, nil, fmt.Errorf("kzg srs is too small: got %d, need %d", len(tsrs.Pk.G1), size+3)
+		}
+		lagrange, err := kzg_bls24315.ToLagrangeG1(tsrs.Pk.G1[:size])
+		if err != nil {
+			return nil, nil, err
+		}
+		srsLagrange = &kzg_bls24315.SRS{Pk: kzg_bls24315.ProvingKey{G1: lagrange}, Vk: tsrs.Vk}
+	case *kzg_bw6633.SRS:
+		if len(tsrs.Pk.G1) < int(size)+3 {
+			return nil, nil, fmt.Errorf("kzg srs is too small: got %d, need %d", len(tsrs.Pk.G1), size+3)
+		}
+		lagrange, err := kzg_bw6633.ToLagrangeG1(tsrs.Pk.G1[:size])
+		if err != nil {
+			return nil, nil, err
+		}
+		srsLagrange = &kzg_bw6633.SRS{Pk: kzg_bw6633.ProvingKey{G1: lagrange}, Vk: tsrs.Vk}
+	default:
+		return nil, nil, fmt.Errorf("unrecognized kzg SRS type %T", srs)
+	}
+	return Setup(ccs, srs, srsLagrange)
+}
+
 // Prove generates PLONK proof from a circuit, associated preprocessed public data, and the witness
 // if the force flag is set:
 //
@@ -68,6 +68,41 @@ func TestProver(t *testing.T) {
 	}
 }
 
+func TestSetupWithSRS(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		curve := curve
+		assert.Run(func(assert *test.Assert) {
+			srs, err := unsafekzg.NewSRSWithSize(curve, 1<<6)
+			assert.NoError(err)
+
+			// two different circuits of different sizes sharing the same SRS.
+			for _, nbConstraints := range []int{5, 50} {
+				ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+				assert.NoError(err)
+				pk, vk, err := plonk.SetupWithSRS(ccs, srs)
+				assert.NoError(err)
+
+				exp := new(big.Int).Lsh(big.NewInt(1), uint(nbConstraints))
+				y := new(big.Int).Exp(big.NewInt(2), exp, curve.ScalarField())
+				w, err := frontend.NewWitness(&refCircuit{X: 2, Y: y}, curve.ScalarField())
+				assert.NoError(err)
+				proof, err := plonk.Prove(ccs, pk, w)
+				assert.NoError(err)
+				pw, err := w.Public()
+				assert.NoError(err)
+				assert.NoError(plonk.Verify(proof, vk, pw))
+			}
+
+			// the SRS is too small for larger circuits.
+			ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &refCircuit{nbConstraints: 100})
+			assert.NoError(err)
+			_, _, err = plonk.SetupWithSRS(ccs, srs)
+			assert.Error(err)
+		}, curve.String())
+	}
+}
+
 func TestCustomHashToField(t *testing.T) {
 	assert := test.NewAssert(t)
 	assignment := &commitmentCircuit{X: 1}
@@ -46,6 +46,7 @@ import (
 	"io"
 	"math/big"
 	"reflect"
+	"strings"
 
 	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -280,7 +281,7 @@ func (w *witness) ToJSON(s *schema.Schema) ([]byte, error) {
 			tValue.Set(reflect.ValueOf(v))
 		}
 		return nil
-	}); err != nil {
+	}, s.InstanceOptions()...); err != nil {
 		return nil, err
 	}
 
@@ -292,7 +293,7 @@ func (w *witness) ToJSON(s *schema.Schema) ([]byte, error) {
 				tValue.Set(reflect.ValueOf(v))
 			}
 			return nil
-		}); err != nil {
+		}, s.InstanceOptions()...); err != nil {
 			return nil, err
 		}
 	}
@@ -314,6 +315,12 @@ func (w *witness) FromJSON(s *schema.Schema, data []byte) error {
 	// note that we pass a pointer here to have nil for zero values
 	instance := s.Instantiate(ptrTyp)
 
+	// decoding in the arrays of the instance silently drops the extra elements
+	// and leaves the missing ones unassigned, we check the lengths first.
+	if err := checkJSONLengths(s.NameStrategy(), s.Fields, data, ""); err != nil {
+		return err
+	}
+
 	dec := json.NewDecoder(bytes.NewReader(data))
 	dec.DisallowUnknownFields()
 
@@ -336,7 +343,7 @@ func (w *witness) FromJSON(s *schema.Schema, data []byte) error {
 			publicValues = append(publicValues, reflect.Indirect(tValue).Interface())
 		}
 		return nil
-	}); err != nil {
+	}, s.InstanceOptions()...); err != nil {
 		// missing public values
 		return err
 	}
@@ -352,7 +359,7 @@ func (w *witness) FromJSON(s *schema.Schema, data []byte) error {
 			secretValues = append(secretValues, reflect.Indirect(tValue).Interface())
 		}
 		return nil
-	}); err != nil {
+	}, s.InstanceOptions()...); err != nil {
 		// missing secret values, we just do the public part.
 		publicOnly = true
 	}
@@ -386,3 +393,63 @@ func (w *witness) FromJSON(s *schema.Schema, data []byte) error {
 
 	return w.Fill(s.NbPublic, s.NbSecret, chValues)
 }
+
+// checkJSONLengths checks that the lengths of the JSON arrays in data match the
+// sizes of the arrays of the schema fields, at any nesting level. The errors
+// are reported with the full name of the array, for example "M_1" for the
+// second row of a matrix M.
+func checkJSONLengths(names schema.NameStrategy, fields []schema.Field, data json.RawMessage, path string) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil || obj == nil {
+		// type errors are reported by the decoder
+		return nil
+	}
+	for _, f := range fields {
+		name := f.Name
+		if f.NameTag != "" {
+			name = f.NameTag
+		}
+		v, ok := obj[name]
+		if !ok {
+			// encoding/json matches the keys case-insensitively
+			for k := range obj {
+				if strings.EqualFold(k, name) {
+					v, ok = obj[k], true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		if err := checkJSONField(names, f, v, schema.JoinName(names, path, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkJSONField(names schema.NameStrategy, f schema.Field, data json.RawMessage, path string) error {
+	switch f.Type {
+	case schema.Struct, schema.Map:
+		return checkJSONLengths(names, f.SubFields, data, path)
+	case schema.Array:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil || elems == nil {
+			return nil
+		}
+		if len(elems) != f.ArraySize {
+			return fmt.Errorf("%s: expected %d elements, got %d", path, f.ArraySize, len(elems))
+		}
+		if len(f.SubFields) == 0 {
+			// array of leaves
+			return nil
+		}
+		for i := range elems {
+			if err := checkJSONField(names, f.SubFields[0], elems[i], schema.JoinName(names, path, schema.IndexName(names, i, len(elems)))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
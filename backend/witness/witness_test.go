@@ -181,3 +181,101 @@ func TestVariableInitHook(t *testing.T) {
 	assert.True(ok)
 	assert.Len(fw, 10, "invalid length")
 }
+
+type matrixCircuit struct {
+	Y frontend.Variable `gnark:",public"`
+	M [][]frontend.Variable
+}
+
+func (c *matrixCircuit) Define(frontend.API) error {
+	return nil
+}
+
+func TestFromJSONNestedSlices(t *testing.T) {
+	assert := require.New(t)
+
+	assignment := &matrixCircuit{
+		Y: 1,
+		M: [][]frontend.Variable{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}},
+	}
+	expected, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	s, err := frontend.NewSchema(assignment)
+	assert.NoError(err)
+	assert.Equal(12, s.NbSecret)
+
+	w, err := witness.New(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	assert.NoError(w.FromJSON(s, []byte(`{"Y":1,"M":[[1,2,3,4],[5,6,7,8],[9,10,11,12]]}`)))
+	assert.Equal(expected.Vector(), w.Vector())
+
+	err = w.FromJSON(s, []byte(`{"Y":1,"M":[[1,2,3,4],[5,6,7],[9,10,11,12]]}`))
+	assert.EqualError(err, "M_1: expected 4 elements, got 3")
+	err = w.FromJSON(s, []byte(`{"Y":1,"M":[[1,2,3,4],[5,6,7,8,0],[9,10,11,12]]}`))
+	assert.EqualError(err, "M_1: expected 4 elements, got 5")
+	err = w.FromJSON(s, []byte(`{"Y":1,"M":[[1,2,3,4],[5,6,7,8]]}`))
+	assert.EqualError(err, "M: expected 3 elements, got 2")
+}
+
+type mapCircuit struct {
+	Y frontend.Variable `gnark:",public"`
+	M map[uint]frontend.Variable
+}
+
+func (c *mapCircuit) Define(frontend.API) error {
+	return nil
+}
+
+func TestFromJSONMap(t *testing.T) {
+	assert := require.New(t)
+
+	assignment := &mapCircuit{
+		Y: 1,
+		M: map[uint]frontend.Variable{10: 2, 9: 3},
+	}
+	expected, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	s, err := frontend.NewSchema(assignment)
+	assert.NoError(err)
+	assert.Equal(2, s.NbSecret)
+
+	w, err := witness.New(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	assert.NoError(w.FromJSON(s, []byte(`{"Y":1,"M":{"10":2,"9":3}}`)))
+	assert.Equal(expected.Vector(), w.Vector())
+
+	data, err := w.ToJSON(s)
+	assert.NoError(err)
+	assert.JSONEq(`{"Y":1,"M":{"9":3,"10":2}}`, string(data))
+}
+
+type constantsCircuit struct {
+	Y frontend.Variable `gnark:",public"`
+	A []frontend.Variable
+}
+
+func (c *constantsCircuit) Define(frontend.API) error {
+	return nil
+}
+
+func TestJSONConstants(t *testing.T) {
+	assert := require.New(t)
+
+	// the constant elements keep their slot in the JSON arrays
+	s, err := frontend.NewSchema(&constantsCircuit{A: []frontend.Variable{nil, frontend.Constant(5), nil}})
+	assert.NoError(err)
+	assert.Equal(2, s.NbSecret)
+	assert.Equal(3, s.Fields[1].ArraySize)
+	assert.Equal([]int{1}, s.Fields[1].Constants)
+
+	expected, err := frontend.NewWitness(&constantsCircuit{Y: 1, A: []frontend.Variable{2, frontend.Constant(5), 3}}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	w, err := witness.New(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	assert.NoError(w.FromJSON(s, []byte(`{"Y":1,"A":[2,null,3]}`)))
+	assert.Equal(expected.Vector(), w.Vector())
+
+	data, err := w.ToJSON(s)
+	assert.NoError(err)
+	assert.JSONEq(`{"Y":1,"A":[2,null,3]}`, string(data))
+}
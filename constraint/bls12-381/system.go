@@ -91,6 +91,13 @@ func (cs *system) Solve(witness witness.Witness, opts ...csolver.Option) (any, e
 		return nil, err
 	}
 
+	if solver.checkWidths {
+		if err := solver.checkWidthAnnotations(cs.WidthAnnotations); err != nil {
+			log.Err(err).Send()
+			return nil, err
+		}
+	}
+
 	log.Debug().Dur("took", time.Since(start)).Msg("constraint system solver done")
 
 	// format the solution
@@ -199,6 +206,15 @@ func (cs *system) GetCoefficient(i int) (r constraint.Element) {
 	return
 }
 
+// Hash returns a digest of the structure of the constraint system, see [constraint.System.Digest]
+func (cs *system) Hash() []byte {
+	coefficients := make([]constraint.Element, len(cs.Coefficients))
+	for i := range coefficients {
+		coefficients[i] = cs.GetCoefficient(i)
+	}
+	return cs.System.Digest(coefficients)
+}
+
 // GetSparseR1Cs return the list of SparseR1C
 func (cs *system) GetSparseR1Cs() []constraint.SparseR1C {
 
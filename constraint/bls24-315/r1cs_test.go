@@ -81,6 +81,7 @@ func TestSerialization(t *testing.T) {
 						"CoeffTable.mCoeffs",
 						"System.lbWireLevel",
 						"System.genericHint",
+						"System.regions",
 						"System.SymbolTable",
 						"System.bitLen")); diff != "" {
 					t.Fatalf("round trip mismatch (-want +got):\n%s", diff)
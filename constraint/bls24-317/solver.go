@@ -50,6 +50,9 @@ type solver struct {
 	// used to out api.Println
 	logger zerolog.Logger
 
+	// check the width annotations once solved, see csolver.WithWidthChecks
+	checkWidths bool
+
 	a, b, c fr.Vector // R1CS solver will compute the a,b,c matrices
 
 	q *big.Int
@@ -96,6 +99,7 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 		solved:          make([]bool, nbWires),
 		mHintsFunctions: hintFunctions,
 		logger:          opt.Logger,
+		checkWidths:     opt.CheckWidths,
 		q:               cs.Field(),
 	}
 
@@ -245,6 +249,28 @@ func (s *solver) solveWithHint(h *constraint.HintMapping) error {
 	return err
 }
 
+// checkWidthAnnotations returns an error if a value annotated with a bit width
+// does not fit in it, see csolver.WithWidthChecks.
+func (s *solver) checkWidthAnnotations(annotations []constraint.WidthAnnotation) error {
+	var v big.Int
+	for _, a := range annotations {
+		var eval fr.Element
+		for _, t := range a.Value {
+			if t.IsConstant() {
+				eval.Add(&eval, &s.Coefficients[t.CoeffID()])
+				continue
+			}
+			tv := s.computeTerm(t)
+			eval.Add(&eval, &tv)
+		}
+		eval.BigInt(&v)
+		if v.BitLen() > a.Bits {
+			return fmt.Errorf("value %s at %s overflows the declared width of %d bits", v.String(), a.Caller, a.Bits)
+		}
+	}
+	return nil
+}
+
 func (s *solver) printLogs(logs []constraint.LogEntry) {
 	if s.logger.GetLevel() == zerolog.Disabled {
 		return
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/blang/semver/v4"
@@ -126,7 +127,21 @@ type System struct {
 	CommitmentInfo Commitments
 	GkrInfo        GkrInfo
 
+	// Metadata contains user defined key/values, serialized with the system
+	Metadata map[string]string
+
+	// Assumptions lists the range assumptions added without constraints, see
+	// [Assumption]
+	Assumptions []Assumption
+
+	// WidthAnnotations lists the expected bit widths of values, checked by the
+	// solver on demand, see [WidthAnnotation]
+	WidthAnnotations []WidthAnnotation
+
 	genericHint BlueprintID
+
+	// regions is the stack of the named profiling regions, see EnterRegion
+	regions []string
 }
 
 // NewSystem initialize the common structure among constraint system
@@ -344,15 +359,21 @@ func (system *System) VariableToString(vID int) string {
 }
 
 func (cs *System) AddR1C(c R1C, bID BlueprintID) int {
-	profile.RecordConstraint()
+	profile.RecordConstraintInRegion(cs.region())
 
 	blueprint := cs.Blueprints[bID]
 
 	// get a []uint32 from a pool
 	calldata := getBuffer()
 
-	// compress the R1C into a []uint32 and add the instruction
-	blueprint.(BlueprintR1C).CompressR1C(&c, calldata)
+	// compress the R1C into a []uint32 and add the instruction. The blueprint
+	// takes a pointer, the constraint is copied in a pooled one instead of
+	// escaping to the heap.
+	pc := r1cPool.Get().(*R1C)
+	*pc = c
+	blueprint.(BlueprintR1C).CompressR1C(pc, calldata)
+	*pc = R1C{}
+	r1cPool.Put(pc)
 	cs.AddInstruction(bID, *calldata)
 
 	// release the []uint32 to the pool
@@ -362,15 +383,19 @@ func (cs *System) AddR1C(c R1C, bID BlueprintID) int {
 }
 
 func (cs *System) AddSparseR1C(c SparseR1C, bID BlueprintID) int {
-	profile.RecordConstraint()
+	profile.RecordConstraintInRegion(cs.region())
 
 	blueprint := cs.Blueprints[bID]
 
 	// get a []uint32 from a pool
 	calldata := getBuffer()
 
-	// compress the SparceR1C into a []uint32 and add the instruction
-	blueprint.(BlueprintSparseR1C).CompressSparseR1C(&c, calldata)
+	// compress the SparceR1C into a []uint32 and add the instruction, see
+	// AddR1C for the pooled constraint
+	pc := sparseR1CPool.Get().(*SparseR1C)
+	*pc = c
+	blueprint.(BlueprintSparseR1C).CompressSparseR1C(pc, calldata)
+	sparseR1CPool.Put(pc)
 
 	cs.AddInstruction(bID, *calldata)
 
@@ -439,6 +464,89 @@ func (cs *System) GetSparseR1CIterator() SparseR1CIterator {
 	return SparseR1CIterator{cs: cs}
 }
 
+// SetMeta sets the metadata value for the key. The metadata is serialized
+// with the constraint system, for example to store the name of the circuit or
+// the build it was compiled with.
+func (cs *System) SetMeta(key, value string) {
+	if cs.Metadata == nil {
+		cs.Metadata = make(map[string]string)
+	}
+	cs.Metadata[key] = value
+}
+
+// Meta returns the metadata value for the key and true if the key is set.
+func (cs *System) Meta(key string) (string, bool) {
+	v, ok := cs.Metadata[key]
+	return v, ok
+}
+
+// Assumption records that a variable is assumed to have a bounded bit-length
+// without being constrained to. Assumptions are unsound if they do not hold
+// by construction of the circuit; they are kept in the constraint system so
+// that they can be audited.
+type Assumption struct {
+	// Variable is the string representation of the assumed variable
+	Variable string
+	// Bits is the assumed bit-length of the variable
+	Bits int
+	// Caller is the file.go:line where the assumption was made
+	Caller string
+}
+
+// AddAssumption records the assumption a. It does not add any constraint.
+func (cs *System) AddAssumption(a Assumption) {
+	cs.Assumptions = append(cs.Assumptions, a)
+}
+
+// GetAssumptions returns the recorded range assumptions.
+func (cs *System) GetAssumptions() []Assumption {
+	return cs.Assumptions
+}
+
+// WidthAnnotation records that a value is expected to fit in a number of
+// bits. Unlike an [Assumption], it does not claim anything about the circuit:
+// it adds no constraint and no wire, and it is only checked by the solver when
+// solving with [solver.WithWidthChecks], to detect values which wrapped around
+// the field modulus.
+type WidthAnnotation struct {
+	// Value is the annotated value
+	Value LinearExpression
+	// Bits is the expected bit width of the value
+	Bits int
+	// Caller is the file.go:line where the annotation was made
+	Caller string
+}
+
+// AddWidthAnnotation records the width annotation a. It does not add any
+// constraint nor wire.
+func (cs *System) AddWidthAnnotation(a WidthAnnotation) {
+	cs.WidthAnnotations = append(cs.WidthAnnotations, a)
+}
+
+// EnterRegion opens a named profiling region: until the matching LeaveRegion
+// call, the constraints added to the system are attributed to this region in
+// the active profiling sessions. Regions may be nested.
+func (cs *System) EnterRegion(name string) {
+	cs.regions = append(cs.regions, name)
+}
+
+// LeaveRegion closes the region opened by the last call to EnterRegion.
+func (cs *System) LeaveRegion() {
+	if len(cs.regions) == 0 {
+		panic("LeaveRegion called without matching EnterRegion")
+	}
+	cs.regions = cs.regions[:len(cs.regions)-1]
+}
+
+// region returns the full path of the innermost open region, "" if none or if
+// no profiling session would record it.
+func (cs *System) region() string {
+	if len(cs.regions) == 0 || !profile.Active() {
+		return ""
+	}
+	return strings.Join(cs.regions, "/")
+}
+
 func (cs *System) GetCommitments() Commitments {
 	return cs.CommitmentInfo
 }
@@ -452,6 +560,13 @@ var bufPool = sync.Pool{
 	},
 }
 
+// r1cPool and sparseR1CPool hold the constraints passed to the blueprints by
+// AddR1C and AddSparseR1C while they are compressed.
+var (
+	r1cPool       = sync.Pool{New: func() interface{} { return new(R1C) }}
+	sparseR1CPool = sync.Pool{New: func() interface{} { return new(SparseR1C) }}
+)
+
 // getBuffer returns a buffer of at least the given size.
 // The buffer is taken from the pool if it is large enough,
 // otherwise a new buffer is allocated.
@@ -0,0 +1,154 @@
+package constraint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"sort"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Digest returns a SHA-256 digest of the structure of the constraint system,
+// given its coefficient table. It is used by the curve specific constraint
+// systems to implement [ConstraintSystem.Hash].
+//
+// The digest covers the system type, the scalar field, the number of public,
+// secret and internal wires, the blueprints, the instructions with their call
+// data, the coefficients, the commitments and the GKR info. The input names,
+// the debug info, the logs, the metadata, the assumptions and the solver levels
+// are not part of the structure: two systems which differ only by these hash
+// identically.
+//
+// The digest does not depend on the order of the operands: the R1C and sparse
+// R1C constraints are hashed with the values of their coefficients, the terms
+// of their linear expressions sorted by wire, and the operands of their
+// products (L and R, or the xa and xb terms) in a canonical order. The
+// coefficient table is sorted. The call data of the other instructions, for
+// example the hints, is hashed as is. The order of the constraints and of the
+// internal wires is part of the structure.
+func (system *System) Digest(coefficients []Element) []byte {
+	h := sha256.New()
+	writeUint64(h, uint64(system.Type))
+	writeString(h, system.Field().String())
+	writeUint64(h, uint64(len(system.Public)))
+	writeUint64(h, uint64(len(system.Secret)))
+	writeUint64(h, uint64(system.NbInternalVariables))
+	writeUint64(h, uint64(system.NbConstraints))
+
+	writeUint64(h, uint64(len(system.Blueprints)))
+	for _, b := range system.Blueprints {
+		// the encoding skips the state cached by the blueprints when solving
+		writeString(h, fmt.Sprintf("%T", b))
+		writeCBOR(h, b)
+	}
+
+	writeUint64(h, uint64(len(system.Instructions)))
+	for _, pi := range system.Instructions {
+		inst := pi.Unpack(system)
+		writeUint64(h, uint64(pi.BlueprintID))
+		writeUint64(h, uint64(inst.ConstraintOffset))
+		writeUint64(h, uint64(inst.WireOffset))
+		switch b := system.Blueprints[pi.BlueprintID].(type) {
+		case BlueprintR1C:
+			var c R1C
+			b.DecompressR1C(&c, inst)
+			writeR1C(h, &c, coefficients)
+		case BlueprintSparseR1C:
+			var c SparseR1C
+			b.DecompressSparseR1C(&c, inst)
+			writeSparseR1C(h, &c, coefficients)
+		default:
+			writeUint64(h, uint64(len(inst.Calldata)))
+			for _, c := range inst.Calldata {
+				writeUint64(h, uint64(c))
+			}
+		}
+	}
+
+	sorted := make([][]byte, len(coefficients))
+	for i, c := range coefficients {
+		b := c.Bytes()
+		sorted[i] = b[:]
+	}
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i], sorted[j]) < 0 })
+	writeUint64(h, uint64(len(sorted)))
+	for _, b := range sorted {
+		h.Write(b)
+	}
+
+	writeString(h, fmt.Sprintf("%T", system.CommitmentInfo))
+	writeCBOR(h, system.CommitmentInfo)
+	writeCBOR(h, system.GkrInfo)
+
+	return h.Sum(nil)
+}
+
+// writeR1C writes c with L and R in canonical order.
+func writeR1C(h hash.Hash, c *R1C, coefficients []Element) {
+	l, r := encodeLinearExpression(c.L, coefficients), encodeLinearExpression(c.R, coefficients)
+	if bytes.Compare(l, r) > 0 {
+		l, r = r, l
+	}
+	writeString(h, string(l))
+	writeString(h, string(r))
+	writeString(h, string(encodeLinearExpression(c.O, coefficients)))
+}
+
+// writeSparseR1C writes c with the xa and xb terms in canonical order.
+func writeSparseR1C(h hash.Hash, c *SparseR1C, coefficients []Element) {
+	a, b := encodeTerm(Term{VID: c.XA, CID: c.QL}, coefficients), encodeTerm(Term{VID: c.XB, CID: c.QR}, coefficients)
+	if bytes.Compare(a, b) > 0 {
+		a, b = b, a
+	}
+	writeString(h, string(a))
+	writeString(h, string(b))
+	writeString(h, string(encodeTerm(Term{VID: c.XC, CID: c.QO}, coefficients)))
+	for _, cid := range []uint32{c.QM, c.QC} {
+		v := coefficients[cid].Bytes()
+		h.Write(v[:])
+	}
+	writeUint64(h, uint64(c.Commitment))
+}
+
+// encodeLinearExpression returns the encoding of the terms of l sorted by wire.
+func encodeLinearExpression(l LinearExpression, coefficients []Element) []byte {
+	terms := make([][]byte, len(l))
+	for i, t := range l {
+		terms[i] = encodeTerm(t, coefficients)
+	}
+	sort.Slice(terms, func(i, j int) bool { return bytes.Compare(terms[i], terms[j]) < 0 })
+	return bytes.Join(terms, nil)
+}
+
+// encodeTerm returns the wire of t followed by the value of its coefficient.
+func encodeTerm(t Term, coefficients []Element) []byte {
+	v := coefficients[t.CID].Bytes()
+	b := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(v)), t.VID)
+	return append(b, v[:]...)
+}
+
+// writeCBOR writes the CBOR encoding of v. The values are serialized with the
+// constraint system, so the encoding can not fail.
+func writeCBOR(h hash.Hash, v any) {
+	b, err := cbor.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("encode %T: %v", v, err))
+	}
+	writeString(h, string(b))
+}
+
+func writeUint64(h hash.Hash, v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	h.Write(buf[:])
+}
+
+// writeString writes the length prefixed string s, so that consecutive strings
+// can't be confused.
+func writeString(h hash.Hash, s string) {
+	writeUint64(h, uint64(len(s)))
+	h.Write([]byte(s))
+}
@@ -0,0 +1,112 @@
+package constraint_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+type hashCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+func (c *hashCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(api.Mul(c.X, c.X), c.Y, 3), c.Z)
+	return nil
+}
+
+// renamedCircuit is hashCircuit with other names and debug output.
+type renamedCircuit struct {
+	A, B frontend.Variable
+	C    frontend.Variable `gnark:",public"`
+}
+
+func (c *renamedCircuit) Define(api frontend.API) error {
+	api.Println("a", c.A)
+	api.AssertIsEqual(api.Add(api.Mul(c.A, c.A), c.B, 3), c.C)
+	return nil
+}
+
+// modifiedCircuit is hashCircuit with another constant.
+type modifiedCircuit hashCircuit
+
+func (c *modifiedCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(api.Mul(c.X, c.X), c.Y, 4), c.Z)
+	return nil
+}
+
+// productCircuit has a product of two inputs and a linear combination with
+// more terms.
+type productCircuit hashCircuit
+
+func (c *productCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(api.Mul(c.X, c.Y), api.Mul(2, c.Y), 3), c.Z)
+	api.AssertIsEqual(api.Mul(api.Add(c.X, api.Mul(5, c.Y)), api.Add(c.Z, 7)), 11)
+	return nil
+}
+
+// permutedCircuit is productCircuit with the operands in another order.
+type permutedCircuit hashCircuit
+
+func (c *permutedCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(3, api.Mul(c.Y, 2), api.Mul(c.Y, c.X)), c.Z)
+	api.AssertIsEqual(api.Mul(api.Add(api.Mul(c.Y, 5), c.X), api.Add(7, c.Z)), 11)
+	return nil
+}
+
+func TestHash(t *testing.T) {
+	for _, tc := range []struct {
+		builder frontend.NewBuilder
+		newCS   func(ecc.ID) constraint.ConstraintSystem
+	}{
+		{r1cs.NewBuilder, groth16.NewCS},
+		{scs.NewBuilder, plonk.NewCS},
+	} {
+		compile := func(field *big.Int, circuit frontend.Circuit) constraint.ConstraintSystem {
+			ccs, err := frontend.Compile(field, tc.builder, circuit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return ccs
+		}
+		ccs := compile(ecc.BN254.ScalarField(), &hashCircuit{})
+		expected := ccs.Hash()
+
+		if !bytes.Equal(compile(ecc.BN254.ScalarField(), &hashCircuit{}).Hash(), expected) {
+			t.Fatal("compiling twice the same circuit gives different hashes")
+		}
+		if !bytes.Equal(compile(ecc.BN254.ScalarField(), &renamedCircuit{}).Hash(), expected) {
+			t.Fatal("renaming the inputs changes the hash")
+		}
+		if bytes.Equal(compile(ecc.BN254.ScalarField(), &modifiedCircuit{}).Hash(), expected) {
+			t.Fatal("modified circuit has the same hash")
+		}
+		if !bytes.Equal(compile(ecc.BN254.ScalarField(), &permutedCircuit{}).Hash(), compile(ecc.BN254.ScalarField(), &productCircuit{}).Hash()) {
+			t.Fatal("permuting the terms changes the hash")
+		}
+		if bytes.Equal(compile(ecc.BLS12_381.ScalarField(), &hashCircuit{}).Hash(), expected) {
+			t.Fatal("circuits over different fields have the same hash")
+		}
+
+		var buf bytes.Buffer
+		if _, err := ccs.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		decoded := tc.newCS(ecc.BN254)
+		if _, err := decoded.ReadFrom(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded.Hash(), expected) {
+			t.Fatal("serialization changes the hash")
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+type iteratorCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+func (c *iteratorCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(api.Add(x3, c.X, 5), c.Y)
+	api.AssertIsDifferent(c.X, 0)
+	api.AssertIsEqual(api.Div(c.Y, c.X), c.Z)
+	return nil
+}
+
+func TestAllR1Cs(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &iteratorCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r1cs := ccs.(constraint.R1CS)
+	var nbConstraints, nbTerms int
+	r1cs.AllR1Cs()(func(c constraint.R1C) bool {
+		nbConstraints++
+		for _, l := range []constraint.LinearExpression{c.L, c.R, c.O} {
+			for _, t := range l {
+				_ = r1cs.GetCoefficient(int(t.CoeffID()))
+				nbTerms++
+			}
+		}
+		return true
+	})
+	if nbConstraints != ccs.GetNbConstraints() {
+		t.Fatalf("iterated over %d constraints, expected %d", nbConstraints, ccs.GetNbConstraints())
+	}
+	if nbTerms == 0 {
+		t.Fatal("no terms")
+	}
+
+	// early stop
+	nbConstraints = 0
+	r1cs.AllR1Cs()(func(constraint.R1C) bool {
+		nbConstraints++
+		return false
+	})
+	if nbConstraints != 1 {
+		t.Fatalf("iteration did not stop, got %d constraints", nbConstraints)
+	}
+}
+
+func TestAllSparseR1Cs(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &iteratorCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scs := ccs.(constraint.SparseR1CS)
+	nbConstraints := 0
+	scs.AllSparseR1Cs()(func(c constraint.SparseR1C) bool {
+		nbConstraints++
+		return true
+	})
+	if nbConstraints != ccs.GetNbConstraints() {
+		t.Fatalf("iterated over %d constraints, expected %d", nbConstraints, ccs.GetNbConstraints())
+	}
+}
@@ -0,0 +1,41 @@
+package constraint_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+func TestMetaSerialization(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &iteratorCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ccs.Meta("name"); ok {
+		t.Fatal("unexpected metadata")
+	}
+	ccs.SetMeta("name", "iterator")
+	ccs.SetMeta("build", "0123abcd")
+	ccs.SetMeta("name", "iteratorCircuit")
+
+	var buf bytes.Buffer
+	if _, err := ccs.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded := plonk.NewCS(ecc.BN254)
+	if _, err := decoded.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for key, expected := range map[string]string{"name": "iteratorCircuit", "build": "0123abcd"} {
+		if v, ok := decoded.Meta(key); !ok || v != expected {
+			t.Fatalf("metadata %s: expected %q, got %q", key, expected, v)
+		}
+	}
+	if _, ok := decoded.Meta("author"); ok {
+		t.Fatal("unexpected metadata")
+	}
+}
@@ -28,6 +28,14 @@ type R1CS interface {
 
 	// GetR1CIterator returns an R1CIterator to iterate on the R1C constraints of the system.
 	GetR1CIterator() R1CIterator
+
+	// AllR1Cs returns a read-only iterator over the R1C constraints of the system.
+	AllR1Cs() func(yield func(R1C) bool)
+
+	// MulConstraintCount returns the number of multiplicative R1C in the system, that is
+	// constraints L⋅R == O where neither L nor R is a constant. The remaining
+	// GetNbConstraints() - MulConstraintCount() constraints are linear.
+	MulConstraintCount() int
 }
 
 // R1CIterator facilitates iterating through R1C constraints.
@@ -53,6 +61,49 @@ func (it *R1CIterator) Next() *R1C {
 	return it.Next()
 }
 
+// AllR1Cs returns an iterator over the R1C constraints of the system, in
+// order. The iterator calls yield for each constraint and stops when yield
+// returns false. The coefficients of the terms of the linear expressions are
+// given by GetCoefficient(t.CoeffID()).
+//
+// The yielded R1C shares memory with the iterator, the caller must not modify
+// or retain it and should copy it if needed. The iterator has the signature of
+// iter.Seq[R1C] and can be used in range loops from Go 1.23.
+func (cs *System) AllR1Cs() func(yield func(R1C) bool) {
+	return func(yield func(R1C) bool) {
+		it := cs.GetR1CIterator()
+		for r1c := it.Next(); r1c != nil; r1c = it.Next() {
+			if !yield(*r1c) {
+				return
+			}
+		}
+	}
+}
+
+// MulConstraintCount returns the number of multiplicative R1C in the system, that is
+// constraints L⋅R == O where neither L nor R is a constant.
+//
+// In a R1CS, the constant wire is the public wire of index 0; a linear expression only
+// referencing that wire is a constant, and the constraint is then linear.
+func (cs *System) MulConstraintCount() int {
+	isConstant := func(l LinearExpression) bool {
+		for _, t := range l {
+			if t.WireID() != 0 && !t.IsConstant() {
+				return false
+			}
+		}
+		return true
+	}
+	cpt := 0
+	it := cs.GetR1CIterator()
+	for r1c := it.Next(); r1c != nil; r1c = it.Next() {
+		if !isConstant(r1c.L) && !isConstant(r1c.R) {
+			cpt++
+		}
+	}
+	return cpt
+}
+
 // // IsValid perform post compilation checks on the Variables
 // //
 // // 1. checks that all user inputs are referenced in at least one constraint
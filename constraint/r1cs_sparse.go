@@ -27,6 +27,9 @@ type SparseR1CS interface {
 
 	// GetSparseR1CIterator returns an SparseR1CIterator to iterate on the SparseR1C constraints of the system.
 	GetSparseR1CIterator() SparseR1CIterator
+
+	// AllSparseR1Cs returns a read-only iterator over the SparseR1C constraints of the system.
+	AllSparseR1Cs() func(yield func(SparseR1C) bool)
 }
 
 // SparseR1CIterator facilitates iterating through SparseR1C constraints.
@@ -52,6 +55,23 @@ func (it *SparseR1CIterator) Next() *SparseR1C {
 	return it.Next()
 }
 
+// AllSparseR1Cs returns an iterator over the SparseR1C constraints of the
+// system, in order. The iterator calls yield for each constraint and stops when
+// yield returns false. The coefficients are given by GetCoefficient(c.QL) etc.
+//
+// The iterator has the signature of iter.Seq[SparseR1C] and can be used in
+// range loops from Go 1.23.
+func (cs *System) AllSparseR1Cs() func(yield func(SparseR1C) bool) {
+	return func(yield func(SparseR1C) bool) {
+		it := cs.GetSparseR1CIterator()
+		for c := it.Next(); c != nil; c = it.Next() {
+			if !yield(*c) {
+				return
+			}
+		}
+	}
+}
+
 // func (system *SparseR1CSCore) CheckUnconstrainedWires() error {
 // 	// TODO @gbotrel add unit test for that.
 // 	return nil
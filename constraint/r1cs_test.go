@@ -2,6 +2,7 @@ package constraint_test
 
 import (
 	"fmt"
+	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/constraint"
@@ -103,3 +104,20 @@ func (circuit *cubic) Define(api frontend.API) error {
 	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
 	return nil
 }
+
+func TestMulConstraintCount(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubic{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r1cs := ccs.(constraint.R1CS)
+
+	// X * X * X --> 2 multiplications
+	// Y == X³ + X + 5 --> 1 linear constraint
+	if got := r1cs.MulConstraintCount(); got != 2 {
+		t.Fatalf("expected 2 multiplication constraints, got %d", got)
+	}
+	if got := r1cs.GetNbConstraints() - r1cs.MulConstraintCount(); got != 1 {
+		t.Fatalf("expected 1 linear constraint, got %d", got)
+	}
+}
@@ -14,6 +14,7 @@ type Option func(*Config) error
 type Config struct {
 	HintFunctions map[HintID]Hint // defaults to all built-in hint functions
 	Logger        zerolog.Logger  // defaults to gnark.Logger
+	CheckWidths   bool            // defaults to false, see WithWidthChecks
 }
 
 // WithHints is a solver option that specifies additional hint functions to be used
@@ -43,6 +44,17 @@ func OverrideHint(id HintID, f Hint) Option {
 	}
 }
 
+// WithWidthChecks is a solver option that enables the verification of the
+// width annotations of the circuit (see frontend.AnnotateWidth). The solver
+// fails if any annotated value does not fit in its declared bit width, which
+// helps to detect unexpected field wrap-arounds before proving.
+func WithWidthChecks() Option {
+	return func(opt *Config) error {
+		opt.CheckWidths = true
+		return nil
+	}
+}
+
 // WithLogger is a prover option that specifies zerolog.Logger as a destination for the
 // logs printed by api.Println(). By default, uses gnark/logger.
 // zerolog.Nop() will disable logging
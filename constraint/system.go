@@ -75,6 +75,34 @@ type ConstraintSystem interface {
 	// This is experimental.
 	CheckUnconstrainedWires() error
 
+	// AddAssumption records a range assumption, without adding constraints.
+	AddAssumption(a Assumption)
+	// GetAssumptions returns the recorded range assumptions.
+	GetAssumptions() []Assumption
+
+	// AddWidthAnnotation records a width annotation, without adding
+	// constraints nor wires.
+	AddWidthAnnotation(a WidthAnnotation)
+
+	// EnterRegion opens a named profiling region, closed by LeaveRegion.
+	EnterRegion(name string)
+	// LeaveRegion closes the last opened profiling region.
+	LeaveRegion()
+
+	// SetMeta sets a metadata key/value, serialized with the constraint system.
+	SetMeta(key, value string)
+	// Meta returns the metadata value for the key and true if the key is set.
+	Meta(key string) (string, bool)
+
+	// WitnessTemplate writes a JSON object with the names of the public and
+	// secret inputs and empty placeholders for their values.
+	WitnessTemplate(w io.Writer) error
+
+	// Hash returns a digest of the structure of the constraint system:
+	// compiling twice the same circuit gives the same hash, even with
+	// different input names or debug info. See [System.Digest].
+	Hash() []byte
+
 	GetInstruction(int) Instruction
 
 	GetCoefficient(i int) Element
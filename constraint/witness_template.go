@@ -0,0 +1,63 @@
+package constraint
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// WitnessTemplate writes to w a JSON object listing the names of the public
+// and secret inputs of the constraint system, in the order of the witness,
+// with empty string placeholders for their values:
+//
+//	{
+//		"public": {"Y": ""},
+//		"secret": {"X": ""}
+//	}
+//
+// The names are the full names of the circuit fields, with nested fields
+// joined by underscores. The constant wire of R1CS is omitted.
+func (system *System) WitnessTemplate(w io.Writer) error {
+	public := system.Public
+	if system.Type == SystemR1CS && len(public) > 0 {
+		// first public wire is the constant one wire.
+		public = public[1:]
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`{"public":`)
+	if err := writeTemplateObject(&buf, public); err != nil {
+		return err
+	}
+	buf.WriteString(`,"secret":`)
+	if err := writeTemplateObject(&buf, system.Secret); err != nil {
+		return err
+	}
+	buf.WriteString(`}`)
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "\t"); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := out.WriteTo(w)
+	return err
+}
+
+// writeTemplateObject writes a JSON object with the given keys, in order, and
+// empty string values. We don't use a map to keep the order of the witness.
+func writeTemplateObject(buf *bytes.Buffer, names []string) error {
+	buf.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteString(`:""`)
+	}
+	buf.WriteByte('}')
+	return nil
+}
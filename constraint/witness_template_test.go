@@ -0,0 +1,50 @@
+package constraint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+type templateCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+	Z struct {
+		W [2]frontend.Variable `gnark:",public"`
+	}
+}
+
+func (c *templateCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	api.AssertIsEqual(api.Add(c.Z.W[0], c.Z.W[1]), c.X)
+	return nil
+}
+
+func TestWitnessTemplate(t *testing.T) {
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &templateCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := ccs.WitnessTemplate(&buf); err != nil {
+			t.Fatal(err)
+		}
+		expected := "{\n\t\"public\": {\n\t\t\"Y\": \"\",\n\t\t\"Z_W_0\": \"\",\n\t\t\"Z_W_1\": \"\"\n\t},\n\t\"secret\": {\n\t\t\"X\": \"\"\n\t}\n}\n"
+		if buf.String() != expected {
+			t.Fatalf("unexpected template:\n%s", buf.String())
+		}
+		var template map[string]map[string]string
+		if err := json.Unmarshal(buf.Bytes(), &template); err != nil {
+			t.Fatal(err)
+		}
+		if len(template["public"]) != 3 || len(template["secret"]) != 1 {
+			t.Fatalf("unexpected number of inputs in %v", template)
+		}
+	}
+}
@@ -80,3 +80,22 @@ func writeStack(sbb *strings.Builder, forceClean ...bool) {
 		}
 	}
 }
+
+// Caller returns the location, as file:line, of the first caller outside of
+// gnark, that is the code of the circuit calling the API or a gadget. The
+// test files of gnark are considered outside of gnark. It returns "unknown
+// location" if there is no such caller.
+func Caller() string {
+	pc := make([]uintptr, 64)
+	n := runtime.Callers(2, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" && (!strings.HasPrefix(frame.Function, "github.com/consensys/gnark/") || strings.HasSuffix(frame.File, "_test.go")) {
+			return filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return "unknown location"
+		}
+	}
+}
@@ -89,7 +89,8 @@ type API interface {
 	// ---------------------------------------------------------------------------------------------
 	// Conditionals
 
-	// Select if b is true, yields i1 else yields i2
+	// Select if b is true, yields i1 else yields i2. It asserts that b is a
+	// boolean, which adds no constraint if b is already known to be one.
 	Select(b Variable, i1, i2 Variable) Variable
 
 	// Lookup2 performs a 2-bit lookup between i1, i2, i3, i4 based on bits b0
@@ -97,7 +98,8 @@ type API interface {
 	// and i3 if b0=b1=1.
 	Lookup2(b0, b1 Variable, i0, i1, i2, i3 Variable) Variable
 
-	// IsZero returns 1 if a is zero, 0 otherwise
+	// IsZero returns 1 if a is zero, 0 otherwise. The result is marked to be a
+	// boolean, so that asserting it is one adds no constraint.
 	IsZero(i1 Variable) Variable
 
 	// Cmp returns:
@@ -138,6 +140,10 @@ type API interface {
 	// Compiler returns the compiler object for advanced circuit development
 	Compiler() Compiler
 
+	// GnarkAPI marks the API, so that the circuit fields holding it are
+	// skipped when parsing the circuit, see [schema.CircuitAPI].
+	GnarkAPI()
+
 	// Deprecated APIs
 
 	// NewHint is a shortcut to api.Compiler().NewHint()
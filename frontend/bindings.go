@@ -0,0 +1,172 @@
+package frontend
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// GenerateBindings writes to w the Go source of package pkg with typed helpers
+// to assign the inputs of the circuit and to prove and verify with Groth16.
+//
+// The generated Assignment type has a setter per input, taking a *big.Int and
+// named after the full name of the input: SetX for X, SetA_B_0 ... for the
+// elements of a slice, so
+// that a renamed or removed input is a compile time error in the downstream
+// code instead of a witness error at runtime. The generated Witness,
+// PublicWitness, Prove and Verify methods build the witness in the order of
+// the compiler, without walking a circuit structure.
+//
+// The circuit must be a pointer, with the sizes of its slices set as for
+// [Compile]. It returns an error if two inputs map to the same setter name.
+func GenerateBindings(circuit interface{}, pkg string, w io.Writer) error {
+	if !token.IsIdentifier(pkg) {
+		return fmt.Errorf("invalid package name %q", pkg)
+	}
+	leaves, err := ParseCircuit(circuit)
+	if err != nil {
+		return err
+	}
+
+	// the compiler allocates the public inputs first
+	var public, secret []bindingInput
+	names := make(map[string]string)
+	for _, l := range leaves {
+		in := bindingInput{Name: l.Name, Ident: bindingIdent(l.Name), Visibility: l.Visibility.String()}
+		if other, ok := names[in.Ident]; ok {
+			return fmt.Errorf("inputs %s and %s have the same setter name Set%s", other, l.Name, in.Ident)
+		}
+		names[in.Ident] = l.Name
+		if l.Visibility == schema.Public {
+			public = append(public, in)
+		} else {
+			secret = append(secret, in)
+		}
+	}
+	inputs := append(public, secret...)
+	for i := range inputs {
+		inputs[i].Index = i
+	}
+
+	var buf bytes.Buffer
+	if err := tmplBindings.Execute(&buf, struct {
+		Package            string
+		Inputs             []bindingInput
+		NbPublic, NbSecret int
+	}{pkg, inputs, len(public), len(secret)}); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format bindings: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+type bindingInput struct {
+	Name       string
+	Ident      string
+	Visibility string
+	Index      int
+}
+
+// bindingIdent returns the exported Go identifier for the input name, the
+// characters which are not letters nor digits are replaced by _.
+func bindingIdent(name string) string {
+	ident := []rune(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name))
+	if len(ident) > 0 {
+		ident[0] = unicode.ToUpper(ident[0])
+	}
+	return string(ident)
+}
+
+var tmplBindings = template.Must(template.New("bindings").Parse(`// Code generated by gnark frontend.GenerateBindings. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// Assignment is the assignment of the inputs of the circuit.
+type Assignment struct {
+	values [{{len .Inputs}}]*big.Int
+}
+{{range .Inputs}}
+// Set{{.Ident}} sets the {{.Visibility}} input {{.Name}}.
+func (a *Assignment) Set{{.Ident}}(v *big.Int) *Assignment {
+	a.values[{{.Index}}] = v
+	return a
+}
+{{end}}
+var names = [{{len .Inputs}}]string{ {{- range .Inputs}}{{printf "%q" .Name}}, {{end -}} }
+
+// Witness returns the full witness of the assignment over the given field.
+func (a *Assignment) Witness(field *big.Int) (witness.Witness, error) {
+	return a.fill(field, {{.NbPublic}}, {{.NbSecret}})
+}
+
+// PublicWitness returns the public witness of the assignment over the given
+// field.
+func (a *Assignment) PublicWitness(field *big.Int) (witness.Witness, error) {
+	return a.fill(field, {{.NbPublic}}, 0)
+}
+
+// Prove returns a Groth16 proof of the assignment.
+func (a *Assignment) Prove(ccs constraint.ConstraintSystem, pk groth16.ProvingKey) (groth16.Proof, error) {
+	w, err := a.Witness(ccs.Field())
+	if err != nil {
+		return nil, err
+	}
+	return groth16.Prove(ccs, pk, w)
+}
+
+// Verify verifies the Groth16 proof against the public inputs of the
+// assignment.
+func (a *Assignment) Verify(proof groth16.Proof, vk groth16.VerifyingKey) error {
+	w, err := a.PublicWitness(vk.CurveID().ScalarField())
+	if err != nil {
+		return err
+	}
+	return groth16.Verify(proof, vk, w)
+}
+
+func (a *Assignment) fill(field *big.Int, nbPublic, nbSecret int) (witness.Witness, error) {
+	for i := 0; i < nbPublic+nbSecret; i++ {
+		if a.values[i] == nil {
+			return nil, errors.New("missing assignment for " + names[i])
+		}
+	}
+	w, err := witness.New(field)
+	if err != nil {
+		return nil, err
+	}
+	values := make(chan any, nbPublic+nbSecret)
+	for i := 0; i < nbPublic+nbSecret; i++ {
+		values <- a.values[i]
+	}
+	close(values)
+	if err := w.Fill(nbPublic, nbSecret, values); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+`))
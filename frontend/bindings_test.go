@@ -0,0 +1,145 @@
+package frontend_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// bindingsCircuit is also declared in the source of bindingsCircuitSrc, which
+// is compiled with the generated bindings.
+type bindingsCircuit struct {
+	X  frontend.Variable
+	Y  frontend.Variable `gnark:",public"`
+	Zs [2]frontend.Variable
+}
+
+func (c *bindingsCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(api.Mul(c.X, c.Zs[0]), c.Zs[1]), c.Y)
+	return nil
+}
+
+const bindingsCircuitSrc = `package bindings
+
+import "github.com/consensys/gnark/frontend"
+
+type Circuit struct {
+	X  frontend.Variable
+	Y  frontend.Variable ` + "`gnark:\",public\"`" + `
+	Zs [2]frontend.Variable
+}
+
+func (c *Circuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(api.Mul(c.X, c.Zs[0]), c.Zs[1]), c.Y)
+	return nil
+}
+`
+
+const bindingsUsageSrc = `package bindings
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+func TestBindings(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &Circuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var a Assignment
+	// 3*4 + 5 == 17
+	a.SetX(big.NewInt(3)).SetY(big.NewInt(17)).SetZs_0(big.NewInt(4)).SetZs_1(big.NewInt(5))
+	proof, err := a.Prove(ccs, pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Verify(proof, vk); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SetY(big.NewInt(18)).Verify(proof, vk); err == nil {
+		t.Fatal("expected verification error")
+	}
+	if _, err := new(Assignment).SetY(big.NewInt(1)).Witness(ccs.Field()); err == nil || err.Error() != "missing assignment for X" {
+		t.Fatalf("expected missing assignment error, got %v", err)
+	}
+}
+`
+
+func TestGenerateBindings(t *testing.T) {
+	var buf bytes.Buffer
+	if err := frontend.GenerateBindings(&bindingsCircuit{}, "bindings", &buf); err != nil {
+		t.Fatal(err)
+	}
+	src := buf.String()
+	for _, setter := range []string{"SetX", "SetY", "SetZs_0", "SetZs_1"} {
+		if !strings.Contains(src, "func (a *Assignment) "+setter+"(") {
+			t.Fatalf("missing setter %s", setter)
+		}
+	}
+
+	if err := frontend.GenerateBindings(bindingsCircuit{}, "bindings", &buf); err == nil {
+		t.Fatal("expected error for a circuit passed by value")
+	}
+	if err := frontend.GenerateBindings(&bindingsCircuit{}, "not a package", &buf); err == nil {
+		t.Fatal("expected error for an invalid package name")
+	}
+
+	// compile the bindings, and prove and verify with them
+	if testing.Short() {
+		t.Skip("skipping the compilation of the bindings in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	// the bindings are built in their own module, which imports gnark from
+	// this tree with its dependencies from the module cache
+	root, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	goSum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goMod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the requirements of gnark, with gnark replaced by this tree
+	requires := goMod[bytes.Index(goMod, []byte("\nrequire")):]
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module bindings\n\ngo 1.20\n\nrequire github.com/consensys/gnark v0.0.0\n\nreplace github.com/consensys/gnark => " + root + "\n" + string(requires),
+		"go.sum":           string(goSum),
+		"bindings.go":      src,
+		"circuit.go":       bindingsCircuitSrc,
+		"bindings_test.go": bindingsUsageSrc,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command(goBin, "test", "-mod=mod", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOPROXY=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+}
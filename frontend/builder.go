@@ -22,6 +22,21 @@ type Compiler interface {
 	// IsBoolean returns true if given variable was marked as boolean in the compiler (see MarkBoolean)
 	// Use with care; variable may not have been **constrained** to be boolean
 	// This returns true if the v is a constant and v == 0 || v == 1.
+	//
+	// Variables constrained with api.AssertIsBoolean (and the bits returned by
+	// api.ToBinary) are marked as boolean, such that gadgets can use this method
+	// to avoid adding redundant boolean constraints.
+	//
+	// The mark is held by the compiler, not by the variable: it is keyed by the
+	// wires and coefficients of v. Copies of v (Go assignments, slices or
+	// struct fields holding it) are reported as boolean too, as is any
+	// variable with the same wires and coefficients. A variable derived from v
+	// by the API is not, even if it is boolean by construction (for example
+	// api.Sub(1, v)); only the results of api.Xor, api.Or, api.And and
+	// api.IsZero are marked. api.AssertIsEqual marks the side not known to be
+	// boolean when the other one is. The mark is only as trustworthy as the
+	// constraint that justified the call to MarkBoolean. The test engine has
+	// no marks and reports whether the value of v is 0 or 1.
 	IsBoolean(v Variable) bool
 
 	// NewHint initializes internal variables whose value will be evaluated
@@ -65,6 +80,10 @@ type Compiler interface {
 	ToCanonicalVariable(Variable) CanonicalVariable
 
 	SetGkrInfo(constraint.GkrInfo) error
+
+	// GnarkAPI marks the compiler, so that the circuit fields holding it are
+	// skipped when parsing the circuit, see [schema.CircuitAPI].
+	GnarkAPI()
 }
 
 // Builder represents a constraint system builder
@@ -100,6 +119,51 @@ type Rangechecker interface {
 	Check(v Variable, bits int)
 }
 
+// Assumer allows to assume that the variables have a bounded bit-length
+// without constraining them, when the bound holds by construction of the
+// circuit and a range check is redundant. The assumptions are not checked by
+// the constraint system: a wrong assumption makes the circuit unsound. They
+// are recorded in the constraint system (see
+// [constraint.System.GetAssumptions]) and reported as warnings at compile
+// time, and they can be forbidden with the [ForbidAssumptions] compile option.
+//
+// Not all compilers implement this interface, it is accessed as
+//
+//	if a, ok := api.Compiler().(frontend.Assumer); ok {
+//		err = a.AssumeInRange(v, 64)
+//	}
+type Assumer interface {
+	// AssumeInRange records that v has bit-length at most bits. It returns an
+	// error if assumptions are forbidden or if bits is not positive.
+	AssumeInRange(v Variable, bits int) error
+}
+
+// Regioner allows to attribute the constraints to named regions in the
+// profiles, see [github.com/consensys/gnark/profile.Profile.Regions]. The
+// regions are held by the builder of the circuit, so that circuits can be
+// compiled concurrently. Regions may be nested.
+//
+// Not all compilers implement this interface, it is accessed as
+//
+//	if r, ok := api.Compiler().(frontend.Regioner); ok {
+//		r.EnterRegion("merkle")
+//		defer r.LeaveRegion()
+//	}
+type Regioner interface {
+	// EnterRegion opens the region name, until the matching LeaveRegion.
+	EnterRegion(name string)
+	// LeaveRegion closes the region opened by the last call to EnterRegion.
+	LeaveRegion()
+}
+
+// WidthAnnotator allows to record the expected bit width of a value, see
+// [AnnotateWidth].
+type WidthAnnotator interface {
+	// AnnotateWidth records that v is expected to fit in bits bits, without
+	// adding constraints nor wires.
+	AnnotateWidth(v Variable, bits int)
+}
+
 // CanonicalVariable represents a variable that's encoded in a constraint system specific way.
 // For example a R1CS builder may represent this as a constraint.LinearExpression,
 // a PLONK builder --> constraint.Term
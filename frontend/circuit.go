@@ -34,6 +34,10 @@ package frontend
 //
 // See the documentation for [schema.TagOpt] for how to use tags to define the
 // behaviour of the compiler and schema parser.
+//
+// A circuit may keep a reference to the [API] (or [Compiler]) in a field, for
+// example to use it in helper methods. Such fields, including pointers to and
+// embedded APIs, are skipped by the compiler and schema parser.
 type Circuit interface {
 	// Define declares the circuit's Constraints
 	Define(api API) error
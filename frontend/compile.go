@@ -9,6 +9,7 @@ import (
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/circuitcommit"
 	"github.com/consensys/gnark/internal/circuitdefer"
 	"github.com/consensys/gnark/logger"
 )
@@ -54,7 +55,7 @@ func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...Com
 
 	// parse the circuit builds a schema of the circuit
 	// and call circuit.Define() method to initialize a list of constraints in the compiler
-	if err = parseCircuit(builder, circuit); err != nil {
+	if err = parseCircuit(builder, circuit, opt); err != nil {
 		log.Err(err).Msg("parsing circuit")
 		return nil, fmt.Errorf("parse circuit: %w", err)
 
@@ -64,13 +65,20 @@ func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...Com
 	return builder.Compile()
 }
 
-func parseCircuit(builder Builder, circuit Circuit) (err error) {
+func parseCircuit(builder Builder, circuit Circuit, opt CompileConfig) (err error) {
 	// ensure circuit.Define has pointer receiver
 	if reflect.ValueOf(circuit).Kind() != reflect.Ptr {
 		return errors.New("frontend.Circuit methods must be defined on pointer receiver")
 	}
 
-	s, err := schema.Walk(circuit, tVariable, nil)
+	walkOpts := []schema.Option{schema.WithNameStrategy(opt.NameStrategy)}
+	if opt.StrictTags {
+		walkOpts = append(walkOpts, schema.WithStrictTags())
+	}
+	if opt.StrictUnexported {
+		walkOpts = append(walkOpts, schema.WithStrictUnexported())
+	}
+	s, err := schema.Walk(circuit, tVariable, nil, walkOpts...)
 	if err != nil {
 		return err
 	}
@@ -78,37 +86,39 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 	log := logger.Logger()
 	log.Info().Int("nbSecret", s.Secret).Int("nbPublic", s.Public).Msg("parsed circuit inputs")
 
+	// the inputs tagged with range and boolean options, constrained before
+	// calling Define
+	var tagged []taggedInput
+	// set if some inputs are tagged with commit, in which case Define must
+	// assert their commitment
+	var committed bool
+
 	// leaf handlers are called when encoutering leafs in the circuit data struct
 	// leafs are Constraints that need to be initialized in the context of compiling a circuit
-	variableAdder := func(targetVisibility schema.Visibility) func(f schema.LeafInfo, tInput reflect.Value) error {
-		return func(f schema.LeafInfo, tInput reflect.Value) error {
-			if tInput.CanSet() {
-				if f.Visibility == schema.Unset {
-					return errors.New("can't set val " + f.FullName() + " visibility is unset")
+	variableAdder := func(targetVisibility schema.Visibility) func(f schema.LeafInfo, v *Variable) error {
+		return func(f schema.LeafInfo, v *Variable) error {
+			if f.Visibility == targetVisibility {
+				if f.Visibility == schema.Public {
+					*v = builder.PublicVariable(f)
+				} else if f.Visibility == schema.Secret {
+					*v = builder.SecretVariable(f)
 				}
-				if f.Visibility == targetVisibility {
-					if f.Visibility == schema.Public {
-						tInput.Set(reflect.ValueOf(builder.PublicVariable(f)))
-					} else if f.Visibility == schema.Secret {
-						tInput.Set(reflect.ValueOf(builder.SecretVariable(f)))
-					}
+				if f.Range > 0 || f.Boolean {
+					tagged = append(tagged, taggedInput{f, *v})
 				}
-
-				return nil
+				committed = committed || f.Commit
 			}
-			return errors.New("can't set val " + f.FullName())
+			return nil
 		}
 	}
 
 	// add public inputs first to compute correct offsets
-	_, err = schema.Walk(circuit, tVariable, variableAdder(schema.Public))
-	if err != nil {
+	if err = walk(circuit, variableAdder(schema.Public), walkOpts...); err != nil {
 		return err
 	}
 
 	// add secret inputs
-	_, err = schema.Walk(circuit, tVariable, variableAdder(schema.Secret))
-	if err != nil {
+	if err = walk(circuit, variableAdder(schema.Secret), walkOpts...); err != nil {
 		return err
 	}
 
@@ -119,6 +129,8 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 		}
 	}()
 
+	assertTagged(builder, tagged)
+
 	// call Define() to fill in the Constraints
 	if err = circuit.Define(builder); err != nil {
 		return fmt.Errorf("define circuit: %w", err)
@@ -126,10 +138,34 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 	if err = callDeferred(builder); err != nil {
 		return fmt.Errorf("deferred: %w", err)
 	}
+	if committed && !circuitcommit.Asserted(builder) {
+		return fmt.Errorf("inputs tagged with %q but their commitment is not asserted in Define (see inputcommit.AssertCommitment)", schema.TagOptCommit)
+	}
 
 	return
 }
 
+// taggedInput is an input tagged with [schema.TagOptRange] or
+// [schema.TagOptBoolean].
+type taggedInput struct {
+	leaf schema.LeafInfo
+	v    Variable
+}
+
+// assertTagged emits the constraints of the tagged inputs: a range check with
+// a binary decomposition for [schema.TagOptRange], and a booleanity constraint
+// for [schema.TagOptBoolean].
+func assertTagged(api API, tagged []taggedInput) {
+	for _, t := range tagged {
+		if t.leaf.Boolean {
+			api.AssertIsBoolean(t.v)
+		}
+		if t.leaf.Range > 0 {
+			api.ToBinary(t.v, t.leaf.Range)
+		}
+	}
+}
+
 func callDeferred(builder Builder) error {
 	for i := 0; i < len(circuitdefer.GetAll[func(API) error](builder)); i++ {
 		if err := circuitdefer.GetAll[func(API) error](builder)[i](builder); err != nil {
@@ -154,6 +190,11 @@ type CompileConfig struct {
 	Capacity                  int
 	IgnoreUnconstrainedInputs bool
 	CompressThreshold         int
+	ForbidAssumptions         bool
+	StrictTags                bool
+	StrictUnexported          bool
+	NoHints                   bool
+	NameStrategy              schema.NameStrategy
 }
 
 // WithCapacity is a compile option that specifies the estimated capacity needed
@@ -180,6 +221,66 @@ func IgnoreUnconstrainedInputs() CompileOption {
 	}
 }
 
+// ForbidAssumptions is a compile option which makes the compiler return an
+// error when the circuit assumes that a variable is in range without
+// constraining it (see [Assumer]). It should be set for the circuits where
+// soundness must not depend on assumptions.
+func ForbidAssumptions() CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.ForbidAssumptions = true
+		return nil
+	}
+}
+
+// NoHints is a compile option which makes the compiler return an error when
+// the circuit uses a hint (see [Compiler.NewHint]), so that all the values of
+// the witness are computed from the inputs by the constraints. It is a safety
+// posture for the deployments which forbid non-deterministic witness values.
+//
+// Many methods of the API and many gadgets use hints, for example
+// [API.IsZero], [API.ToBinary] or [API.Cmp], and they can not be used with
+// this option. The methods of the API panic on the error, which is returned
+// by [Compile].
+func NoHints() CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.NoHints = true
+		return nil
+	}
+}
+
+// WithStrictTags is a compile option which makes the compiler return an error
+// for the unknown options of the gnark struct tags of the circuit, for example
+// a misspelled "publik" which would otherwise silently leave the input secret.
+// See [schema.WithStrictTags].
+func WithStrictTags() CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.StrictTags = true
+		return nil
+	}
+}
+
+// WithStrictUnexported is a compile option which makes the compiler return an
+// error for the unexported fields of the circuit holding variables, which are
+// otherwise skipped with a warning. See [schema.WithStrictUnexported].
+func WithStrictUnexported() CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.StrictUnexported = true
+		return nil
+	}
+}
+
+// WithNameStrategy is a compile option which sets the strategy building the
+// full names of the inputs of the circuit, as recorded in the constraint
+// system. By default, the [schema.DefaultNameStrategy] is used. The same
+// strategy must be given to the functions naming the inputs of the circuit,
+// for example [ParseCircuit] or [NewSchema]. See [schema.WithNameStrategy].
+func WithNameStrategy(s schema.NameStrategy) CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.NameStrategy = s
+		return nil
+	}
+}
+
 // WithCompressThreshold is a compile option which enforces automatic variable
 // compression if the length of the linear expression in the variable exceeds
 // given threshold.
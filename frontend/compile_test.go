@@ -0,0 +1,927 @@
+package frontend_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/logger"
+	"github.com/consensys/gnark/test"
+	"github.com/rs/zerolog"
+)
+
+type apiHolder struct {
+	API frontend.API
+	Y   frontend.Variable
+}
+
+type apiCircuit struct {
+	frontend.API
+	Compiler *frontend.Compiler
+	Holders  []apiHolder
+	X        frontend.Variable `gnark:",public"`
+}
+
+func (c *apiCircuit) Define(api frontend.API) error {
+	c.API = api
+	compiler := api.Compiler()
+	c.Compiler = &compiler
+	for i := range c.Holders {
+		c.Holders[i].API = api
+		c.AssertIsEqual(c.Holders[i].Y, c.X)
+	}
+	return nil
+}
+
+func TestCircuitWithAPI(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	circuit := &apiCircuit{Holders: make([]apiHolder, 2)}
+	// compile twice, the second time the API fields are set
+	for i := 0; i < 2; i++ {
+		ccs, err := frontend.Compile(field, r1cs.NewBuilder, circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ccs.GetNbPublicVariables() != 2 || ccs.GetNbSecretVariables() != 2 {
+			t.Fatalf("unexpected number of inputs %d public and %d secret", ccs.GetNbPublicVariables(), ccs.GetNbSecretVariables())
+		}
+	}
+
+	circuit.X = 3
+	circuit.Holders[0].Y = 3
+	circuit.Holders[1].Y = 3
+	w, err := frontend.NewWitness(circuit, field)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Vector().(interface{ Len() int }).Len() != 3 {
+		t.Fatal("unexpected witness length")
+	}
+	if _, err := frontend.NewSchema(circuit); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type account struct {
+	Balance frontend.Variable
+	Keys    [2]frontend.Variable `gnark:"keys"`
+}
+
+type namedCircuit struct {
+	Root     frontend.Variable `gnark:",public"`
+	Accounts []account
+}
+
+func (c *namedCircuit) Define(api frontend.API) error {
+	for i := range c.Accounts {
+		api.AssertIsEqual(api.Add(c.Accounts[i].Balance, c.Accounts[i].Keys[0], c.Accounts[i].Keys[1]), c.Root)
+	}
+	return nil
+}
+
+func TestNameStrategy(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &namedCircuit{Accounts: make([]account, 2)}, frontend.WithNameStrategy(schema.Separator(".")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	system := ccs.(*cs_bn254.R1CS)
+	if expected := []string{"1", "Root"}; !reflect.DeepEqual(system.Public, expected) {
+		t.Fatalf("expected public names %v, got %v", expected, system.Public)
+	}
+	expected := []string{
+		"Accounts.0.Balance", "Accounts.0.keys.0", "Accounts.0.keys.1",
+		"Accounts.1.Balance", "Accounts.1.keys.0", "Accounts.1.keys.1",
+	}
+	if !reflect.DeepEqual(system.Secret, expected) {
+		t.Fatalf("expected secret names %v, got %v", expected, system.Secret)
+	}
+	// the inputs are named the same way when given the same strategy
+	leaves, err := frontend.ParseCircuit(&namedCircuit{Accounts: make([]account, 2)}, schema.WithNameStrategy(schema.Separator(".")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaves[1].Name != expected[0] {
+		t.Fatalf("expected %s, got %s", expected[0], leaves[1].Name)
+	}
+
+	// the strategy only applies to its compilation
+	ccs, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &namedCircuit{Accounts: make([]account, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret := ccs.(*cs_bn254.R1CS).Secret; secret[1] != "Accounts_0_keys_0" {
+		t.Fatalf("unexpected default name %s", secret[1])
+	}
+}
+
+type mapCircuit struct {
+	Total    frontend.Variable `gnark:",public"`
+	Balances map[string]frontend.Variable
+	Accounts map[int]account
+}
+
+func (c *mapCircuit) Define(api frontend.API) error {
+	sum := frontend.Variable(0)
+	for _, b := range c.Balances {
+		sum = api.Add(sum, b)
+	}
+	for _, a := range c.Accounts {
+		sum = api.Add(sum, a.Balance)
+	}
+	api.AssertIsEqual(sum, c.Total)
+	return nil
+}
+
+func TestCompileMap(t *testing.T) {
+	circuit := mapCircuit{
+		Balances: map[string]frontend.Variable{"bob": nil, "alice": nil},
+		Accounts: map[int]account{3: {}},
+	}
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"Balances_alice", "Balances_bob", "Accounts_3_Balance", "Accounts_3_keys_0", "Accounts_3_keys_1"}
+	if secret := ccs.(*cs_bn254.R1CS).Secret; !reflect.DeepEqual(secret, expected) {
+		t.Fatalf("expected secret names %v, got %v", expected, secret)
+	}
+
+	assignment := mapCircuit{
+		Total:    10,
+		Balances: map[string]frontend.Variable{"bob": 2, "alice": 3},
+		Accounts: map[int]account{3: {Balance: 5, Keys: [2]frontend.Variable{0, 0}}},
+	}
+	w, err := frontend.NewWitness(&assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ccs.IsSolved(w); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type assumptionCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *assumptionCircuit) Define(api frontend.API) error {
+	a, ok := api.Compiler().(frontend.Assumer)
+	if !ok {
+		return fmt.Errorf("compiler does not implement frontend.Assumer")
+	}
+	if err := a.AssumeInRange(c.X, 8); err != nil {
+		return err
+	}
+	api.AssertIsEqual(api.Mul(c.X, 2), c.Y)
+	return nil
+}
+
+func TestAssumeInRange(t *testing.T) {
+	previous := logger.Logger()
+	defer logger.Set(previous)
+
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		var logs bytes.Buffer
+		logger.Set(zerolog.New(&logs))
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &assumptionCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assumptions := ccs.GetAssumptions()
+		if len(assumptions) != 1 || assumptions[0].Bits != 8 || !strings.HasPrefix(assumptions[0].Caller, "compile_test.go:") || assumptions[0].Variable == "" {
+			t.Fatalf("unexpected assumptions %+v", assumptions)
+		}
+		if !strings.Contains(logs.String(), "range assumed without constraint") {
+			t.Fatalf("assumption not reported: %s", logs.String())
+		}
+
+		if _, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &assumptionCircuit{}, frontend.ForbidAssumptions()); err == nil {
+			t.Fatal("assumption allowed in strict mode")
+		}
+	}
+
+	// the test engine checks the assumptions
+	if err := test.IsSolved(&assumptionCircuit{}, &assumptionCircuit{X: 255, Y: 510}, ecc.BN254.ScalarField()); err != nil {
+		t.Fatal(err)
+	}
+	if err := test.IsSolved(&assumptionCircuit{}, &assumptionCircuit{X: 256, Y: 512}, ecc.BN254.ScalarField()); err == nil {
+		t.Fatal("wrong assumption accepted by the test engine")
+	}
+}
+
+type booleanMarkCircuit struct {
+	B frontend.Variable
+}
+
+func (c *booleanMarkCircuit) Define(api frontend.API) error {
+	api.AssertIsBoolean(c.B)
+	copied := []frontend.Variable{c.B}
+	if !api.Compiler().IsBoolean(copied[0]) {
+		return fmt.Errorf("copy of a boolean variable is not marked")
+	}
+	notB := api.Sub(1, c.B)
+	if api.Compiler().IsBoolean(notB) {
+		return fmt.Errorf("derived variable is marked")
+	}
+	api.Compiler().MarkBoolean(notB)
+	if !api.Compiler().IsBoolean(notB) {
+		return fmt.Errorf("MarkBoolean has no effect")
+	}
+	return nil
+}
+
+func TestBooleanMark(t *testing.T) {
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		if _, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &booleanMarkCircuit{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+type isBooleanCircuit struct {
+	A, B, C frontend.Variable
+}
+
+func (c *isBooleanCircuit) Define(api frontend.API) error {
+	api.AssertIsBoolean(c.A)
+	bits := api.ToBinary(c.C, 8)
+	if !api.Compiler().IsBoolean(c.A) {
+		return errors.New("expected A to be boolean after AssertIsBoolean")
+	}
+	if api.Compiler().IsBoolean(c.B) {
+		return errors.New("expected B not to be boolean")
+	}
+	for i := range bits {
+		if !api.Compiler().IsBoolean(bits[i]) {
+			return fmt.Errorf("expected bit %d to be boolean after ToBinary", i)
+		}
+	}
+	api.AssertIsEqual(c.B, api.Add(c.A, c.C))
+	return nil
+}
+
+func TestIsBoolean(t *testing.T) {
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		if _, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &isBooleanCircuit{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+type redundantBooleanCircuit struct {
+	redundant bool
+	A, B      frontend.Variable
+}
+
+func (c *redundantBooleanCircuit) Define(api frontend.API) error {
+	api.AssertIsBoolean(c.A)
+	// B is equal to a boolean, it is a boolean
+	api.AssertIsEqual(c.B, c.A)
+	if c.redundant {
+		api.AssertIsBoolean(c.A)
+		api.AssertIsBoolean(c.B)
+	}
+	return nil
+}
+
+func TestRedundantBoolean(t *testing.T) {
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		expected, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &redundantBooleanCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &redundantBooleanCircuit{redundant: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ccs.GetNbConstraints() != expected.GetNbConstraints() {
+			t.Fatalf("expected %d constraints, got %d", expected.GetNbConstraints(), ccs.GetNbConstraints())
+		}
+	}
+}
+
+type selectCircuit struct {
+	marked     bool
+	C, A, B, Y frontend.Variable
+}
+
+func (c *selectCircuit) Define(api frontend.API) error {
+	if c.marked {
+		api.AssertIsBoolean(c.C)
+	}
+	api.AssertIsEqual(api.Select(c.C, c.A, c.B), c.Y)
+	return nil
+}
+
+func TestSelect(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&selectCircuit{},
+		test.WithValidAssignment(&selectCircuit{C: 1, A: 3, B: 5, Y: 3}),
+		test.WithValidAssignment(&selectCircuit{C: 0, A: 3, B: 5, Y: 5}),
+		test.WithInvalidAssignment(&selectCircuit{C: 1, A: 3, B: 5, Y: 5}),
+		// the condition must be a boolean
+		test.WithInvalidAssignment(&selectCircuit{C: 2, A: 4, B: 5, Y: 3}),
+		test.WithCurves(ecc.BN254))
+
+	// a condition known to be boolean is not constrained again
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		expected, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &selectCircuit{})
+		assert.NoError(err)
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &selectCircuit{marked: true})
+		assert.NoError(err)
+		assert.Equal(expected.GetNbConstraints(), ccs.GetNbConstraints())
+	}
+}
+
+type isZeroCircuit struct {
+	constant bool
+	X, Y     frontend.Variable
+}
+
+func (c *isZeroCircuit) Define(api frontend.API) error {
+	if c.constant {
+		for x, expected := range map[int]uint64{0: 1, 5: 0} {
+			if v, ok := api.Compiler().ConstantValue(api.IsZero(x)); !ok || v.Uint64() != expected {
+				return fmt.Errorf("IsZero(%d) is not the constant %d", x, expected)
+			}
+		}
+		return nil
+	}
+	z := api.IsZero(c.X)
+	if !api.Compiler().IsBoolean(z) {
+		return errors.New("the result of IsZero is not marked boolean")
+	}
+	api.AssertIsEqual(z, c.Y)
+	return nil
+}
+
+func TestIsZero(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&isZeroCircuit{},
+		test.WithValidAssignment(&isZeroCircuit{X: 0, Y: 1}),
+		test.WithValidAssignment(&isZeroCircuit{X: 7, Y: 0}),
+		test.WithInvalidAssignment(&isZeroCircuit{X: 0, Y: 0}),
+		test.WithInvalidAssignment(&isZeroCircuit{X: 7, Y: 1}),
+		test.WithCurves(ecc.BN254))
+
+	// a constant yields a constant, without constraint
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &isZeroCircuit{constant: true})
+		assert.NoError(err)
+		assert.Equal(0, ccs.GetNbConstraints())
+	}
+}
+
+type parseCircuit struct {
+	A struct {
+		B [2]frontend.Variable
+		C []frontend.Variable `gnark:"c,public"`
+	}
+	D frontend.Variable `gnark:",public"`
+}
+
+func (c *parseCircuit) Define(frontend.API) error { return nil }
+
+func TestParseCircuit(t *testing.T) {
+	circuit := parseCircuit{}
+	circuit.A.C = make([]frontend.Variable, 2)
+	leaves, err := frontend.ParseCircuit(&circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		name       string
+		visibility schema.Visibility
+		value      *frontend.Variable
+	}{
+		{"A_B_0", schema.Secret, &circuit.A.B[0]},
+		{"A_B_1", schema.Secret, &circuit.A.B[1]},
+		{"A_c_0", schema.Public, &circuit.A.C[0]},
+		{"A_c_1", schema.Public, &circuit.A.C[1]},
+		{"D", schema.Public, &circuit.D},
+	}
+	if len(leaves) != len(expected) {
+		t.Fatalf("expected %d leaves, got %d", len(expected), len(leaves))
+	}
+	for i, e := range expected {
+		if leaves[i].Name != e.name || leaves[i].Visibility != e.visibility || leaves[i].Value != e.value {
+			t.Fatalf("leaf %d: expected %s %s, got %s %s", i, e.name, e.visibility, leaves[i].Name, leaves[i].Visibility)
+		}
+	}
+
+	if _, err := frontend.ParseCircuit(circuit); err == nil {
+		t.Fatal("expected an error for a non-pointer circuit")
+	}
+	if _, err := frontend.ParseCircuit(&struct {
+		X frontend.Variable `gnark:",bits=0"`
+	}{}); err == nil {
+		t.Fatal("expected an error for an invalid tag")
+	}
+}
+
+func TestWalk(t *testing.T) {
+	circuit := schemaCircuit{
+		Accounts: make([]schemaAccount, 1),
+		Fees:     map[string]frontend.Variable{"b": nil, "a": nil},
+	}
+	var names []string
+	err := frontend.Walk(&circuit, func(visibility schema.Visibility, name string, v *frontend.Variable) error {
+		names = append(names, visibility.String()+" "+name)
+		*v = name
+		if name == "Accounts_0_Balance" {
+			return errors.New("handler error")
+		}
+		return nil
+	})
+	// the errors of the handler do not stop the walk
+	if err == nil || err.Error() != "handler error" {
+		t.Fatalf("expected the error of the handler, got %v", err)
+	}
+	expected := []string{
+		"secret Balance", "public Keys_0", "public Keys_1",
+		"secret Accounts_0_Balance", "public Accounts_0_Keys_0", "public Accounts_0_Keys_1",
+		"public Fees_a", "public Fees_b", "public root",
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	// the variables are set, including the elements of the maps
+	if circuit.Accounts[0].Keys[1] != "Accounts_0_Keys_1" || circuit.Fees["b"] != "Fees_b" || circuit.Root != "root" {
+		t.Fatal("the variables set by the handler are not written back")
+	}
+
+	if err := frontend.Walk(circuit, func(schema.Visibility, string, *frontend.Variable) error { return nil }); err == nil {
+		t.Fatal("expected an error for a non-pointer circuit")
+	}
+}
+
+func TestAssertAllocated(t *testing.T) {
+	circuit := parseCircuit{}
+	circuit.A.C = make([]frontend.Variable, 2)
+	if err := frontend.AssertAllocated(&circuit); err == nil || !strings.Contains(err.Error(), "A_B_0: input not allocated") {
+		t.Fatalf("expected the inputs of a circuit which is not compiled to be unallocated, got %v", err)
+	}
+
+	if _, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit, frontend.IgnoreUnconstrainedInputs()); err != nil {
+		t.Fatal(err)
+	}
+	if err := frontend.AssertAllocated(&circuit); err != nil {
+		t.Fatal(err)
+	}
+
+	// an input added after the compilation has no wire
+	circuit.A.C = append(circuit.A.C, nil)
+	if err := frontend.AssertAllocated(&circuit); err == nil || err.Error() != "A_c_2: input not allocated by the compiler" {
+		t.Fatalf("expected A_c_2 to be unallocated, got %v", err)
+	}
+
+	if err := frontend.AssertAllocated(circuit); err == nil {
+		t.Fatal("expected an error for a non-pointer circuit")
+	}
+}
+
+type schemaAccount struct {
+	Balance frontend.Variable
+	Keys    [2]frontend.Variable `gnark:",public"`
+}
+
+type schemaCircuit struct {
+	schemaAccount
+	Accounts []schemaAccount
+	Fees     map[string]frontend.Variable `gnark:",public"`
+	Root     frontend.Variable            `gnark:"root,public"`
+}
+
+func (c *schemaCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.Root, c.Balance)
+	return nil
+}
+
+func TestSchema(t *testing.T) {
+	newCircuit := func() *schemaCircuit {
+		return &schemaCircuit{
+			Accounts: make([]schemaAccount, 1),
+			Fees:     map[string]frontend.Variable{"b": nil, "a": nil},
+		}
+	}
+	s, err := frontend.Schema(newCircuit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &frontend.CircuitSchema{
+		Public: []frontend.SchemaInput{
+			{Name: "Keys_0", Index: 0},
+			{Name: "Keys_1", Index: 1},
+			{Name: "Accounts_0_Keys_0", Index: 2},
+			{Name: "Accounts_0_Keys_1", Index: 3},
+			{Name: "Fees_a", Index: 4},
+			{Name: "Fees_b", Index: 5},
+			{Name: "root", Index: 6},
+		},
+		Secret: []frontend.SchemaInput{
+			{Name: "Balance", Index: 7},
+			{Name: "Accounts_0_Balance", Index: 8},
+		},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Fatalf("unexpected schema %v", s)
+	}
+
+	// the names are the ones of the compiled constraint system
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, newCircuit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	system := ccs.(*cs_bn254.R1CS)
+	for _, in := range s.Public {
+		// the first public wire is the constant 1
+		if system.Public[in.Index+1] != in.Name {
+			t.Fatalf("public input %d: expected %s, got %s", in.Index, system.Public[in.Index+1], in.Name)
+		}
+	}
+	for _, in := range s.Secret {
+		if name := system.Secret[in.Index-len(s.Public)]; name != in.Name {
+			t.Fatalf("secret input %d: expected %s, got %s", in.Index, name, in.Name)
+		}
+	}
+
+	if _, err := frontend.Schema(schemaCircuit{}); err == nil {
+		t.Fatal("expected an error for a non-pointer circuit")
+	}
+}
+
+func TestInputCounts(t *testing.T) {
+	newCircuit := func() *schemaCircuit {
+		return &schemaCircuit{
+			Accounts: make([]schemaAccount, 2),
+			Fees:     map[string]frontend.Variable{"b": nil, "a": nil},
+		}
+	}
+	public, secret, err := frontend.InputCounts(newCircuit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the counts are the ones of the compiled constraint system, with the
+	// inputs of the embedded struct, the slice and the map
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, newCircuit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if public != 9 || secret != 3 {
+		t.Fatalf("expected 9 public and 3 secret inputs, got %d and %d", public, secret)
+	}
+	// the first public wire is the constant 1
+	if public != ccs.GetNbPublicVariables()-1 || secret != ccs.GetNbSecretVariables() {
+		t.Fatalf("the counts %d and %d do not match the constraint system", public, secret)
+	}
+
+	var names []string
+	if err := frontend.ForEachInput(newCircuit(), func(name string, v schema.Visibility) error {
+		if v == schema.Public {
+			names = append(names, name)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != public || names[0] != "Keys_0" || names[public-1] != "root" {
+		t.Fatalf("unexpected public inputs %v", names)
+	}
+
+	// ForEachInput stops on the first error
+	errStop := errors.New("stop")
+	calls := 0
+	if err := frontend.ForEachInput(newCircuit(), func(string, schema.Visibility) error {
+		calls++
+		return errStop
+	}); err != errStop || calls != 1 {
+		t.Fatalf("expected the error of the first call, got %v after %d calls", err, calls)
+	}
+
+	if _, _, err := frontend.InputCounts(schemaCircuit{}); err == nil {
+		t.Fatal("expected an error for a non-pointer circuit")
+	}
+}
+
+type publicInputsCircuit struct {
+	schemaAccount
+	Nested struct {
+		X frontend.Variable
+		Y frontend.Variable `gnark:",secret"`
+	} `gnark:",public"`
+	Z frontend.Variable
+	W frontend.Variable `gnark:"w,public"`
+}
+
+func (c *publicInputsCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.Z, c.Balance)
+	return nil
+}
+
+func TestPublicInputs(t *testing.T) {
+	circuit := &publicInputsCircuit{}
+	leaves, err := frontend.PublicInputs(circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the fields of the embedded struct come first, and X is public as its
+	// parent while Y is secret by its own tag
+	expected := []string{"Keys_0", "Keys_1", "Nested_X", "w"}
+	var names []string
+	for _, l := range leaves {
+		if l.Visibility != schema.Public {
+			t.Fatalf("%s: expected a public input, got %s", l.Name, l.Visibility)
+		}
+		names = append(names, l.Name)
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	if leaves[2].Value != &circuit.Nested.X {
+		t.Fatal("the public input does not point to the variable of the circuit")
+	}
+
+	// the order is the one of the compiled constraint system
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &publicInputsCircuit{}, frontend.IgnoreUnconstrainedInputs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first public wire is the constant 1
+	if public := ccs.(*cs_bn254.R1CS).Public[1:]; !reflect.DeepEqual(public, expected) {
+		t.Fatalf("expected the public wires %v, got %v", expected, public)
+	}
+
+	if _, err := frontend.PublicInputs(publicInputsCircuit{}); err == nil {
+		t.Fatal("expected an error for a non-pointer circuit")
+	}
+}
+
+func TestLayoutHash(t *testing.T) {
+	newCircuit := func() *schemaCircuit {
+		return &schemaCircuit{
+			Accounts: make([]schemaAccount, 1),
+			Fees:     map[string]frontend.Variable{"b": nil, "a": nil},
+		}
+	}
+	h, err := frontend.LayoutHash(newCircuit())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the values do not change the hash
+	assigned := newCircuit()
+	assigned.Balance, assigned.Root = 1, 2
+	if other, err := frontend.LayoutHash(assigned); err != nil || other != h {
+		t.Fatalf("expected the hash of the assignment to be the one of the circuit, got %x, %v", other, err)
+	}
+
+	// a structurally identical circuit has the same hash, a renamed field a
+	// different one
+	type renamed struct {
+		schemaAccount
+		Accounts []schemaAccount
+		Fees     map[string]frontend.Variable `gnark:",public"`
+		Root     frontend.Variable            `gnark:"newRoot,public"`
+	}
+	type identical struct {
+		schemaAccount
+		Accounts []schemaAccount
+		Fees     map[string]frontend.Variable `gnark:",public"`
+		Root     frontend.Variable            `gnark:"root,public"`
+	}
+	fees := map[string]frontend.Variable{"a": nil, "b": nil}
+	if other, err := frontend.LayoutHash(&identical{Accounts: make([]schemaAccount, 1), Fees: fees}); err != nil || other != h {
+		t.Fatalf("expected the hash of an identical circuit to be %x, got %x, %v", h, other, err)
+	}
+	if other, err := frontend.LayoutHash(&renamed{Accounts: make([]schemaAccount, 1), Fees: fees}); err != nil || other == h {
+		t.Fatalf("expected the hash of a renamed field to change, got %x, %v", other, err)
+	}
+
+	// as a change of visibility or of the length of a slice
+	changed := newCircuit()
+	changed.Accounts = append(changed.Accounts, schemaAccount{})
+	if other, err := frontend.LayoutHash(changed); err != nil || other == h {
+		t.Fatalf("expected the hash of a longer slice to change, got %x, %v", other, err)
+	}
+	type secretRoot struct {
+		schemaAccount
+		Accounts []schemaAccount
+		Fees     map[string]frontend.Variable `gnark:",public"`
+		Root     frontend.Variable            `gnark:"root,secret"`
+	}
+	if other, err := frontend.LayoutHash(&secretRoot{Accounts: make([]schemaAccount, 1), Fees: fees}); err != nil || other == h {
+		t.Fatalf("expected the hash of a secret field to change, got %x, %v", other, err)
+	}
+
+	// the encoding is fixed, the hash does not change across runs
+	h, err = frontend.LayoutHash(&parseCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(h[:]) != "2ee82513811a2395e29a51e38669fd697eebefb8d567f2629347b619d1249327" {
+		t.Fatalf("unexpected hash %x", h)
+	}
+
+	if _, err := frontend.LayoutHash(schemaCircuit{}); err == nil {
+		t.Fatal("expected an error for a non-pointer circuit")
+	}
+}
+
+type strictTagsCircuit struct {
+	Balance frontend.Variable `gnark:",publik"`
+	Amount  frontend.Variable `gnark:"amount,public"`
+}
+
+func (c *strictTagsCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.Balance, c.Amount)
+	return nil
+}
+
+func TestCompileStrictTags(t *testing.T) {
+	// by default, the misspelled option is ignored and Balance is secret
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &strictTagsCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ccs.GetNbSecretVariables() != 1 {
+		t.Fatalf("expected 1 secret variable, got %d", ccs.GetNbSecretVariables())
+	}
+
+	_, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &strictTagsCircuit{}, frontend.WithStrictTags())
+	if err == nil || !strings.Contains(err.Error(), `unknown gnark tag option "publik" on field Balance`) {
+		t.Fatalf("expected unknown option error, got %v", err)
+	}
+}
+
+type unexportedCircuit struct {
+	X frontend.Variable
+	y frontend.Variable
+}
+
+func (c *unexportedCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.X, 1)
+	return nil
+}
+
+func TestCompileStrictUnexported(t *testing.T) {
+	// by default, the unexported field is skipped
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &unexportedCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ccs.GetNbSecretVariables() != 1 {
+		t.Fatalf("expected 1 secret variable, got %d", ccs.GetNbSecretVariables())
+	}
+
+	_, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &unexportedCircuit{}, frontend.WithStrictUnexported())
+	if err == nil || !strings.Contains(err.Error(), "y: unexported field of type frontend.Variable") {
+		t.Fatalf("expected unexported field error, got %v", err)
+	}
+}
+
+type taggedFlags struct {
+	Flags [2]frontend.Variable
+}
+
+type taggedCircuit struct {
+	Amount frontend.Variable `gnark:"amount,secret,range=8"`
+	Flag   frontend.Variable `gnark:"flag,secret,boolean"`
+	More   []taggedFlags     `gnark:",public,boolean"`
+	Sum    frontend.Variable `gnark:",public"`
+}
+
+func (c *taggedCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(c.Amount, c.Flag, c.More[0].Flags[0], c.More[0].Flags[1]), c.Sum)
+	return nil
+}
+
+func TestCompileTaggedConstraints(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	newCircuit := func() *taggedCircuit { return &taggedCircuit{More: make([]taggedFlags, 1)} }
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, newCircuit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the sum, the 3 booleans and the decomposition of the amount in 8 bits,
+	// with their booleanity
+	if n := ccs.GetNbConstraints(); n != 1+3+1+8 {
+		t.Fatalf("expected the tagged constraints, got %d constraints", n)
+	}
+
+	for _, tc := range []struct {
+		assignment *taggedCircuit
+		valid      bool
+	}{
+		{&taggedCircuit{Amount: 255, Flag: 1, More: []taggedFlags{{[2]frontend.Variable{0, 1}}}, Sum: 257}, true},
+		{&taggedCircuit{Amount: 256, Flag: 0, More: []taggedFlags{{[2]frontend.Variable{0, 0}}}, Sum: 256}, false},
+		{&taggedCircuit{Amount: 1, Flag: 2, More: []taggedFlags{{[2]frontend.Variable{0, 0}}}, Sum: 3}, false},
+		// the boolean option is inherited by the elements
+		{&taggedCircuit{Amount: 1, Flag: 0, More: []taggedFlags{{[2]frontend.Variable{0, 2}}}, Sum: 3}, false},
+	} {
+		w, err := frontend.NewWitness(tc.assignment, field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ccs.IsSolved(w); (err == nil) != tc.valid {
+			t.Fatalf("%v: expected valid=%t, got %v", tc.assignment, tc.valid, err)
+		}
+		if err := test.IsSolved(newCircuit(), tc.assignment, field); (err == nil) != tc.valid {
+			t.Fatalf("%v: test engine: expected valid=%t, got %v", tc.assignment, tc.valid, err)
+		}
+	}
+
+	if _, err := frontend.Compile(field, r1cs.NewBuilder, &struct {
+		taggedCircuit
+		X frontend.Variable `gnark:",range=0"`
+	}{taggedCircuit: *newCircuit()}); err == nil || !strings.Contains(err.Error(), `invalid "range" option "0"`) {
+		t.Fatalf("expected an invalid option error, got %v", err)
+	}
+}
+
+type squareAsserter interface {
+	AssertSquare(api frontend.API)
+}
+
+type squareGadget struct {
+	X, Y frontend.Variable
+}
+
+func (g *squareGadget) AssertSquare(api frontend.API) {
+	api.AssertIsEqual(api.Mul(g.X, g.X), g.Y)
+}
+
+type gadgetCircuit struct {
+	Gadget squareAsserter
+	Unused squareAsserter
+}
+
+func (c *gadgetCircuit) Define(api frontend.API) error {
+	c.Gadget.AssertSquare(api)
+	return nil
+}
+
+func TestCompileInterfaceField(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, &gadgetCircuit{Gadget: &squareGadget{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := ccs.GetNbSecretVariables(); n != 2 {
+		t.Fatalf("expected the 2 inputs of the gadget, got %d", n)
+	}
+	for _, tc := range []struct {
+		x, y  int
+		valid bool
+	}{{3, 9, true}, {3, 8, false}} {
+		w, err := frontend.NewWitness(&gadgetCircuit{Gadget: &squareGadget{X: tc.x, Y: tc.y}}, field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ccs.IsSolved(w); (err == nil) != tc.valid {
+			t.Fatalf("%d^2 = %d: expected valid=%t, got %v", tc.x, tc.y, tc.valid, err)
+		}
+	}
+}
+
+type hintCircuit struct {
+	X, Y   frontend.Variable
+	noHint bool
+}
+
+func (c *hintCircuit) Define(api frontend.API) error {
+	if !c.noHint {
+		// uses a hint for the inverse of X
+		api.AssertIsEqual(api.IsZero(c.X), 0)
+	}
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestCompileNoHints(t *testing.T) {
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		if _, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &hintCircuit{}); err != nil {
+			t.Fatal(err)
+		}
+		_, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &hintCircuit{}, frontend.NoHints())
+		if err == nil || !strings.Contains(err.Error(), "hints are forbidden by the compile options") || !strings.Contains(err.Error(), "InvZeroHint") {
+			t.Fatalf("expected hint error, got %v", err)
+		}
+		// the location is the call to IsZero in Define, not in the builder
+		if !strings.Contains(err.Error(), "at compile_test.go:") {
+			t.Fatalf("expected the location in the circuit, got %v", err)
+		}
+		if _, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &hintCircuit{noHint: true}, frontend.NoHints()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
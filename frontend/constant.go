@@ -0,0 +1,44 @@
+package frontend
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// Constant returns a Variable holding the compile time constant value. Unlike
+// a Variable set to a plain integer, a constant set in a field of a circuit is
+// not an input of the circuit: it is skipped when parsing the circuit and does
+// not need a slot in the witness. The assignment must hold a constant in the
+// same field, so that the field is skipped when building the witness too.
+//
+// The accepted values are the ones of a Variable: the integer types, big.Int,
+// *big.Int, the field elements, []byte (big-endian) and strings encoding an
+// integer in base 10, or in base 16, 8 or 2 with a prefix. Constant panics on
+// any other type, or on a string which does not encode an integer.
+//
+// The constant is recognized as such by the API: for example, api.Mul(x,
+// Constant(3)) adds no constraint in a R1CS.
+func Constant(value interface{}) Variable {
+	c := new(constant)
+	c.v = utils.FromInterface(value)
+	return c
+}
+
+// constant is a Variable built by [Constant].
+type constant struct {
+	v big.Int
+}
+
+// GnarkConstant implements [schema.ConstantLeaf].
+func (c *constant) GnarkConstant() {}
+
+// ToBigIntRegular sets res to the value of the constant and returns it.
+func (c *constant) ToBigIntRegular(res *big.Int) *big.Int {
+	return res.Set(&c.v)
+}
+
+// String implements [fmt.Stringer].
+func (c *constant) String() string {
+	return c.v.String()
+}
@@ -0,0 +1,54 @@
+package frontend_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+)
+
+type constantCircuit struct {
+	X frontend.Variable
+	K frontend.Variable `gnark:",public"`
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *constantCircuit) Define(api frontend.API) error {
+	if _, ok := api.Compiler().ConstantValue(c.K); !ok {
+		return errors.New("K should be a constant")
+	}
+	api.AssertIsEqual(api.Mul(c.X, c.K), c.Y)
+	return nil
+}
+
+func TestConstant(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, &constantCircuit{K: frontend.Constant("0x3")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// K is not an input, and the multiplication by a constant is free
+	if nb := ccs.GetNbPublicVariables(); nb != 2 {
+		t.Fatalf("expected 2 public variables (one wire and Y), got %d", nb)
+	}
+	if nb := ccs.GetNbConstraints(); nb != 1 {
+		t.Fatalf("expected 1 constraint, got %d", nb)
+	}
+	w, err := frontend.NewWitness(&constantCircuit{X: 2, K: frontend.Constant(3), Y: 6}, field)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ccs.IsSolved(w); err != nil {
+		t.Fatal(err)
+	}
+
+	assert := test.NewAssert(t)
+	assert.Equal("12", frontend.Constant(big.NewInt(12)).(fmt.Stringer).String())
+	assert.Panics(func() { frontend.Constant(1.5) })
+	assert.Panics(func() { frontend.Constant("not a number") })
+}
@@ -0,0 +1,148 @@
+package frontend
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/consensys/gnark/frontend/internal/expr"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// DeepCopy returns a new instance of the circuit, with its own slices, arrays,
+// maps, pointed structs and values held by interfaces, so that assigning the
+// copy does not change the circuit. The pointers to the same value, including the cycles, point to the
+// same copy. The circuit must be a pointer, the copy is a pointer to a value of
+// the same type.
+//
+// The inputs of the circuit, as returned by [ParseCircuit], are reset in the
+// copy, unless they hold a wire of a compiled circuit which is kept: the copy
+// of a circuit after [Compile] refers to the same wires, the copy of an
+// assignment is a fresh assignment to fill. The other fields, the constants,
+// the fields tagged with "-" and the unexported fields, are copied as is.
+func DeepCopy(circuit interface{}) (interface{}, error) {
+	src := reflect.ValueOf(circuit)
+	if src.Kind() != reflect.Ptr || src.IsNil() {
+		return nil, errors.New("circuit must be a non-nil pointer")
+	}
+	dst := reflect.New(src.Elem().Type())
+	seen := map[copied]reflect.Value{{src.Pointer(), src.Type()}: dst}
+	deepCopy(dst.Elem(), src.Elem(), seen)
+
+	// the inputs in the maps are copies, they are reset by the walker
+	if err := UnassignAll(dst.Interface()); err != nil {
+		return nil, err
+	}
+	return dst.Interface(), nil
+}
+
+// UnassignAll resets the inputs of the circuit to nil, so that the circuit can
+// be assigned again, for example to build the witness of another proof. The
+// circuit must be a pointer. It walks the circuit as [ParseCircuit], through
+// the structs, pointers, slices, arrays and maps. The inputs holding a wire of
+// a compiled circuit are kept, as are the constants, the fields tagged with
+// "-" and the unexported fields.
+func UnassignAll(circuit interface{}) error {
+	if reflect.ValueOf(circuit).Kind() != reflect.Ptr {
+		return errors.New("circuit must be a pointer")
+	}
+	// the walker writes the elements of the maps back after visiting them
+	_, err := schema.Walk(circuit, tVariable, func(f schema.LeafInfo, tInput reflect.Value) error {
+		if !tInput.CanSet() {
+			return errors.New("can't set val " + f.FullName())
+		}
+		if !tInput.IsNil() && !IsCanonical(tInput.Interface()) {
+			tInput.Set(reflect.Zero(tVariable))
+		}
+		return nil
+	})
+	return err
+}
+
+// copied identifies a pointer already copied, to keep the pointers to the same
+// value shared in the copy and to stop on the cycles.
+type copied struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// deepCopy copies src in dst, which must be settable. seen holds the copies of
+// the pointers already copied.
+func deepCopy(dst, src reflect.Value, seen map[copied]reflect.Value) {
+	if src.Type() == tVariable {
+		if src.IsNil() {
+			return
+		}
+		// the linear expressions of the wires are slices, they must not be
+		// shared with the circuit
+		switch v := src.Interface().(type) {
+		case expr.LinearExpression:
+			dst.Set(reflect.ValueOf(v.Clone()))
+		case *expr.LinearExpression:
+			c := v.Clone()
+			dst.Set(reflect.ValueOf(&c))
+		case *expr.Term:
+			t := *v
+			dst.Set(reflect.ValueOf(&t))
+		default:
+			dst.Set(src)
+		}
+		return
+	}
+	switch src.Kind() {
+	case reflect.Struct:
+		// copy the unexported fields as is, and the exported ones deeply
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				deepCopy(dst.Field(i), src.Field(i), seen)
+			}
+		}
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		k := copied{src.Pointer(), src.Type()}
+		if p, ok := seen[k]; ok {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Elem().Type())
+		seen[k] = p
+		dst.Set(p)
+		deepCopy(p.Elem(), src.Elem(), seen)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i), seen)
+		}
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i), seen)
+		}
+	case reflect.Interface:
+		// the dynamic value, for example a gadget, must not be shared with
+		// the circuit
+		if src.IsNil() {
+			return
+		}
+		v := reflect.New(src.Elem().Type()).Elem()
+		deepCopy(v, src.Elem(), seen)
+		dst.Set(v)
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		iter := src.MapRange()
+		for iter.Next() {
+			v := reflect.New(src.Type().Elem()).Elem()
+			deepCopy(v, iter.Value(), seen)
+			dst.SetMapIndex(iter.Key(), v)
+		}
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,196 @@
+package frontend_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/internal/expr"
+	"github.com/stretchr/testify/require"
+)
+
+type copyPoint struct {
+	X, Y frontend.Variable
+}
+
+type copyEmbedded struct {
+	E frontend.Variable
+}
+
+type copyCircuit struct {
+	copyEmbedded
+	P      copyPoint `gnark:",public"`
+	Ps     []copyPoint
+	Q      *copyPoint
+	Arr    [2]frontend.Variable
+	C      frontend.Variable
+	Hidden frontend.Variable `gnark:"-"`
+	n      int
+}
+
+func (c *copyCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(c.E, c.P.X, c.P.Y, c.Ps[0].X, c.Ps[0].Y, c.Q.X, c.Q.Y, c.Arr[0], c.Arr[1], c.C), c.Hidden)
+	return nil
+}
+
+func newCopyCircuit() *copyCircuit {
+	return &copyCircuit{Ps: make([]copyPoint, 1), Q: new(copyPoint), C: frontend.Constant(3), Hidden: 10, n: 1}
+}
+
+func TestDeepCopy(t *testing.T) {
+	assert := require.New(t)
+
+	// the copy of a compiled circuit keeps the wires
+	circuit := newCopyCircuit()
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, circuit)
+	assert.NoError(err)
+	c, err := frontend.DeepCopy(circuit)
+	assert.NoError(err)
+	cpy := c.(*copyCircuit)
+	assert.Equal(circuit, cpy)
+	cpy.P.X.(expr.LinearExpression)[0].VID = 42
+	cpy.Ps[0].Y = 1
+	cpy.Q.X = 2
+	cpy.Arr[1] = 3
+	cpy.E = 4
+	assert.NotEqual(42, circuit.P.X.(expr.LinearExpression)[0].VID)
+	for _, v := range []frontend.Variable{circuit.Ps[0].Y, circuit.Q.X, circuit.Arr[1], circuit.E} {
+		assert.True(frontend.IsCanonical(v))
+	}
+
+	// the copy of an assignment is a fresh assignment, with the same shape
+	assignment := &copyCircuit{
+		copyEmbedded: copyEmbedded{E: 1},
+		P:            copyPoint{1, 1},
+		Ps:           []copyPoint{{1, 1}},
+		Q:            &copyPoint{1, 1},
+		Arr:          [2]frontend.Variable{1, 1},
+		C:            frontend.Constant(3),
+		Hidden:       10,
+		n:            1,
+	}
+	c, err = frontend.DeepCopy(assignment)
+	assert.NoError(err)
+	cpy = c.(*copyCircuit)
+	assert.Equal(&copyCircuit{
+		Ps:     []copyPoint{{}},
+		Q:      &copyPoint{},
+		C:      frontend.Constant(3),
+		Hidden: 10,
+		n:      1,
+	}, cpy)
+	assert.NotSame(assignment.Q, cpy.Q)
+	cpy.Ps[0].X = 2
+	assert.Equal(1, assignment.Ps[0].X)
+
+	// the copy can be assigned and reused as a circuit
+	leaves, err := frontend.ParseCircuit(cpy)
+	assert.NoError(err)
+	assert.Len(leaves, 9)
+
+	_, err = frontend.DeepCopy(*assignment)
+	assert.Error(err)
+}
+
+type copyNode struct {
+	V    frontend.Variable
+	Next *copyNode `gnark:"-"`
+}
+
+type copyMapCircuit struct {
+	M    map[string]frontend.Variable
+	Node *copyNode
+}
+
+func TestDeepCopyMap(t *testing.T) {
+	assert := require.New(t)
+
+	// the inputs in the maps are reset, and the cycles are copied
+	node := &copyNode{V: 1}
+	node.Next = node
+	assignment := &copyMapCircuit{M: map[string]frontend.Variable{"a": 1, "b": 2}, Node: node}
+	c, err := frontend.DeepCopy(assignment)
+	assert.NoError(err)
+	cpy := c.(*copyMapCircuit)
+	assert.Equal(map[string]frontend.Variable{"a": nil, "b": nil}, cpy.M)
+	assert.Equal(1, assignment.M["a"])
+	assert.Nil(cpy.Node.V)
+	assert.NotSame(node, cpy.Node)
+	assert.Same(cpy.Node, cpy.Node.Next)
+	assert.Equal(1, node.V)
+}
+
+type copyGadget interface {
+	Value() frontend.Variable
+}
+
+type copyGadgetImpl struct {
+	A frontend.Variable
+}
+
+func (g *copyGadgetImpl) Value() frontend.Variable { return g.A }
+
+type copyInterfaceCircuit struct {
+	G copyGadget
+}
+
+func TestDeepCopyInterface(t *testing.T) {
+	assert := require.New(t)
+
+	// the gadget held by an interface is copied, the original stays assigned
+	orig := &copyInterfaceCircuit{G: &copyGadgetImpl{A: 7}}
+	c, err := frontend.DeepCopy(orig)
+	assert.NoError(err)
+	cpy := c.(*copyInterfaceCircuit)
+	assert.NotSame(orig.G, cpy.G)
+	assert.Equal(7, orig.G.Value())
+	assert.Nil(cpy.G.Value())
+}
+
+type unassignCircuit struct {
+	copyCircuit
+	M map[string]frontend.Variable `gnark:",public"`
+}
+
+func TestUnassignAll(t *testing.T) {
+	assert := require.New(t)
+	field := ecc.BN254.ScalarField()
+
+	assign := func(c *unassignCircuit, v int) {
+		c.E, c.P, c.Ps[0], c.Q, c.Arr = v, copyPoint{v, v}, copyPoint{v, v}, &copyPoint{v, v}, [2]frontend.Variable{v, v}
+		c.M["a"], c.M["b"] = v, v
+	}
+	c := &unassignCircuit{copyCircuit: *newCopyCircuit(), M: map[string]frontend.Variable{"a": nil, "b": nil}}
+	assign(c, 1)
+	w1, err := frontend.NewWitness(c, field)
+	assert.NoError(err)
+
+	assert.NoError(frontend.UnassignAll(c))
+	leaves, err := frontend.ParseCircuit(c)
+	assert.NoError(err)
+	assert.Len(leaves, 11)
+	for _, l := range leaves {
+		assert.Nil(*l.Value, l.Name)
+	}
+	assert.Nil(c.M["a"])
+	assert.Equal(frontend.Constant(3), c.C)
+	assert.Equal(10, c.Hidden)
+	_, err = frontend.NewWitness(c, field)
+	assert.Error(err, "unassigned inputs")
+
+	// the circuit can be assigned again
+	assign(c, 2)
+	w2, err := frontend.NewWitness(c, field)
+	assert.NoError(err)
+	assert.NotEqual(w1.Vector(), w2.Vector())
+
+	// the wires of a compiled circuit are kept
+	circuit := newCopyCircuit()
+	_, err = frontend.Compile(field, r1cs.NewBuilder, circuit)
+	assert.NoError(err)
+	assert.NoError(frontend.UnassignAll(circuit))
+	assert.True(frontend.IsCanonical(circuit.P.X))
+
+	assert.Error(frontend.UnassignAll(*c))
+}
@@ -43,8 +43,10 @@ import (
 // Add returns res = i1+i2+...in
 func (builder *builder) Add(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
 	// extract frontend.Variables from input
-	vars, s := builder.toVariables(append([]frontend.Variable{i1, i2}, in...)...)
-	return builder.add(vars, false, s, nil)
+	vars, s := builder.operands(i1, i2, in)
+	res := builder.add(vars, false, s, nil)
+	builder.releaseOperands(vars)
+	return res
 }
 
 func (builder *builder) MulAcc(a, b, c frontend.Variable) frontend.Variable {
@@ -101,8 +103,10 @@ func (builder *builder) MulAcc(a, b, c frontend.Variable) frontend.Variable {
 // Sub returns res = i1 - i2
 func (builder *builder) Sub(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
 	// extract frontend.Variables from input
-	vars, s := builder.toVariables(append([]frontend.Variable{i1, i2}, in...)...)
-	return builder.add(vars, true, s, nil)
+	vars, s := builder.operands(i1, i2, in)
+	res := builder.add(vars, true, s, nil)
+	builder.releaseOperands(vars)
+	return res
 }
 
 // returns res = Σ(vars) or res = vars[0] - Σ(vars[1:]) if sub == true.
@@ -123,7 +127,7 @@ func (builder *builder) add(vars []expr.LinearExpression, sub bool, capacity int
 	builder.heap.heapify()
 
 	if res == nil {
-		t := make(expr.LinearExpression, 0, capacity)
+		t := builder.newLinearExpression(capacity)
 		res = &t
 	}
 	curr := -1
@@ -196,12 +200,12 @@ func (builder *builder) Neg(i frontend.Variable) frontend.Variable {
 
 // Mul returns res = i1 * i2 * ... in
 func (builder *builder) Mul(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
-	vars, _ := builder.toVariables(append([]frontend.Variable{i1, i2}, in...)...)
+	vars, _ := builder.operands(i1, i2, in)
 
 	mul := func(v1, v2 expr.LinearExpression, first bool) expr.LinearExpression {
 
-		n1, v1Constant := builder.constantValue(v1)
-		n2, v2Constant := builder.constantValue(v2)
+		n1, v1Constant := builder.linearConstant(v1)
+		n2, v2Constant := builder.linearConstant(v2)
 
 		// v1 and v2 are both unknown, this is the only case we add a constraint
 		if !v1Constant && !v2Constant {
@@ -227,6 +231,7 @@ func (builder *builder) Mul(i1, i2 frontend.Variable, in ...frontend.Variable) f
 	for i := 2; i < len(vars); i++ {
 		res = mul(res, vars[i], false)
 	}
+	builder.releaseOperands(vars)
 
 	return res
 }
@@ -565,6 +570,9 @@ func (builder *builder) IsZero(i1 frontend.Variable) frontend.Variable {
 
 	builder.cs.AttachDebugInfo(debug, []int{c1, c2})
 
+	// m is 1 when a == 0 and constrained to 0 otherwise, hence a boolean
+	builder.MarkBoolean(m)
+
 	return m
 }
 
@@ -680,6 +688,9 @@ func (builder *builder) Compiler() frontend.Compiler {
 	return builder
 }
 
+// GnarkAPI implements [schema.CircuitAPI].
+func (builder *builder) GnarkAPI() {}
+
 func (builder *builder) Commit(v ...frontend.Variable) (frontend.Variable, error) {
 
 	commitments := builder.cs.GetCommitments().(constraint.Groth16Commitments)
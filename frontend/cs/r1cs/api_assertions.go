@@ -28,9 +28,12 @@ import (
 
 // AssertIsEqual adds an assertion in the constraint builder (i1 == i2)
 func (builder *builder) AssertIsEqual(i1, i2 frontend.Variable) {
+	v1, v2 := builder.toVariable(i1), builder.toVariable(i2)
+	builder.markEqualBoolean(v1, v2)
+
 	// encoded 1 * i1 == i2
-	r := builder.getLinearExpression(builder.toVariable(i1))
-	o := builder.getLinearExpression(builder.toVariable(i2))
+	r := builder.getLinearExpression(v1)
+	o := builder.getLinearExpression(v2)
 
 	cID := builder.cs.AddR1C(builder.newR1C(builder.cstOne(), r, o), builder.genericGate)
 
@@ -40,6 +43,26 @@ func (builder *builder) AssertIsEqual(i1, i2 frontend.Variable) {
 	}
 }
 
+// markEqualBoolean marks i1, constrained to be equal to i2, as a boolean if i2
+// is known to be boolean, and conversely, so that asserting later that it is
+// a boolean adds no constraint.
+func (builder *builder) markEqualBoolean(i1, i2 frontend.Variable) {
+	b1, b2 := builder.IsBoolean(i1), builder.IsBoolean(i2)
+	if b1 == b2 {
+		return
+	}
+	// v is the one not known to be boolean
+	v := i1
+	if b1 {
+		v = i2
+	}
+	if _, ok := builder.constantValue(v); ok {
+		// a constant which is not a boolean, the equality can not hold
+		return
+	}
+	builder.MarkBoolean(v)
+}
+
 // AssertIsDifferent constrain i1 and i2 to be different
 func (builder *builder) AssertIsDifferent(i1, i2 frontend.Variable) {
 	s := builder.Sub(i1, i2).(expr.LinearExpression)
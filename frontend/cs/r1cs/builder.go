@@ -18,9 +18,13 @@ package r1cs
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
+	"path/filepath"
 	"reflect"
+	"runtime"
 	"sort"
+	"sync"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/constraint"
@@ -57,13 +61,27 @@ type builder struct {
 	config frontend.CompileConfig
 	kvstore.Store
 
-	// map for recording boolean constrained variables (to not constrain them twice)
-	mtBooleans map[uint64][]expr.LinearExpression
-
 	tOne        constraint.Element
 	eZero, eOne expr.LinearExpression
 	cZero, cOne constraint.LinearExpression
 
+	// the buffers which do not outlive the compilation, taken from
+	// scratchPool and put back by Compile
+	*scratch
+
+	// the free terms of the current chunk, see newLinearExpression
+	terms []expr.Term
+
+	genericGate constraint.BlueprintID
+}
+
+// scratch holds the state of a builder which is not referenced by the
+// constraint system nor by the variables of the circuit, so that it can be
+// reused by the next compilation instead of being allocated again by each one.
+type scratch struct {
+	// map for recording boolean constrained variables (to not constrain them twice)
+	mtBooleans map[uint64][]expr.LinearExpression
+
 	// helps merge k sorted linear expressions
 	heap minHeap
 
@@ -71,7 +89,44 @@ type builder struct {
 	mbuf1 expr.LinearExpression
 	mbuf2 expr.LinearExpression
 
-	genericGate constraint.BlueprintID
+	// buffers used by newR1C; the constraint is compressed when it is added to
+	// the system, so they are reused for every constraint
+	lbuf, rbuf, obuf constraint.LinearExpression
+
+	// free buffers for the operands of the operations, see operands
+	operandBufs [][]expr.LinearExpression
+}
+
+// scratchPool holds the scratch states of the finished compilations.
+var scratchPool sync.Pool
+
+// pooling disables scratchPool and the chunks of terms of
+// newLinearExpression when false, for the benchmarks.
+var pooling = true
+
+// newScratch returns a scratch state from the pool, or a new one with the
+// given capacities.
+func newScratch(config frontend.CompileConfig, macCapacity int) *scratch {
+	if s, ok := scratchPool.Get().(*scratch); ok && pooling {
+		return s
+	}
+	return &scratch{
+		mtBooleans: make(map[uint64][]expr.LinearExpression, config.Capacity/10),
+		heap:       make(minHeap, 0, 100),
+		mbuf1:      make(expr.LinearExpression, 0, macCapacity),
+		mbuf2:      make(expr.LinearExpression, 0, macCapacity),
+	}
+}
+
+// Reset clears s, keeping its allocated memory, so that it holds no reference
+// to the compilation which used it.
+func (s *scratch) Reset() {
+	for k := range s.mtBooleans {
+		delete(s.mtBooleans, k)
+	}
+	s.heap = s.heap[:0]
+	s.mbuf1, s.mbuf2 = s.mbuf1[:0], s.mbuf2[:0]
+	s.lbuf, s.rbuf, s.obuf = s.lbuf[:0], s.rbuf[:0], s.obuf[:0]
 }
 
 // initialCapacity has quite some impact on frontend performance, especially on large circuits size
@@ -82,12 +137,9 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		macCapacity = config.CompressThreshold
 	}
 	builder := builder{
-		mtBooleans: make(map[uint64][]expr.LinearExpression, config.Capacity/10),
-		config:     config,
-		heap:       make(minHeap, 0, 100),
-		mbuf1:      make(expr.LinearExpression, 0, macCapacity),
-		mbuf2:      make(expr.LinearExpression, 0, macCapacity),
-		Store:      kvstore.New(),
+		config:  config,
+		scratch: newScratch(config, macCapacity),
+		Store:   kvstore.New(),
 	}
 
 	// by default the circuit is given a public wire equal to 1
@@ -135,7 +187,34 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 // the wire's id to the number of wires, and returns it
 func (builder *builder) newInternalVariable() expr.LinearExpression {
 	idx := builder.cs.AddInternalVariable()
-	return expr.NewLinearExpression(idx, builder.tOne)
+	return builder.newTerm(idx, builder.tOne)
+}
+
+// termChunk is the number of terms allocated at once by newLinearExpression.
+const termChunk = 1024
+
+// newLinearExpression returns an empty linear expression with capacity n. The
+// short ones are cut from a chunk of terms, so that the linear expressions
+// built by the operations do not cost an allocation each. An append past the
+// capacity reallocates the expression, it never overwrites the next one.
+//
+// The chunks are not reused by the next compilations: the variables of a
+// compiled circuit, for example its inputs, would then change.
+func (builder *builder) newLinearExpression(n int) expr.LinearExpression {
+	if n > termChunk/8 || !pooling {
+		return make(expr.LinearExpression, 0, n)
+	}
+	if cap(builder.terms) < n {
+		builder.terms = make([]expr.Term, termChunk)
+	}
+	l := builder.terms[:0:n]
+	builder.terms = builder.terms[n:]
+	return l
+}
+
+// newTerm returns the linear expression coeff*vID, see newLinearExpression.
+func (builder *builder) newTerm(vID int, coeff constraint.Element) expr.LinearExpression {
+	return append(builder.newLinearExpression(1), expr.NewTerm(vID, coeff))
 }
 
 // PublicVariable creates a new public Variable
@@ -172,12 +251,16 @@ func (builder *builder) FieldBitLen() int {
 	return builder.cs.FieldBitLen()
 }
 
-// newR1C clones the linear expression associated with the Variables (to avoid offsetting the ID multiple time)
-// and return a R1C
+// newR1C converts the Variables to the linear expressions of a R1C, and returns
+// it.
+//
+// The linear expressions of the R1C are stored in buffers of the builder, not
+// cloned: the R1C must be added to the constraint system, which compresses it,
+// before the next call.
 func (builder *builder) newR1C(l, r, o frontend.Variable) constraint.R1C {
-	L := builder.getLinearExpression(l)
-	R := builder.getLinearExpression(r)
-	O := builder.getLinearExpression(o)
+	L := builder.toLinearExpression(l, &builder.lbuf)
+	R := builder.toLinearExpression(r, &builder.rbuf)
+	O := builder.toLinearExpression(o, &builder.obuf)
 
 	// interestingly, this is key to groth16 performance.
 	// l * r == r * l == o
@@ -194,6 +277,12 @@ func (builder *builder) newR1C(l, r, o frontend.Variable) constraint.R1C {
 }
 
 func (builder *builder) getLinearExpression(_l interface{}) constraint.LinearExpression {
+	return builder.toLinearExpression(_l, nil)
+}
+
+// toLinearExpression is getLinearExpression, appending the terms to (*buf)[:0]
+// instead of a new slice if buf is not nil.
+func (builder *builder) toLinearExpression(_l interface{}, buf *constraint.LinearExpression) constraint.LinearExpression {
 	var L constraint.LinearExpression
 	switch tl := _l.(type) {
 	case expr.LinearExpression:
@@ -204,10 +293,17 @@ func (builder *builder) getLinearExpression(_l interface{}) constraint.LinearExp
 				return builder.cOne
 			}
 		}
-		L = make(constraint.LinearExpression, 0, len(tl))
+		if buf != nil {
+			L = (*buf)[:0]
+		} else {
+			L = make(constraint.LinearExpression, 0, len(tl))
+		}
 		for _, t := range tl {
 			L = append(L, builder.cs.MakeTerm(t.Coeff, t.VID))
 		}
+		if buf != nil {
+			*buf = L
+		}
 	case constraint.LinearExpression:
 		L = tl
 	default:
@@ -278,6 +374,10 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		Int("nbConstraints", builder.cs.GetNbConstraints()).
 		Msg("building constraint builder")
 
+	for _, a := range builder.cs.GetAssumptions() {
+		log.Warn().Str("variable", a.Variable).Int("bits", a.Bits).Str("caller", a.Caller).Msg("range assumed without constraint")
+	}
+
 	// ensure all inputs and hints are constrained
 	if err := builder.cs.CheckUnconstrainedWires(); err != nil {
 		log.Warn().Msg("circuit has unconstrained inputs")
@@ -286,9 +386,66 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		}
 	}
 
+	// the builder is not used after the compilation
+	if builder.scratch != nil && pooling {
+		builder.scratch.Reset()
+		scratchPool.Put(builder.scratch)
+		builder.scratch = nil
+	}
+
 	return builder.cs, nil
 }
 
+// EnterRegion opens a named profiling region. See [frontend.Regioner].
+func (builder *builder) EnterRegion(name string) {
+	builder.cs.EnterRegion(name)
+}
+
+// LeaveRegion closes the last opened profiling region. See [frontend.Regioner].
+func (builder *builder) LeaveRegion() {
+	builder.cs.LeaveRegion()
+}
+
+// AssumeInRange records that v has bit-length at most bits, without adding
+// constraints. See [frontend.Assumer].
+func (builder *builder) AssumeInRange(v frontend.Variable, bits int) error {
+	if bits <= 0 {
+		return fmt.Errorf("range assumption: invalid bit-length %d", bits)
+	}
+	a := constraint.Assumption{Bits: bits}
+	if _, file, line, ok := runtime.Caller(1); ok {
+		a.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	if builder.config.ForbidAssumptions {
+		return fmt.Errorf("range assumption at %s: assumptions are forbidden by the compile options", a.Caller)
+	}
+	if c, ok := builder.constantValue(v); ok {
+		// constants are checked directly, there is nothing to assume
+		if n := builder.cs.ToBigInt(c).BitLen(); n > bits {
+			return fmt.Errorf("range assumption at %s: constant has %d bits, more than %d", a.Caller, n, bits)
+		}
+		return nil
+	}
+	a.Variable = builder.getLinearExpression(builder.toVariable(v)).String(builder.cs)
+	builder.cs.AddAssumption(a)
+	return nil
+}
+
+// AnnotateWidth records that v is expected to fit in bits bits, see
+// [frontend.WidthAnnotator]. It adds no constraint nor wire.
+func (builder *builder) AnnotateWidth(v frontend.Variable, bits int) {
+	if bits < 0 {
+		panic("negative width")
+	}
+	a := constraint.WidthAnnotation{Bits: bits}
+	// skip frontend.AnnotateWidth
+	if _, file, line, ok := runtime.Caller(2); ok {
+		a.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	a.Value = builder.getLinearExpression(builder.toVariable(v))
+	builder.cs.AddWidthAnnotation(a)
+}
+
 // ConstantValue returns the big.Int value of v.
 // Will panic if v.IsConstant() == false
 func (builder *builder) ConstantValue(v frontend.Variable) (*big.Int, bool) {
@@ -301,21 +458,27 @@ func (builder *builder) ConstantValue(v frontend.Variable) (*big.Int, bool) {
 
 func (builder *builder) constantValue(v frontend.Variable) (constraint.Element, bool) {
 	if _v, ok := v.(expr.LinearExpression); ok {
-		assertIsSet(_v)
-
-		if len(_v) != 1 {
-			// TODO @gbotrel this assumes linear expressions of coeff are not possible
-			// and are always reduced to one element. may not always be true?
-			return constraint.Element{}, false
-		}
-		if !(_v[0].WireID() == 0) { // public ONE WIRE
-			return constraint.Element{}, false
-		}
-		return _v[0].Coeff, true
+		return builder.linearConstant(_v)
 	}
 	return builder.cs.FromInterface(v), true
 }
 
+// linearConstant is constantValue for a linear expression, which is not boxed
+// in a frontend.Variable.
+func (builder *builder) linearConstant(v expr.LinearExpression) (constraint.Element, bool) {
+	assertIsSet(v)
+
+	if len(v) != 1 {
+		// TODO @gbotrel this assumes linear expressions of coeff are not possible
+		// and are always reduced to one element. may not always be true?
+		return constraint.Element{}, false
+	}
+	if !(v[0].WireID() == 0) { // public ONE WIRE
+		return constraint.Element{}, false
+	}
+	return v[0].Coeff, true
+}
+
 // toVariable will return (and allocate if necessary) a linearExpression from given value
 //
 // if input is already a linearExpression, does nothing
@@ -331,14 +494,49 @@ func (builder *builder) toVariable(input interface{}) expr.LinearExpression {
 		assertIsSet(*t)
 		return *t
 	case constraint.Element:
-		return expr.NewLinearExpression(0, t)
+		return builder.newTerm(0, t)
 	case *constraint.Element:
-		return expr.NewLinearExpression(0, *t)
+		return builder.newTerm(0, *t)
 	default:
 		// try to make it into a constant
 		c := builder.cs.FromInterface(t)
-		return expr.NewLinearExpression(0, c)
+		return builder.newTerm(0, c)
+	}
+}
+
+// operands is toVariables for the operands i1, i2 and in of an operation. The
+// returned slice is a buffer of the scratch state, released with
+// releaseOperands when the operation returns, so that the operations may be
+// nested.
+func (builder *builder) operands(i1, i2 frontend.Variable, in []frontend.Variable) ([]expr.LinearExpression, int) {
+	var r []expr.LinearExpression
+	if n := len(builder.operandBufs); n > 0 {
+		r = builder.operandBufs[n-1]
+		builder.operandBufs = builder.operandBufs[:n-1]
+	} else {
+		r = make([]expr.LinearExpression, 0, 2+len(in))
+	}
+	s := 0
+	for _, i := range [2]frontend.Variable{i1, i2} {
+		v := builder.toVariable(i)
+		r = append(r, v)
+		s += len(v)
+	}
+	for _, i := range in {
+		v := builder.toVariable(i)
+		r = append(r, v)
+		s += len(v)
 	}
+	return r, s
+}
+
+// releaseOperands puts back the buffer returned by operands.
+func (builder *builder) releaseOperands(r []expr.LinearExpression) {
+	// the buffer holds no reference to the variables of the circuit
+	for i := range r {
+		r[i] = nil
+	}
+	builder.operandBufs = append(builder.operandBufs, r[:0])
 }
 
 // toVariables return frontend.Variable corresponding to inputs and the total size of the linear expressions
@@ -379,6 +577,13 @@ func (builder *builder) NewHintForId(id solver.HintID, nbOutputs int, inputs ...
 }
 
 func (builder *builder) newHint(f solver.Hint, id solver.HintID, nbOutputs int, inputs []frontend.Variable) ([]frontend.Variable, error) {
+	if builder.config.NoHints {
+		name := fmt.Sprintf("%d", id)
+		if f != nil {
+			name = solver.GetHintName(f)
+		}
+		return nil, fmt.Errorf("hint %s at %s: hints are forbidden by the compile options", name, debug.Caller())
+	}
 	hintInputs := make([]constraint.LinearExpression, len(inputs))
 
 	// TODO @gbotrel hint input pass
@@ -17,6 +17,7 @@ limitations under the License.
 package r1cs
 
 import (
+	"fmt"
 	"math/rand"
 	"sort"
 	"testing"
@@ -160,3 +161,38 @@ func TestPreCompileHook(t *testing.T) {
 		t.Error("callback not called")
 	}
 }
+
+type mulChainCircuit struct {
+	X, Y frontend.Variable
+	n    int
+}
+
+func (c *mulChainCircuit) Define(api frontend.API) error {
+	x := c.X
+	for i := 0; i < c.n; i++ {
+		x = api.Add(api.Mul(x, c.Y), i)
+	}
+	api.AssertIsEqual(x, 0)
+	return nil
+}
+
+// BenchmarkCompile reports the allocations of compiling the same circuit 1000
+// times, as a long-running service would, with and without the pooling of the
+// builders. An operation is the 1000 compilations.
+func BenchmarkCompile(b *testing.B) {
+	const nbCompiles = 1000
+	for _, pool := range []bool{true, false} {
+		b.Run(fmt.Sprintf("pool=%t", pool), func(b *testing.B) {
+			defer func(old bool) { pooling = old }(pooling)
+			pooling = pool
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < nbCompiles; j++ {
+					if _, err := frontend.Compile(ecc.BN254.ScalarField(), NewBuilder, &mulChainCircuit{n: 1000}); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
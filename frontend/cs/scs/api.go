@@ -416,6 +416,9 @@ func (builder *builder) Select(b frontend.Variable, i1, i2 frontend.Variable) fr
 		return i1
 	}
 
+	// ensures that b is boolean, no constraint is added if it is known to be
+	builder.AssertIsBoolean(b)
+
 	u := builder.Sub(i1, i2)
 	l := builder.Mul(u, b)
 
@@ -515,6 +518,9 @@ func (builder *builder) IsZero(i1 frontend.Variable) frontend.Variable {
 		qM: a.Coeff,
 	})
 
+	// m is 1 when a == 0 and constrained to 0 otherwise, hence a boolean
+	builder.MarkBoolean(m)
+
 	return m
 }
 
@@ -620,6 +626,9 @@ func (builder *builder) Compiler() frontend.Compiler {
 	return builder
 }
 
+// GnarkAPI implements [schema.CircuitAPI].
+func (builder *builder) GnarkAPI() {}
+
 func (builder *builder) Commit(v ...frontend.Variable) (frontend.Variable, error) {
 
 	commitments := builder.cs.GetCommitments().(constraint.PlonkCommitments)
@@ -38,6 +38,7 @@ func (builder *builder) AssertIsEqual(i1, i2 frontend.Variable) {
 		}
 		return
 	}
+	builder.markEqualBoolean(i1, i2)
 	if i1Constant {
 		i1, i2 = i2, i1
 		i2Constant = i1Constant
@@ -83,6 +84,26 @@ func (builder *builder) AssertIsEqual(i1, i2 frontend.Variable) {
 	}
 }
 
+// markEqualBoolean marks i1, constrained to be equal to i2, as a boolean if i2
+// is known to be boolean, and conversely, so that asserting later that it is
+// a boolean adds no constraint.
+func (builder *builder) markEqualBoolean(i1, i2 frontend.Variable) {
+	b1, b2 := builder.IsBoolean(i1), builder.IsBoolean(i2)
+	if b1 == b2 {
+		return
+	}
+	// v is the one not known to be boolean
+	v := i1
+	if b1 {
+		v = i2
+	}
+	if _, ok := builder.constantValue(v); ok {
+		// a constant which is not a boolean, the equality can not hold
+		return
+	}
+	builder.MarkBoolean(v)
+}
+
 // AssertIsDifferent fails if i1 == i2
 func (builder *builder) AssertIsDifferent(i1, i2 frontend.Variable) {
 	s := builder.Sub(i1, i2)
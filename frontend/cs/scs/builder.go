@@ -17,8 +17,11 @@ limitations under the License.
 package scs
 
 import (
+	"fmt"
 	"math/big"
+	"path/filepath"
 	"reflect"
+	"runtime"
 	"sort"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -289,6 +292,10 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		Int("nbConstraints", builder.cs.GetNbConstraints()).
 		Msg("building constraint builder")
 
+	for _, a := range builder.cs.GetAssumptions() {
+		log.Warn().Str("variable", a.Variable).Int("bits", a.Bits).Str("caller", a.Caller).Msg("range assumed without constraint")
+	}
+
 	// ensure all inputs and hints are constrained
 	err := builder.cs.CheckUnconstrainedWires()
 	if err != nil {
@@ -301,6 +308,66 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 	return builder.cs, nil
 }
 
+// EnterRegion opens a named profiling region. See [frontend.Regioner].
+func (builder *builder) EnterRegion(name string) {
+	builder.cs.EnterRegion(name)
+}
+
+// LeaveRegion closes the last opened profiling region. See [frontend.Regioner].
+func (builder *builder) LeaveRegion() {
+	builder.cs.LeaveRegion()
+}
+
+// AssumeInRange records that v has bit-length at most bits, without adding
+// constraints. See [frontend.Assumer].
+func (builder *builder) AssumeInRange(v frontend.Variable, bits int) error {
+	if bits <= 0 {
+		return fmt.Errorf("range assumption: invalid bit-length %d", bits)
+	}
+	a := constraint.Assumption{Bits: bits}
+	if _, file, line, ok := runtime.Caller(1); ok {
+		a.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	if builder.config.ForbidAssumptions {
+		return fmt.Errorf("range assumption at %s: assumptions are forbidden by the compile options", a.Caller)
+	}
+	if c, ok := builder.constantValue(v); ok {
+		// constants are checked directly, there is nothing to assume
+		if n := builder.cs.ToBigInt(c).BitLen(); n > bits {
+			return fmt.Errorf("range assumption at %s: constant has %d bits, more than %d", a.Caller, n, bits)
+		}
+		return nil
+	}
+	t, ok := v.(expr.Term)
+	if !ok {
+		return fmt.Errorf("range assumption on %v: invalid variable type %T", v, v)
+	}
+	a.Variable = builder.cs.MakeTerm(t.Coeff, t.VID).String(builder.cs)
+	builder.cs.AddAssumption(a)
+	return nil
+}
+
+// AnnotateWidth records that v is expected to fit in bits bits, see
+// [frontend.WidthAnnotator]. It adds no constraint nor wire.
+func (builder *builder) AnnotateWidth(v frontend.Variable, bits int) {
+	if bits < 0 {
+		panic("negative width")
+	}
+	a := constraint.WidthAnnotation{Bits: bits}
+	// skip frontend.AnnotateWidth
+	if _, file, line, ok := runtime.Caller(2); ok {
+		a.Caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	if t, ok := v.(expr.Term); ok {
+		a.Value = constraint.LinearExpression{builder.cs.MakeTerm(t.Coeff, t.VID)}
+	} else {
+		term := builder.cs.MakeTerm(builder.cs.FromInterface(v), 0)
+		term.MarkConstant()
+		a.Value = constraint.LinearExpression{term}
+	}
+	builder.cs.AddWidthAnnotation(a)
+}
+
 // ConstantValue returns the big.Int value of v.
 // Will panic if v.IsConstant() == false
 func (builder *builder) ConstantValue(v frontend.Variable) (*big.Int, bool) {
@@ -346,6 +413,13 @@ func (builder *builder) NewHint(f solver.Hint, nbOutputs int, inputs ...frontend
 }
 
 func (builder *builder) newHint(f solver.Hint, id solver.HintID, nbOutputs int, inputs ...frontend.Variable) ([]frontend.Variable, error) {
+	if builder.config.NoHints {
+		name := fmt.Sprintf("%d", id)
+		if f != nil {
+			name = solver.GetHintName(f)
+		}
+		return nil, fmt.Errorf("hint %s at %s: hints are forbidden by the compile options", name, debug.Caller())
+	}
 	hintInputs := builder.hintBuffer(len(inputs))
 
 	// ensure inputs are set and pack them in a []uint64
@@ -0,0 +1,301 @@
+// Package dsl implements a minimal textual description language for circuits.
+//
+// It allows tooling written in other languages to emit circuits which can
+// then be compiled with gnark. A circuit description is a sequence of
+// statements, one per line. Empty lines and lines starting with '#' are
+// ignored. The statements are:
+//
+//	public <name>...          declares public inputs
+//	secret <name>...          declares secret inputs
+//	<name> = <op> <arg>...    defines a new variable, <op> is one of add, sub, mul, div, neg, inv
+//	assert_eq <arg> <arg>     asserts that the arguments are equal
+//	assert_neq <arg> <arg>    asserts that the arguments are different
+//	assert_bool <arg>         asserts that the argument is boolean
+//
+// An argument is either the name of a previously declared input or variable,
+// or a base 10 integer constant. For example, the following description
+// corresponds to the circuit x**3 + x + 5 == y:
+//
+//	public Y
+//	secret X
+//	x3 = mul X X X
+//	r = add x3 X 5
+//	assert_eq Y r
+//
+// The statements map one to one to calls to [frontend.API], such that the
+// compiled constraint system is the same as the one obtained from the
+// equivalent Go circuit, provided the inputs are declared in the same order.
+package dsl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Circuit is a circuit parsed from a textual description. It implements
+// [frontend.Circuit] and can be compiled with [frontend.Compile].
+//
+// The public (resp. secret) inputs are stored in the order of their
+// declaration in Public (resp. Secret). Use [Circuit.Assign] to build an
+// assignment from named values.
+type Circuit struct {
+	Public []frontend.Variable `gnark:",public"`
+	Secret []frontend.Variable `gnark:",secret"`
+
+	publicNames  []string      `gnark:"-"`
+	secretNames  []string      `gnark:"-"`
+	instructions []instruction `gnark:"-"`
+}
+
+type opcode string
+
+const (
+	opAdd        opcode = "add"
+	opSub        opcode = "sub"
+	opMul        opcode = "mul"
+	opDiv        opcode = "div"
+	opNeg        opcode = "neg"
+	opInv        opcode = "inv"
+	opAssertEq   opcode = "assert_eq"
+	opAssertNeq  opcode = "assert_neq"
+	opAssertBool opcode = "assert_bool"
+)
+
+// arity returns the minimal and maximal (-1 for unbounded) number of arguments.
+func (op opcode) arity() (int, int) {
+	switch op {
+	case opAdd, opSub, opMul:
+		return 2, -1
+	case opDiv, opAssertEq, opAssertNeq:
+		return 2, 2
+	case opNeg, opInv, opAssertBool:
+		return 1, 1
+	}
+	return -1, -1
+}
+
+func (op opcode) isAssertion() bool {
+	return op == opAssertEq || op == opAssertNeq || op == opAssertBool
+}
+
+type instruction struct {
+	line   int
+	op     opcode
+	output string // empty for assertions
+	args   []string
+}
+
+// Parse reads a circuit description from r. It returns an error if the
+// description is malformed, if a name is declared twice or if an argument
+// refers to an unknown name.
+func Parse(r io.Reader) (*Circuit, error) {
+	c := new(Circuit)
+	defined := make(map[string]struct{})
+	define := func(line int, name string) error {
+		if !isIdentifier(name) {
+			return fmt.Errorf("line %d: invalid name %q", line, name)
+		}
+		if _, ok := defined[name]; ok {
+			return fmt.Errorf("line %d: %q is already defined", line, name)
+		}
+		defined[name] = struct{}{}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		tokens := strings.Fields(text)
+
+		switch tokens[0] {
+		case "public", "secret":
+			if len(c.instructions) != 0 {
+				return nil, fmt.Errorf("line %d: inputs must be declared before any operation", line)
+			}
+			if len(tokens) < 2 {
+				return nil, fmt.Errorf("line %d: missing input name", line)
+			}
+			for _, name := range tokens[1:] {
+				if err := define(line, name); err != nil {
+					return nil, err
+				}
+				if tokens[0] == "public" {
+					c.publicNames = append(c.publicNames, name)
+				} else {
+					c.secretNames = append(c.secretNames, name)
+				}
+			}
+			continue
+		}
+
+		var inst instruction
+		inst.line = line
+		if len(tokens) >= 3 && tokens[1] == "=" {
+			inst.output = tokens[0]
+			inst.op = opcode(tokens[2])
+			inst.args = tokens[3:]
+			if inst.op.isAssertion() {
+				return nil, fmt.Errorf("line %d: assertion %s does not have an output", line, inst.op)
+			}
+		} else {
+			inst.op = opcode(tokens[0])
+			inst.args = tokens[1:]
+			if !inst.op.isAssertion() {
+				return nil, fmt.Errorf("line %d: missing output for operation %s", line, inst.op)
+			}
+		}
+
+		lo, hi := inst.op.arity()
+		if lo < 0 {
+			return nil, fmt.Errorf("line %d: unknown operation %q", line, inst.op)
+		}
+		if len(inst.args) < lo || (hi >= 0 && len(inst.args) > hi) {
+			return nil, fmt.Errorf("line %d: invalid number of arguments for %s: %d", line, inst.op, len(inst.args))
+		}
+		for _, arg := range inst.args {
+			if _, isConstant := new(big.Int).SetString(arg, 10); isConstant {
+				continue
+			}
+			if _, ok := defined[arg]; !ok {
+				return nil, fmt.Errorf("line %d: undefined %q", line, arg)
+			}
+		}
+		if inst.output != "" {
+			if err := define(line, inst.output); err != nil {
+				return nil, err
+			}
+		}
+		c.instructions = append(c.instructions, inst)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	c.Public = make([]frontend.Variable, len(c.publicNames))
+	c.Secret = make([]frontend.Variable, len(c.secretNames))
+	return c, nil
+}
+
+// Define implements [frontend.Circuit].
+func (c *Circuit) Define(api frontend.API) error {
+	if len(c.Public) != len(c.publicNames) || len(c.Secret) != len(c.secretNames) {
+		return fmt.Errorf("circuit inputs do not match the description")
+	}
+	variables := make(map[string]frontend.Variable, len(c.publicNames)+len(c.secretNames)+len(c.instructions))
+	for i, name := range c.publicNames {
+		variables[name] = c.Public[i]
+	}
+	for i, name := range c.secretNames {
+		variables[name] = c.Secret[i]
+	}
+
+	for _, inst := range c.instructions {
+		args := make([]frontend.Variable, len(inst.args))
+		for i, arg := range inst.args {
+			if v, ok := variables[arg]; ok {
+				args[i] = v
+			} else {
+				args[i] = arg // constant, validated at parsing time.
+			}
+		}
+
+		var res frontend.Variable
+		switch inst.op {
+		case opAdd:
+			res = api.Add(args[0], args[1], args[2:]...)
+		case opSub:
+			res = api.Sub(args[0], args[1], args[2:]...)
+		case opMul:
+			res = api.Mul(args[0], args[1], args[2:]...)
+		case opDiv:
+			res = api.Div(args[0], args[1])
+		case opNeg:
+			res = api.Neg(args[0])
+		case opInv:
+			res = api.Inverse(args[0])
+		case opAssertEq:
+			api.AssertIsEqual(args[0], args[1])
+		case opAssertNeq:
+			api.AssertIsDifferent(args[0], args[1])
+		case opAssertBool:
+			api.AssertIsBoolean(args[0])
+		default:
+			return fmt.Errorf("line %d: unknown operation %q", inst.line, inst.op)
+		}
+		if inst.output != "" {
+			variables[inst.output] = res
+		}
+	}
+	return nil
+}
+
+// Assign returns an assignment of the circuit where the inputs are set from
+// the named values. It returns an error if an input is missing or if a value
+// does not correspond to any input.
+func (c *Circuit) Assign(values map[string]interface{}) (*Circuit, error) {
+	assignment := &Circuit{
+		Public:       make([]frontend.Variable, len(c.publicNames)),
+		Secret:       make([]frontend.Variable, len(c.secretNames)),
+		publicNames:  c.publicNames,
+		secretNames:  c.secretNames,
+		instructions: c.instructions,
+	}
+	assign := func(names []string, to []frontend.Variable) error {
+		for i, name := range names {
+			v, ok := values[name]
+			if !ok {
+				return fmt.Errorf("missing value for input %q", name)
+			}
+			to[i] = v
+		}
+		return nil
+	}
+	if err := assign(c.publicNames, assignment.Public); err != nil {
+		return nil, err
+	}
+	if err := assign(c.secretNames, assignment.Secret); err != nil {
+		return nil, err
+	}
+	if len(values) != len(c.publicNames)+len(c.secretNames) {
+		for name := range values {
+			if !contains(c.publicNames, name) && !contains(c.secretNames, name) {
+				return nil, fmt.Errorf("%q is not an input of the circuit", name)
+			}
+		}
+	}
+	return assignment, nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isIdentifier(s string) bool {
+	if s == "" || s == "=" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package dsl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+)
+
+const cubicDescription = `
+# x**3 + x + 5 == y
+public Y
+secret X
+x3 = mul X X X
+r = add x3 X 5
+assert_eq Y r
+`
+
+type cubic struct {
+	Y frontend.Variable `gnark:",public"`
+	X frontend.Variable `gnark:",secret"`
+}
+
+func (c *cubic) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+func TestCompileDSL(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	circuit, err := Parse(strings.NewReader(cubicDescription))
+	assert.NoError(err)
+
+	ccsDSL, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, circuit)
+	assert.NoError(err)
+	ccsGo, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubic{})
+	assert.NoError(err)
+
+	assert.Equal(ccsGo.GetNbConstraints(), ccsDSL.GetNbConstraints())
+	assert.Equal(ccsGo.GetNbPublicVariables(), ccsDSL.GetNbPublicVariables())
+	assert.Equal(ccsGo.GetNbSecretVariables(), ccsDSL.GetNbSecretVariables())
+	assert.True(reflect.DeepEqual(ccsGo.(constraint.R1CS).GetR1Cs(), ccsDSL.(constraint.R1CS).GetR1Cs()), "R1CS differ")
+
+	valid, err := circuit.Assign(map[string]interface{}{"X": 3, "Y": 35})
+	assert.NoError(err)
+	invalid, err := circuit.Assign(map[string]interface{}{"X": 3, "Y": 36})
+	assert.NoError(err)
+	assert.CheckCircuit(circuit, test.WithValidAssignment(valid), test.WithInvalidAssignment(invalid), test.WithCurves(ecc.BN254))
+
+	_, err = circuit.Assign(map[string]interface{}{"X": 3})
+	assert.Error(err)
+	_, err = circuit.Assign(map[string]interface{}{"X": 3, "Y": 35, "Z": 1})
+	assert.Error(err)
+}
+
+func TestParseErrors(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, description := range []string{
+		"secret X\nassert_eq X Y",           // undefined name
+		"secret X\nX = add X 1",             // redefinition
+		"secret X\nr = pow X 2",             // unknown operation
+		"secret X\nr = div X",               // wrong arity
+		"secret X\nadd X 1",                 // missing output
+		"secret X\nr = assert_bool X",       // assertion with an output
+		"secret X\nassert_bool X\npublic Y", // late declaration
+		"secret 1X",                         // invalid name
+	} {
+		_, err := Parse(strings.NewReader(description))
+		assert.Error(err, description)
+	}
+}
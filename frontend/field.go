@@ -0,0 +1,22 @@
+package frontend
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrFieldTooSmall is returned by [RequireFieldBits] when the scalar field is
+// smaller than required.
+var ErrFieldTooSmall = errors.New("scalar field too small")
+
+// RequireFieldBits returns an error wrapping [ErrFieldTooSmall] if the scalar
+// field of api has less than n bits. Gadgets which rely on the field size for
+// soundness, for example to range check 254-bit values, should call it in
+// their constructor and return the error so that the compilation fails instead
+// of silently producing a broken circuit on an unsuitable curve.
+func RequireFieldBits(api API, n int) error {
+	if nbBits := api.Compiler().FieldBitLen(); nbBits < n {
+		return fmt.Errorf("%w: gadget requires %d bits, got %d bits", ErrFieldTooSmall, n, nbBits)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package frontend_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+type fieldBitsCircuit struct {
+	X frontend.Variable
+}
+
+func (c *fieldBitsCircuit) Define(api frontend.API) error {
+	if err := frontend.RequireFieldBits(api, 254); err != nil {
+		return err
+	}
+	api.ToBinary(c.X, 254)
+	return nil
+}
+
+func TestRequireFieldBits(t *testing.T) {
+	if _, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &fieldBitsCircuit{}); err != nil {
+		t.Fatal(err)
+	}
+	// the BLS12-377 scalar field has 253 bits
+	_, err := frontend.Compile(ecc.BLS12_377.ScalarField(), r1cs.NewBuilder, &fieldBitsCircuit{})
+	if !errors.Is(err, frontend.ErrFieldTooSmall) {
+		t.Fatalf("expected field size error, got %v", err)
+	}
+}
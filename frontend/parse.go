@@ -0,0 +1,224 @@
+package frontend
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// LeafInfo describes a Variable of a circuit, see [ParseCircuit].
+type LeafInfo struct {
+	// Name is the full name of the variable, for example "A_B_2" for the third
+	// element of the slice B of the struct A. See [schema.NameStrategy].
+	Name string
+	// Visibility is [schema.Public] or [schema.Secret].
+	Visibility schema.Visibility
+	// Value points to the variable in the circuit. For the elements of a map,
+	// it points to a copy of the element.
+	Value *Variable
+}
+
+// ParseCircuit walks the circuit as the compiler does and returns its
+// variables, in the order of their declaration, with their full names and
+// visibilities. The circuit must be a pointer. It returns the errors the
+// compiler would return when parsing the inputs of the circuit, for example on
+// invalid tags. The names are built with the strategy set in opts, see
+// [schema.WithNameStrategy].
+func ParseCircuit(circuit interface{}, opts ...schema.Option) ([]LeafInfo, error) {
+	var leaves []LeafInfo
+	err := Walk(circuit, func(visibility schema.Visibility, name string, v *Variable) error {
+		leaves = append(leaves, LeafInfo{Name: name, Visibility: visibility, Value: v})
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// Walk walks the circuit as the compiler does and calls handler with the
+// visibility, the full name and a pointer to each of its variables, in the
+// order of [ParseCircuit]. The handler can set the variables, for example to
+// allocate them; the elements of the maps are written back after the handler
+// returns. The circuit must be a pointer.
+//
+// The errors returned by handler do not stop the walk: Walk returns them
+// joined with the errors the compiler would return when parsing the inputs of
+// the circuit.
+func Walk(circuit interface{}, handler func(visibility schema.Visibility, name string, v *Variable) error, opts ...schema.Option) error {
+	return walk(circuit, func(f schema.LeafInfo, v *Variable) error {
+		return handler(f.Visibility, f.FullName(), v)
+	}, opts...)
+}
+
+// walk is [Walk] with the full description of the variables given to the
+// handler, and the options of the walk.
+func walk(circuit interface{}, handler func(f schema.LeafInfo, v *Variable) error, opts ...schema.Option) error {
+	if reflect.ValueOf(circuit).Kind() != reflect.Ptr {
+		return errors.New("circuit must be a pointer")
+	}
+	_, err := schema.Walk(circuit, tVariable, func(f schema.LeafInfo, tInput reflect.Value) error {
+		if !tInput.CanSet() {
+			return errors.New("can't set val " + f.FullName())
+		}
+		if f.Visibility == schema.Unset {
+			return errors.New("can't set val " + f.FullName() + " visibility is unset")
+		}
+		return handler(f, tInput.Addr().Interface().(*Variable))
+	}, opts...)
+	return err
+}
+
+// AssertAllocated returns an error naming the inputs of the circuit which do
+// not hold a wire allocated by the compiler, for example the elements appended
+// to a slice after [Compile], or the inputs overwritten by Define with a
+// value. The circuit must be a pointer, and be the one passed to [Compile].
+// The constants and the fields tagged with "-" are not inputs, so they are not
+// checked.
+func AssertAllocated(circuit interface{}) error {
+	leaves, err := ParseCircuit(circuit)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, l := range leaves {
+		if !IsCanonical(*l.Value) {
+			errs = append(errs, fmt.Errorf("%s: input not allocated by the compiler", l.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CircuitSchema is the layout of the inputs of a circuit, see [Schema].
+type CircuitSchema struct {
+	Public []SchemaInput `json:"public"`
+	Secret []SchemaInput `json:"secret"`
+}
+
+// SchemaInput is an input of a circuit.
+type SchemaInput struct {
+	// Name is the full name of the input, as in [LeafInfo].
+	Name string `json:"name"`
+	// Index is the index of the input in the witness vector, the public
+	// inputs first and then the secret ones.
+	Index int `json:"index"`
+}
+
+// Schema returns the layout of the inputs of the circuit, with the names used
+// by the compiler, for example to assemble a witness in another language. The
+// circuit must be a pointer, with the sizes of its slices set as for
+// [Compile].
+//
+// The inputs are ordered as in the witness vector: the fields in the order of
+// their declaration and the elements of the slices and arrays in the order of
+// their indices. The elements of the maps are ordered by their keys.
+func Schema(circuit interface{}, opts ...schema.Option) (*CircuitSchema, error) {
+	leaves, err := ParseCircuit(circuit, opts...)
+	if err != nil {
+		return nil, err
+	}
+	s := &CircuitSchema{Public: []SchemaInput{}, Secret: []SchemaInput{}}
+	for _, l := range leaves {
+		if l.Visibility == schema.Public {
+			s.Public = append(s.Public, SchemaInput{Name: l.Name, Index: len(s.Public)})
+		} else {
+			s.Secret = append(s.Secret, SchemaInput{Name: l.Name})
+		}
+	}
+	for i := range s.Secret {
+		s.Secret[i].Index = len(s.Public) + i
+	}
+	return s, nil
+}
+
+// PublicInputs returns the public inputs of the circuit, in the order of their
+// allocation by the compiler, that is in the order of the public part of the
+// witness. The visibilities are resolved as for [ParseCircuit], with the
+// visibility of a parent applying to its untagged fields, so that a verifier
+// can assemble the public witness without the secret inputs. The circuit must
+// be a pointer.
+func PublicInputs(circuit interface{}, opts ...schema.Option) ([]LeafInfo, error) {
+	leaves, err := ParseCircuit(circuit, opts...)
+	if err != nil {
+		return nil, err
+	}
+	public := leaves[:0]
+	for _, l := range leaves {
+		if l.Visibility == schema.Public {
+			public = append(public, l)
+		}
+	}
+	return public, nil
+}
+
+// LayoutHash returns a hash of the layout of the inputs of the circuit: the
+// sequence of their full names and visibilities, in the order of
+// [ParseCircuit]. It does not depend on the values assigned to the inputs, so
+// that it detects when the layout of the inputs of a circuit changes, for
+// example to check that a witness was built for the same inputs. The circuit
+// must be a pointer.
+//
+// It is not a key of the compiled circuits: the circuits with the same inputs
+// and different parameters, held in the unexported fields or in Define,
+// have the same hash.
+//
+// The hash is the SHA-256 of the big-endian length of each name, followed by
+// the name and the visibility byte. The elements of the maps are walked in the
+// order of their keys. The names are built with the [schema.NameStrategy]
+// given in opts, the hashes computed with different strategies differ.
+func LayoutHash(circuit interface{}, opts ...schema.Option) ([32]byte, error) {
+	leaves, err := ParseCircuit(circuit, opts...)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	h := sha256.New()
+	var buf [8]byte
+	for _, l := range leaves {
+		binary.BigEndian.PutUint64(buf[:], uint64(len(l.Name)))
+		h.Write(buf[:])
+		h.Write([]byte(l.Name))
+		h.Write([]byte{byte(l.Visibility)})
+	}
+	var res [32]byte
+	h.Sum(res[:0])
+	return res, nil
+}
+
+// InputCounts returns the number of public and secret inputs of the circuit,
+// as allocated by the compiler, for example to size the witness buffers. The
+// circuit must be a pointer, see [ParseCircuit].
+func InputCounts(circuit interface{}) (public, secret int, err error) {
+	leaves, err := ParseCircuit(circuit)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, l := range leaves {
+		if l.Visibility == schema.Public {
+			public++
+		} else {
+			secret++
+		}
+	}
+	return public, secret, nil
+}
+
+// ForEachInput calls fn with the full name and the visibility of each input of
+// the circuit, in the order of [ParseCircuit]. It stops and returns the first
+// error returned by fn. The circuit is parsed before fn is called, so that fn
+// is not called on a circuit the compiler would reject.
+func ForEachInput(circuit interface{}, fn func(name string, v schema.Visibility) error, opts ...schema.Option) error {
+	leaves, err := ParseCircuit(circuit, opts...)
+	if err != nil {
+		return err
+	}
+	for _, l := range leaves {
+		if err := fn(l.Name, l.Visibility); err != nil {
+			return err
+		}
+	}
+	return nil
+}
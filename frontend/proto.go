@@ -0,0 +1,177 @@
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// ProtoMessage is implemented by the Go types generated for protobuf messages
+// by protoc-gen-go, in both versions of the protobuf API.
+type ProtoMessage interface {
+	ProtoMessage()
+}
+
+// AssignFromProto assigns the values of the fields of the protobuf message msg
+// to the fields of the circuit with the same name. A circuit field matches a
+// message field if they have the same Go name, or if the gnark tag name of the
+// circuit field is the protobuf name of the message field.
+//
+// Nested circuit structs are assigned from nested messages and circuit slices
+// and arrays from repeated fields. Slices of the circuit without elements are
+// allocated to the length of the repeated field, otherwise the lengths must
+// match. Integer fields are assigned as is, booleans as 0 or 1, bytes fields
+// as the big-endian encoded integer and string fields as strings (see
+// [Variable] for the accepted formats).
+//
+// Circuit fields which are ignored by the compiler (with tag "-") and
+// exported fields which are not variables, structs, slices or arrays are left
+// untouched. The method returns an error if a circuit variable has no matching
+// message field.
+func AssignFromProto(circuit Circuit, msg ProtoMessage) error {
+	dst := reflect.ValueOf(circuit)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return errors.New("circuit must be a non-nil pointer")
+	}
+	return assignFromProto(dst.Elem(), reflect.ValueOf(msg), "")
+}
+
+func assignFromProto(dst, src reflect.Value, path string) error {
+	for src.Kind() == reflect.Ptr || src.Kind() == reflect.Interface {
+		if src.IsNil() {
+			return fmt.Errorf("%s: missing value in message", path)
+		}
+		src = src.Elem()
+	}
+	if dst.Type() == tVariable {
+		v, err := protoScalar(src)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		dst.Set(reflect.ValueOf(v))
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Struct:
+		if src.Kind() != reflect.Struct {
+			return fmt.Errorf("%s: expected a message, got %s", path, src.Type())
+		}
+		for i := 0; i < dst.NumField(); i++ {
+			f := dst.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, _ := parseGnarkTag(f)
+			if name == string(schema.TagOptOmit) {
+				continue
+			}
+			if !holdsVariables(f.Type) {
+				continue
+			}
+			srcField, ok := protoField(src, f.Name, name)
+			if !ok {
+				return fmt.Errorf("%s: no message field for %s", path, f.Name)
+			}
+			if err := assignFromProto(dst.Field(i), srcField, joinPath(path, f.Name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			return fmt.Errorf("%s: expected a repeated field, got %s", path, src.Type())
+		}
+		if dst.Kind() == reflect.Slice && dst.Len() == 0 {
+			dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Len()))
+		}
+		if dst.Len() != src.Len() {
+			return fmt.Errorf("%s: expected %d elements, got %d", path, dst.Len(), src.Len())
+		}
+		for i := 0; i < dst.Len(); i++ {
+			if err := assignFromProto(dst.Index(i), src.Index(i), joinPath(path, fmt.Sprint(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// protoScalar converts the value of a scalar protobuf field to a value
+// assignable to a Variable.
+func protoScalar(v reflect.Value) (any, error) {
+	switch v.Kind() {
+	case reflect.Int32, reflect.Int64, reflect.Uint32, reflect.Uint64:
+		return v.Interface(), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return new(big.Int).SetBytes(v.Bytes()), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported message field type %s", v.Type())
+}
+
+// protoField returns the field of the message with the given Go name, or with
+// the given protobuf name.
+func protoField(msg reflect.Value, goName, protoName string) (reflect.Value, bool) {
+	if f, ok := msg.Type().FieldByName(goName); ok && f.IsExported() {
+		return msg.FieldByIndex(f.Index), true
+	}
+	if protoName == "" {
+		return reflect.Value{}, false
+	}
+	for i := 0; i < msg.NumField(); i++ {
+		f := msg.Type().Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		for _, opt := range strings.Split(f.Tag.Get("protobuf"), ",") {
+			if opt == "name="+protoName {
+				return msg.Field(i), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// parseGnarkTag returns the name given in the gnark tag of the field, if any.
+func parseGnarkTag(f reflect.StructField) (name string, ok bool) {
+	tag, ok := f.Tag.Lookup("gnark")
+	if !ok {
+		return "", false
+	}
+	name, _, _ = strings.Cut(tag, ",")
+	return strings.TrimSpace(name), true
+}
+
+// holdsVariables returns true if values of type t may contain variables.
+func holdsVariables(t reflect.Type) bool {
+	if t == tVariable {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Slice, reflect.Array:
+		return holdsVariables(t.Elem())
+	}
+	return false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
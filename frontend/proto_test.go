@@ -0,0 +1,95 @@
+package frontend_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// protoPoint and protoTransfer mimic the code generated by protoc-gen-go for
+//
+//	message Point { bytes x = 1; bytes y = 2; }
+//	message Transfer {
+//	  uint64 amount = 1;
+//	  Point sender = 2;
+//	  repeated int64 path = 3;
+//	  bool is_fee = 4;
+//	  string memo_hash = 5;
+//	}
+type protoPoint struct {
+	state         struct{}
+	sizeCache     int32
+	unknownFields []byte
+
+	X []byte `protobuf:"bytes,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y []byte `protobuf:"bytes,2,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+func (*protoPoint) ProtoMessage() {}
+
+type protoTransfer struct {
+	state         struct{}
+	sizeCache     int32
+	unknownFields []byte
+
+	Amount   uint64      `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Sender   *protoPoint `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Path     []int64     `protobuf:"varint,3,rep,packed,name=path,proto3" json:"path,omitempty"`
+	IsFee    bool        `protobuf:"varint,4,opt,name=is_fee,json=isFee,proto3" json:"is_fee,omitempty"`
+	MemoHash string      `protobuf:"bytes,5,opt,name=memo_hash,json=memoHash,proto3" json:"memo_hash,omitempty"`
+}
+
+func (*protoTransfer) ProtoMessage() {}
+
+type transferCircuit struct {
+	Amount frontend.Variable `gnark:",public"`
+	Sender struct {
+		X, Y frontend.Variable
+	}
+	Path []frontend.Variable
+	Fee  frontend.Variable `gnark:"is_fee"`
+	Memo frontend.Variable `gnark:"memo_hash"`
+}
+
+func (c *transferCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.Sender.X, c.Sender.Y), c.Amount)
+	sum := frontend.Variable(0)
+	for i := range c.Path {
+		sum = api.Add(sum, c.Path[i])
+	}
+	api.AssertIsEqual(sum, c.Memo)
+	api.AssertIsBoolean(c.Fee)
+	return nil
+}
+
+func TestAssignFromProto(t *testing.T) {
+	assert := test.NewAssert(t)
+	msg := &protoTransfer{
+		Amount:   6 * 256,
+		Sender:   &protoPoint{X: []byte{1, 0}, Y: []byte{6}},
+		Path:     []int64{-1, 2, 4},
+		IsFee:    true,
+		MemoHash: "5",
+	}
+	var assignment transferCircuit
+	assert.NoError(frontend.AssignFromProto(&assignment, msg))
+	assert.Equal(uint64(6*256), assignment.Amount)
+	assert.Equal(0, big.NewInt(256).Cmp(assignment.Sender.X.(*big.Int)))
+	assert.Equal([]frontend.Variable{int64(-1), int64(2), int64(4)}, assignment.Path)
+	assert.Equal(1, assignment.Fee)
+	assert.Equal("5", assignment.Memo)
+
+	circuit := transferCircuit{Path: make([]frontend.Variable, 3)}
+	assert.NoError(test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField()))
+
+	// mismatching lengths
+	wrongLen := transferCircuit{Path: make([]frontend.Variable, 2)}
+	assert.Error(frontend.AssignFromProto(&wrongLen, msg))
+
+	// missing nested message
+	msg.Sender = nil
+	assert.Error(frontend.AssignFromProto(&transferCircuit{}, msg))
+}
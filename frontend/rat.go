@@ -0,0 +1,21 @@
+package frontend
+
+import (
+	"errors"
+	"math/big"
+)
+
+// FromRat returns the constant n/d reduced modulo the scalar field of api,
+// where r = n/d. It allows to write coefficients such as 1/2 directly instead
+// of precomputing the field inverse. It returns an error if d is not invertible
+// in the scalar field.
+func FromRat(api API, r *big.Rat) (Variable, error) {
+	q := api.Compiler().Field()
+	d := new(big.Int).Mod(r.Denom(), q)
+	if d.ModInverse(d, q) == nil {
+		return nil, errors.New("denominator " + r.Denom().String() + " is not invertible in the scalar field")
+	}
+	n := new(big.Int).Mod(r.Num(), q)
+	n.Mul(n, d).Mod(n, q)
+	return n, nil
+}
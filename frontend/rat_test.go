@@ -0,0 +1,58 @@
+package frontend_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+)
+
+type ratCircuit struct {
+	X, Y, Z frontend.Variable
+}
+
+func (c *ratCircuit) Define(api frontend.API) error {
+	third, err := frontend.FromRat(api, big.NewRat(1, 3))
+	if err != nil {
+		return err
+	}
+	twoThirds, err := frontend.FromRat(api, big.NewRat(-2, -3))
+	if err != nil {
+		return err
+	}
+	// Z == X/3 + 2Y/3
+	api.AssertIsEqual(api.Add(api.Mul(third, c.X), api.Mul(twoThirds, c.Y)), c.Z)
+	return nil
+}
+
+func TestFromRat(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&ratCircuit{},
+		test.WithValidAssignment(&ratCircuit{X: 3, Y: 6, Z: 5}),
+		test.WithValidAssignment(&ratCircuit{X: 1, Y: 1, Z: 1}),
+		test.WithInvalidAssignment(&ratCircuit{X: 3, Y: 6, Z: 4}),
+		test.WithCurves(ecc.BN254))
+}
+
+type ratNotInvertibleCircuit struct {
+	X frontend.Variable
+}
+
+func (c *ratNotInvertibleCircuit) Define(api frontend.API) error {
+	coeff, err := frontend.FromRat(api, new(big.Rat).SetFrac(big.NewInt(1), api.Compiler().Field()))
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(api.Mul(coeff, c.X), 1)
+	return nil
+}
+
+func TestFromRatNotInvertible(t *testing.T) {
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &ratNotInvertibleCircuit{})
+	if err == nil {
+		t.Fatal("expected error for non-invertible denominator")
+	}
+}
@@ -16,6 +16,11 @@ limitations under the License.
 
 package schema
 
+import (
+	"fmt"
+	"reflect"
+)
+
 // Field represent a schema Field and is analogous to reflect.StructField (but simplified)
 type Field struct {
 	Name       string
@@ -23,8 +28,11 @@ type Field struct {
 	FullName   string
 	Visibility Visibility
 	Type       FieldType
-	SubFields  []Field // will be set only if it's a struct, or an array of struct
+	SubFields  []Field // will be set only if it's a struct, an array of struct, or a map (one per key)
 	ArraySize  int
+	Constants  []int // indices of the constant elements of an array of leaves, which are not inputs
+
+	keyKind reflect.Kind // kind of the keys of a map: reflect.Int64, reflect.Uint64 or reflect.String
 }
 
 // FieldType represents the type a field is allowed to have in a gnark Schema
@@ -34,6 +42,7 @@ const (
 	Leaf FieldType = iota
 	Array
 	Struct
+	Map
 )
 
 // Visibility encodes a Variable (or wire) visibility
@@ -48,6 +57,7 @@ const (
 	Virtual
 )
 
+// String returns the name of the visibility, as parsed by [ParseVisibility].
 func (v Visibility) String() string {
 	switch v {
 	case Internal:
@@ -62,3 +72,14 @@ func (v Visibility) String() string {
 
 	return "unset"
 }
+
+// ParseVisibility returns the visibility named s, one of "unset", "internal",
+// "secret", "public" or "virtual", as returned by [Visibility.String].
+func ParseVisibility(s string) (Visibility, error) {
+	for v := Unset; v <= Virtual; v++ {
+		if v.String() == s {
+			return v, nil
+		}
+	}
+	return Unset, fmt.Errorf("invalid visibility %q", s)
+}
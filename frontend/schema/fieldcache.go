@@ -0,0 +1,95 @@
+package schema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// fieldInfo is the parsed gnark tag of a struct field, see [structFields].
+type fieldInfo struct {
+	omit           bool // tagged with "-"
+	opaque         bool // of a type holding no leaf, see isOpaque
+	unexportedLeaf bool // unexported field holding leaves, which can not be set
+
+	nameTag    string // name set by the tag, may be invalid
+	validName  bool
+	unknown    []string // unknown tag options
+	visibility Visibility
+	commit     bool
+	static     bool
+	hasBits    bool
+	bits       string // value of the bits option
+	nbBits     int    // parsed bits option, 0 if invalid
+	hasRange   bool
+	rng        string // value of the range option
+	nbRange    int    // parsed range option, 0 if invalid
+	boolean    bool
+	embedded   bool
+}
+
+type fieldsKey struct {
+	t, target reflect.Type
+}
+
+// fieldsCache maps a struct type and the leaf type to the []fieldInfo of its
+// fields.
+var fieldsCache sync.Map
+
+// cacheFields disables fieldsCache when false, for the benchmarks.
+var cacheFields = true
+
+// structFields returns the parsed tags of the fields of the struct type t. The
+// tags are parsed once per type, a circuit with many elements of the same
+// struct type does not parse them for every element.
+func structFields(t, target reflect.Type) []fieldInfo {
+	key := fieldsKey{t, target}
+	if fields, ok := fieldsCache.Load(key); ok && cacheFields {
+		return fields.([]fieldInfo)
+	}
+	fields := make([]fieldInfo, t.NumField())
+	for i := range fields {
+		sf := t.Field(i)
+		f := &fields[i]
+		tag, ok := sf.Tag.Lookup(string(tagKey))
+		if ok && tag == string(TagOptOmit) {
+			f.omit = true
+			continue
+		}
+		if isOpaque(sf.Type) {
+			f.opaque = true
+			continue
+		}
+		f.unexportedLeaf = !sf.IsExported() && !sf.Anonymous && containsType(sf.Type, target)
+		f.validName = true
+		if ok && tag != "" {
+			var opts tagOptions
+			f.nameTag, opts = parseTag(tag)
+			f.validName = checkTagName(f.nameTag, "") == nil
+			f.unknown = opts.unknown()
+			opts = tagOptions(strings.TrimSpace(string(opts)))
+			f.visibility = opts.visibility()
+			f.commit = opts.contains(TagOptCommit)
+			f.static = opts.contains(TagOptStatic)
+			if bits, ok := opts.value(TagOptBits); ok {
+				f.hasBits, f.bits = true, bits
+				if n, err := strconv.Atoi(bits); err == nil && n > 0 {
+					f.nbBits = n
+				}
+			}
+			if rng, ok := opts.value(TagOptRange); ok {
+				f.hasRange, f.rng = true, rng
+				if n, err := strconv.Atoi(rng); err == nil && n > 0 {
+					f.nbRange = n
+				}
+			}
+			f.boolean = opts.contains(TagOptBoolean)
+		}
+		f.embedded = isEmbedded(sf, f.nameTag)
+	}
+	if cacheFields {
+		fieldsCache.Store(key, fields)
+	}
+	return fields
+}
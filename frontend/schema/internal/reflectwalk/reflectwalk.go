@@ -11,9 +11,11 @@
 package reflectwalk
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 )
 
 // InterfaceWalker implementations are able to handle interface values as they
@@ -36,6 +38,15 @@ type ArrayWalker interface {
 	ArrayElem(int, reflect.Value) error
 }
 
+// MapWalker implementations are able to handle map elements found within
+// complex structures. Map values are not addressable: MapElem is called with an
+// addressable copy of the value, which is written back in the map once walked.
+// The elements are walked in the order of their keys.
+type MapWalker interface {
+	Map(reflect.Value) error
+	MapElem(k, v reflect.Value) error
+}
+
 // StructWalker is an interface that has methods that are called for
 // structs when a Walk is done.
 type StructWalker interface {
@@ -168,7 +179,10 @@ func walk(v reflect.Value, w interface{}) (err error) {
 	case reflect.Array:
 		err = walkArray(v, w)
 		return
-	case reflect.Bool, reflect.Chan, reflect.Func, reflect.Int, reflect.String, reflect.Invalid, reflect.Map:
+	case reflect.Map:
+		err = walkMap(v, w)
+		return
+	case reflect.Bool, reflect.Chan, reflect.Func, reflect.Int, reflect.String, reflect.Invalid:
 		err = nil
 		return
 	default:
@@ -262,6 +276,101 @@ func walkArray(v reflect.Value, w interface{}) (err error) {
 	return nil
 }
 
+func walkMap(v reflect.Value, w interface{}) (err error) {
+	mw, ok := w.(MapWalker)
+	if !ok || !v.CanInterface() {
+		// without a MapWalker, or for a map in an unexported field, we can not
+		// write the walked values back
+		return nil
+	}
+	ew, ewok := w.(EnterExitWalker)
+	if ewok {
+		ew.Enter(Map)
+	}
+
+	if err := mw.Map(v); err != nil {
+		return err
+	}
+
+	keys := v.MapKeys()
+	SortKeys(keys)
+	for _, k := range keys {
+		elem := reflect.New(v.Type().Elem()).Elem()
+		elem.Set(v.MapIndex(k))
+
+		if err := mw.MapElem(k, elem); err != nil {
+			return err
+		}
+
+		if ewok {
+			ew.Enter(MapValue)
+		}
+
+		if err := walk(elem, w); err != nil && err != ErrSkipEntry {
+			return err
+		}
+
+		if ewok {
+			ew.Exit(MapValue)
+		}
+
+		v.SetMapIndex(k, elem)
+	}
+
+	if ewok {
+		ew.Exit(Map)
+	}
+
+	return nil
+}
+
+// SortKeys sorts the map keys so that the walk is deterministic. Integer keys
+// are sorted numerically, other keys by their string representation: their
+// text for the keys implementing encoding.TextMarshaler, which does not depend
+// on their address for pointers, and their default format otherwise.
+func SortKeys(keys []reflect.Value) {
+	if len(keys) == 0 {
+		return
+	}
+	switch keys[0].Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Int() < keys[j].Int() })
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Uint() < keys[j].Uint() })
+	case reflect.String:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	default:
+		strs := make([]string, len(keys))
+		for i := range keys {
+			strs[i] = keyString(keys[i])
+		}
+		sort.Sort(keysByString{keys, strs})
+	}
+}
+
+// keyString returns the string representation of the map key k.
+func keyString(k reflect.Value) string {
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok && !(k.Kind() == reflect.Pointer && k.IsNil()) {
+		if b, err := tm.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprint(k.Interface())
+}
+
+// keysByString sorts map keys by their precomputed string representations.
+type keysByString struct {
+	keys []reflect.Value
+	strs []string
+}
+
+func (s keysByString) Len() int           { return len(s.keys) }
+func (s keysByString) Less(i, j int) bool { return s.strs[i] < s.strs[j] }
+func (s keysByString) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.strs[i], s.strs[j] = s.strs[j], s.strs[i]
+}
+
 func walkStruct(v reflect.Value, w interface{}) (err error) {
 	ew, ewok := w.(EnterExitWalker)
 	if ewok {
@@ -285,14 +394,6 @@ func walkStruct(v reflect.Value, w interface{}) (err error) {
 		for i := 0; i < vt.NumField(); i++ {
 			sf := vt.Field(i)
 			f := v.FieldByIndex([]int{i})
-			if sf.Anonymous { // TODO @gbotrel check this
-				err = walk(f, w)
-				if err != nil && err != ErrSkipEntry {
-					return
-				}
-				continue
-			}
-
 			if sw, ok := w.(StructWalker); ok {
 				err = sw.StructField(sf, f)
 
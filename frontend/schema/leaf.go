@@ -7,7 +7,13 @@ import "reflect"
 type LeafInfo struct {
 	Visibility Visibility
 	FullName   func() string // in most instances, we don't need to actually evaluate the name.
+	Commit     bool          // the leaf is tagged (or has a parent tagged) with [TagOptCommit]
+	Static     bool          // the leaf is tagged (or has a parent tagged) with [TagOptStatic]
+	Bits       int           // expected bit width of the leaf value set with [TagOptBits], 0 if unset
+	Range      int           // bit width the leaf is constrained to with [TagOptRange], 0 if unset
+	Boolean    bool          // the leaf is constrained to be a boolean with [TagOptBoolean]
 	name       string
+	embedded   bool // the element is an embedded struct, without a name of its own
 }
 
 // LeafCount stores the number of secret and public interface of type target(reflect.Type)
@@ -25,3 +31,18 @@ type LeafHandler func(field LeafInfo, tValue reflect.Value) error
 type InitHook interface {
 	GnarkInitHook() // TODO @gbotrel find a better home for this
 }
+
+// A leaf value implementing ConstantLeaf is a compile time constant and not an
+// input of the circuit: the walk and the schema skip it.
+type ConstantLeaf interface {
+	GnarkConstant()
+}
+
+// isConstant returns true if the leaf value v is a [ConstantLeaf].
+func isConstant(v reflect.Value) bool {
+	if !v.CanInterface() {
+		return false
+	}
+	_, ok := v.Interface().(ConstantLeaf)
+	return ok
+}
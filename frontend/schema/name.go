@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// NameStrategy builds the full names of the circuit inputs, as returned by
+// [LeafInfo.FullName] and used in the constraint system, the witness
+// schema and error messages.
+//
+// The full name of an input is built by joining, from the top-level field
+// down, the names of the struct fields (or their name tags) and the indexes
+// of the array and slice elements on the path to reach it. The strategy may
+// also name the indexes, see [IndexNamer].
+type NameStrategy interface {
+	// Join returns the full name of the element name nested in the element
+	// parent. parent is never empty.
+	Join(parent, name string) string
+}
+
+// Separator is a [NameStrategy] joining the names with the separator. The
+// default strategy is Separator("_"), for example the input Accounts[3].Balance
+// is named "Accounts_3_Balance", and Separator(".") names it
+// "Accounts.3.Balance".
+type Separator string
+
+// Join implements [NameStrategy].
+func (s Separator) Join(parent, name string) string {
+	return parent + string(s) + name
+}
+
+// IndexNamer is an optional interface of a [NameStrategy] naming the elements
+// of the arrays and slices. Without it, the element i is named
+// strconv.Itoa(i).
+type IndexNamer interface {
+	// IndexName returns the name of the element i of an array or a slice of
+	// the given length.
+	IndexName(i, length int) string
+}
+
+// PaddedIndices is a [NameStrategy] which zero-pads the indexes of the
+// elements of arrays and slices to the number of digits of their length, and
+// joins the names with the embedded strategy. With
+// PaddedIndices{DefaultNameStrategy}, the elements of a [100]Node field are
+// named "Node_000" to "Node_099", so that sorting the names as strings, for
+// example the keys of a serialized witness, keeps the order of the indexes.
+type PaddedIndices struct {
+	NameStrategy
+}
+
+// IndexName implements [IndexNamer].
+func (PaddedIndices) IndexName(i, length int) string {
+	return fmt.Sprintf("%0*d", len(strconv.Itoa(length)), i)
+}
+
+// IndexName returns the name of the element i of an array or a slice of the
+// given length with the strategy s, see [IndexNamer].
+func IndexName(s NameStrategy, i, length int) string {
+	if n, ok := s.(IndexNamer); ok {
+		return n.IndexName(i, length)
+	}
+	return strconv.Itoa(i)
+}
+
+// DefaultNameStrategy is the name strategy used unless set otherwise with
+// [WithNameStrategy].
+const DefaultNameStrategy = Separator("_")
+
+// JoinName returns the full name of name nested in parent with the strategy
+// s. The name of a top-level element is its own name.
+func JoinName(s NameStrategy, parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return s.Join(parent, name)
+}
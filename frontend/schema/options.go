@@ -0,0 +1,131 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Option configures the parsing of a circuit by [Walk] and [New].
+type Option func(*config)
+
+type config struct {
+	strictTags       bool
+	strictUnexported bool
+	parallelism      int
+	names            NameStrategy
+
+	constantFields []Field         // schema fields marking the constant elements, see withConstants
+	constants      map[string]bool // full names of the constant elements, built from constantFields
+}
+
+// WithStrictTags makes [Walk] and [New] return an error for the unknown
+// options of the gnark struct tags, for example a misspelled "publik" which
+// would otherwise silently leave the field secret. The fields with unknown
+// options are skipped. By default, the unknown options are ignored.
+func WithStrictTags() Option {
+	return func(c *config) {
+		c.strictTags = true
+	}
+}
+
+// WithStrictUnexported makes [Walk] and [New] return an error for the
+// unexported fields holding leaves, which can not be set and would leave the
+// circuit without these inputs. By default, such fields are skipped with a
+// warning. The fields tagged with "-" are never reported.
+func WithStrictUnexported() Option {
+	return func(c *config) {
+		c.strictUnexported = true
+	}
+}
+
+// WithParallelism makes [New] parse the elements of the slices and arrays of
+// structs with up to n goroutines. The elements are merged in the order of
+// their indices, so that the schema and the names are the same as with a
+// sequential parsing. The [InitHook] of the elements may then be called
+// concurrently, and must be safe for it.
+//
+// [Walk] ignores this option: the order of the calls to its [LeafHandler]
+// defines the order of the inputs in the constraint system and the witness,
+// so the leaves are always walked sequentially. In particular, the option does
+// not speed up the compilation of a circuit, which walks it with [Walk].
+func WithParallelism(n int) Option {
+	return func(c *config) {
+		c.parallelism = n
+	}
+}
+
+// WithNameStrategy sets the strategy used by [Walk] and [New] to build the
+// full names of the inputs. If s is nil, the [DefaultNameStrategy] is used.
+//
+// The names of the inputs of a compiled circuit are the ones of its
+// compilation: the same strategy must be given wherever the inputs are named,
+// for example when compiling the circuit and when building its schema.
+func WithNameStrategy(s NameStrategy) Option {
+	return func(c *config) {
+		c.names = s
+	}
+}
+
+// withConstants makes [Walk] skip the elements of the arrays of leaves marked
+// as constants in fields.
+func withConstants(fields []Field) Option {
+	return func(c *config) {
+		c.constantFields = fields
+	}
+}
+
+// unexported reports the unexported field name of type t holding leaves: it
+// returns an error with [WithStrictUnexported], and prints a warning otherwise.
+func (c *config) unexported(name string, t reflect.Type) error {
+	if c.strictUnexported {
+		return fmt.Errorf("%s: unexported field of type %s, export it or tag it with %q", name, t, TagOptOmit)
+	}
+	fmt.Printf("ignoring unexported field: %s %s\n", name, t.String())
+	return nil
+}
+
+func newConfig(opts []Option) config {
+	var c config
+	for _, o := range opts {
+		o(&c)
+	}
+	if c.names == nil {
+		c.names = DefaultNameStrategy
+	}
+	if c.constantFields != nil {
+		c.constants = make(map[string]bool)
+		c.addConstants(Field{Type: Struct, SubFields: c.constantFields}, "")
+	}
+	return c
+}
+
+// addConstants records the full names of the constant elements of the arrays
+// of leaves held by the field f named full.
+func (c *config) addConstants(f Field, full string) {
+	switch f.Type {
+	case Struct, Map:
+		for _, sf := range f.SubFields {
+			c.addConstants(sf, c.getFullName(full, sf.Name, sf.NameTag))
+		}
+	case Array:
+		for _, i := range f.Constants {
+			c.constants[c.joinName(full, c.indexName(i, f.ArraySize))] = true
+		}
+		if len(f.SubFields) > 0 {
+			for j := 0; j < f.ArraySize; j++ {
+				c.addConstants(f.SubFields[0], c.joinName(full, c.indexName(j, f.ArraySize)))
+			}
+		}
+	}
+}
+
+// joinName returns the full name of name nested in parent.
+func (c *config) joinName(parent, name string) string {
+	return JoinName(c.names, parent, name)
+}
+
+// indexName returns the name of the element i of an array or a slice of the
+// given length.
+func (c *config) indexName(i, length int) string {
+	return IndexName(c.names, i, length)
+}
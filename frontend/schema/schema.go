@@ -17,11 +17,15 @@ limitations under the License.
 package schema
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/consensys/gnark/frontend/schema/internal/reflectwalk"
 )
 
 // Schema represents the structure of a gnark circuit (/ witness)
@@ -29,22 +33,40 @@ type Schema struct {
 	Fields   []Field
 	NbPublic int
 	NbSecret int
+
+	names NameStrategy
 }
 
 // New builds a schema.Schema walking through the provided interface (a circuit structure).
 //
 // schema.Walk performs better and should be used when possible.
-func New(circuit interface{}, tLeaf reflect.Type) (*Schema, error) {
+func New(circuit interface{}, tLeaf reflect.Type, opts ...Option) (*Schema, error) {
 	// note circuit is of type interface{} instead of frontend.Circuit to avoid import cycle
 	// same for tLeaf it is in practice always frontend.Variable
 
+	// the fields of a circuit passed by value are not addressable and would be
+	// silently skipped.
+	if reflect.ValueOf(circuit).Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("schema: expected pointer to circuit, got %s", reflect.TypeOf(circuit))
+	}
+
 	var nbPublic, nbSecret int
-	fields, err := parse(nil, circuit, tLeaf, "", "", "", Unset, &nbPublic, &nbSecret)
+	c := newConfig(opts)
+	fields, err := parse(nil, reflect.ValueOf(circuit), tLeaf, "", "", "", Unset, &nbPublic, &nbSecret, &parseState{seen: make(map[string]int), config: c})
 	if err != nil {
 		return nil, err
 	}
 
-	return &Schema{Fields: fields, NbPublic: nbPublic, NbSecret: nbSecret}, nil
+	return &Schema{Fields: fields, NbPublic: nbPublic, NbSecret: nbSecret, names: c.names}, nil
+}
+
+// NameStrategy returns the strategy naming the inputs of the schema, as set
+// by [WithNameStrategy] when building it.
+func (s Schema) NameStrategy() NameStrategy {
+	if s.names == nil {
+		return DefaultNameStrategy
+	}
+	return s.names
 }
 
 // Instantiate builds a concrete type using reflect matching the provided schema
@@ -66,13 +88,24 @@ func (s Schema) Instantiate(leafType reflect.Type, omitEmptyTag ...bool) interfa
 	// now create the corresponding type
 	typ := reflect.StructOf(is)
 
-	// instantiate the type
+	// instantiate the type, the maps hold an element per key of the schema
 	v := reflect.New(typ).Elem()
+	if hasMaps(s.Fields) {
+		fillMaps(v, s.Fields)
+	}
 
 	// return interface
 	return v.Addr().Interface()
 }
 
+// InstanceOptions returns the options to walk an instance of the schema built
+// by [Schema.Instantiate]: its inputs are named with the strategy of the
+// schema, and the elements of the arrays of leaves marked as constants (see
+// [Field.Constants]) are skipped, as they are in the circuit.
+func (s Schema) InstanceOptions() []Option {
+	return []Option{WithNameStrategy(s.NameStrategy()), withConstants(s.Fields)}
+}
+
 // WriteSequence writes the expected sequence order of the witness on provided writer
 // witness elements are identified by their tag name, or if unset, struct & field name
 //
@@ -91,7 +124,7 @@ func (s Schema) WriteSequence(w io.Writer) error {
 		}
 		return nil
 	}
-	if _, err := Walk(instance, reflect.TypeOf(a), collectHandler); err != nil {
+	if _, err := Walk(instance, reflect.TypeOf(a), collectHandler, s.InstanceOptions()...); err != nil {
 		return err
 	}
 
@@ -138,12 +171,90 @@ func toStructField(fields []Field, leafType reflect.Type, omitEmpty bool) []refl
 			r[i].Type = arrayElementType(f.ArraySize, f.SubFields, leafType, omitEmpty)
 		case Struct:
 			r[i].Type = reflect.StructOf(toStructField(f.SubFields, leafType, omitEmpty))
+		case Map:
+			r[i].Type = mapType(f, leafType, omitEmpty)
 		}
 	}
 
 	return r
 }
 
+// mapType returns the type of the map field f. The elements have the type of
+// the first one.
+func mapType(f Field, leafType reflect.Type, omitEmpty bool) reflect.Type {
+	var tKey reflect.Type
+	switch f.keyKind {
+	case reflect.Int64:
+		tKey = reflect.TypeOf(int64(0))
+	case reflect.Uint64:
+		tKey = reflect.TypeOf(uint64(0))
+	default:
+		tKey = reflect.TypeOf("")
+	}
+	var tElem reflect.Type
+	switch e := f.SubFields[0]; e.Type {
+	case Leaf:
+		tElem = leafType
+	case Array:
+		tElem = arrayElementType(e.ArraySize, e.SubFields, leafType, omitEmpty)
+	case Struct:
+		tElem = reflect.StructOf(toStructField(e.SubFields, leafType, omitEmpty))
+	case Map:
+		tElem = mapType(e, leafType, omitEmpty)
+	}
+	return reflect.MapOf(tKey, tElem)
+}
+
+// hasMaps returns true if a map is held by fields, at any nesting level.
+func hasMaps(fields []Field) bool {
+	for _, f := range fields {
+		if f.Type == Map || hasMaps(f.SubFields) {
+			return true
+		}
+	}
+	return false
+}
+
+// fillMaps sets the maps held by the struct v, built from fields, to an
+// element per key of the schema.
+func fillMaps(v reflect.Value, fields []Field) {
+	for i, f := range fields {
+		fillField(v.Field(i), f)
+	}
+}
+
+func fillField(v reflect.Value, f Field) {
+	if !hasMaps([]Field{f}) {
+		return
+	}
+	switch f.Type {
+	case Struct:
+		fillMaps(v, f.SubFields)
+	case Array:
+		for j := 0; j < v.Len(); j++ {
+			fillField(v.Index(j), f.SubFields[0])
+		}
+	case Map:
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(f.SubFields)))
+		for _, e := range f.SubFields {
+			k := reflect.New(v.Type().Key()).Elem()
+			switch f.keyKind {
+			case reflect.Int64:
+				n, _ := strconv.ParseInt(e.Name, 10, 64)
+				k.SetInt(n)
+			case reflect.Uint64:
+				n, _ := strconv.ParseUint(e.Name, 10, 64)
+				k.SetUint(n)
+			default:
+				k.SetString(e.Name)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			fillField(elem, e)
+			v.SetMapIndex(k, elem)
+		}
+	}
+}
+
 func arrayElementType(n int, fields []Field, leafType reflect.Type, omitEmpty bool) reflect.Type {
 	// we know parent is an array.
 	// we check first element of fields
@@ -160,6 +271,8 @@ func arrayElementType(n int, fields []Field, leafType reflect.Type, omitEmpty bo
 		return reflect.ArrayOf(n, reflect.StructOf(toStructField(fields[0].SubFields, leafType, omitEmpty)))
 	case Array:
 		return reflect.ArrayOf(n, arrayElementType(fields[0].ArraySize, fields[0].SubFields, leafType, omitEmpty))
+	case Map:
+		return reflect.ArrayOf(n, mapType(fields[0], leafType, omitEmpty))
 	}
 	panic("invalid array type")
 }
@@ -184,19 +297,39 @@ func structTag(baseNameTag string, visibility Visibility, omitEmpty bool) reflec
 	return reflect.StructTag(fmt.Sprintf("gnark:\"%s,%s\" json:\"%s%s\"", baseNameTag, visibility.String(), baseNameTag, sOmitEmpty))
 }
 
-// parentFullName: the name of parent with its ancestors separated by "_"
+// parentFullName: the name of parent with its ancestors, joined with the [NameStrategy] of st
 // parentGoName: the name of parent (Go struct definition)
 // parentTagName: may be empty, set if a struct tag with name is set
-func parse(r []Field, input interface{}, target reflect.Type, parentFullName, parentGoName, parentTagName string, parentVisibility Visibility, nbPublic, nbSecret *int) ([]Field, error) {
-	tValue := reflect.ValueOf(input)
+func parse(r []Field, tValue reflect.Value, target reflect.Type, parentFullName, parentGoName, parentTagName string, parentVisibility Visibility, nbPublic, nbSecret *int, st *parseState) ([]Field, error) {
 
-	// get pointed value if needed
+	// get pointed value if needed. Pointers are followed at each level, nil
+	// ones to a leaf or to a struct holding leaves are set to a new value when
+	// possible.
 	if tValue.Kind() == reflect.Ptr {
 		tValue = tValue.Elem()
 	}
+	for tValue.Kind() == reflect.Ptr {
+		if tValue.IsNil() {
+			if !tValue.CanSet() || !allocOnNil(tValue.Type(), target) {
+				return r, nil
+			}
+			tValue.Set(reflect.New(tValue.Type().Elem()))
+		}
+		tValue = tValue.Elem()
+	}
 
 	// stop condition
 	if tValue.Type() == target {
+		if isConstant(tValue) {
+			return r, nil
+		}
+		// the fields of embedded structs share the namespace of their parent
+		if st.seen == nil {
+			// parsed in a worker, the names are checked when merging
+			st.names = append(st.names, leafName{parentFullName, *nbPublic + *nbSecret})
+		} else if err := st.checkName(parentFullName, *nbPublic+*nbSecret); err != nil {
+			return r, err
+		}
 		f := Field{
 			Name:       parentGoName,
 			NameTag:    parentTagName,
@@ -217,20 +350,54 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 		return append(r, f), nil
 	}
 
-	// struct
+	// interface which is not a leaf, for example a gadget: its dynamic value
+	// is parsed, nil interfaces are ignored
+	if tValue.Kind() == reflect.Interface {
+		if isAPI(tValue.Type()) {
+			return r, nil
+		}
+		if tValue.IsNil() {
+			if canHold(tValue.Type(), target) {
+				fmt.Printf("ignoring nil interface: %s %s\n", parentGoName, tValue.Type().String())
+			}
+			return r, nil
+		}
+		if err := checkDynamicValue(parentFullName, tValue, target); err != nil {
+			return r, err
+		}
+		elem := tValue.Elem()
+		if elem.Kind() != reflect.Ptr {
+			// the value held by the interface holds no leaves
+			return r, nil
+		}
+		return parse(r, elem, target, parentFullName, parentGoName, parentTagName, parentVisibility, nbPublic, nbSecret, st)
+	}
+
+	// struct, the opaque ones hold no leaves
+	if tValue.Kind() == reflect.Struct && isOpaque(tValue.Type()) {
+		return r, nil
+	}
 	if tValue.Kind() == reflect.Struct {
 		var subFields []Field
+		// the errors of the fields do not stop the parsing of the others
+		var errs []error
 
-		// get visible fields
-		fields := reflect.VisibleFields(tValue.Type())
-
-		for _, f := range fields {
+		// the fields promoted from embedded structs are parsed with them
+		for i := 0; i < tValue.NumField(); i++ {
+			f := tValue.Type().Field(i)
 			// check if the gnark tag is set
 			tag, ok := f.Tag.Lookup(string(tagKey))
 			if ok && tag == string(TagOptOmit) {
 				continue // skipping "-"
 			}
 
+			if !f.IsExported() && !f.Anonymous && containsType(f.Type, target) {
+				if err := st.unexported(st.getFullName(parentFullName, f.Name, ""), f.Type); err != nil {
+					errs = append(errs, err)
+				}
+				continue
+			}
+
 			// default visibility is Unset
 			visibility := Unset
 
@@ -242,15 +409,22 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 				// gnark tag is set
 				var opts tagOptions
 				nameTag, opts = parseTag(tag)
-				if !isValidTag(nameTag) {
-					nameTag = ""
+				if err := checkTagName(nameTag, st.getFullName(parentFullName, f.Name, "")); err != nil {
+					errs = append(errs, err)
+					continue
 				}
-				opts = tagOptions(strings.TrimSpace(string(opts)))
-				switch {
-				case opts.contains(TagOptSecret):
-					visibility = Secret
-				case opts.contains(TagOptPublic):
-					visibility = Public
+				if unknown := opts.unknown(); st.strictTags && len(unknown) > 0 {
+					for _, opt := range unknown {
+						errs = append(errs, fmt.Errorf("unknown gnark tag option %q on field %s", opt, st.getFullName(parentFullName, f.Name, "")))
+					}
+					continue
+				}
+				// commit, static, bits, range and boolean options do not
+				// change the visibility
+				opts = tagOptions(strings.TrimSpace(string(opts))).without(TagOptCommit, TagOptStatic, TagOptBits, TagOptRange, TagOptBoolean)
+				switch v := opts.visibility(); {
+				case v != Unset:
+					visibility = v
 				case opts == "" && parentFullName == "":
 					// our promise is to set visibility to secret for empty-tagged elements.
 					visibility = Secret
@@ -266,35 +440,49 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 				case opts.contains(TagOptInherit):
 					// but we can not inherit the visibility for top-level
 					// elements. Return an error.
-					return r, fmt.Errorf("can not inherit visibility for top-level element %s", getFullName(parentGoName, name, nameTag))
+					errs = append(errs, fmt.Errorf("can not inherit visibility for top-level element %s", st.getFullName(parentGoName, name, nameTag)))
+					continue
 				default:
-					return r, fmt.Errorf("invalid gnark struct tag option on %s. must be \"public\", \"secret\" or \"-\"", getFullName(parentGoName, name, nameTag))
+					errs = append(errs, fmt.Errorf("invalid gnark struct tag option on %s. must be \"public\", \"secret\" or \"-\"", st.getFullName(parentGoName, name, nameTag)))
+					continue
 				}
 			}
 
-			if ((parentVisibility == Public) && (visibility == Secret)) ||
-				((parentVisibility == Secret) && (visibility == Public)) {
-				return r, fmt.Errorf("conflicting visibility. %s (%s) has a parent with different visibility attribute", getFullName(parentGoName, name, nameTag), visibility.String())
-			}
-
-			// inherit parent visibility
+			// inherit parent visibility, unless the field has an explicit tag
 			if visibility == Unset {
 				visibility = parentVisibility
 			}
 
 			fValue := tValue.FieldByIndex(f.Index)
 
-			if fValue.CanAddr() && fValue.Addr().CanInterface() {
-				value := fValue.Addr().Interface()
-				if ih, hasInitHook := value.(InitHook); hasInitHook {
+			if !fValue.CanAddr() {
+				continue
+			}
+			if fValue.Addr().CanInterface() {
+				if ih, hasInitHook := fValue.Addr().Interface().(InitHook); hasInitHook {
 					ih.GnarkInitHook()
 				}
-				var err error
-				subFields, err = parse(subFields, value, target, getFullName(parentFullName, name, nameTag), name, nameTag, visibility, nbPublic, nbSecret)
+			} else if !f.Anonymous {
+				continue
+			}
+			if isEmbedded(f, nameTag) {
+				// the fields of the embedded struct are in our namespace. The
+				// struct is parsed as a root, which returns its fields.
+				embedded, err := parse(nil, fValue.Addr(), target, parentFullName, "", "", visibility, nbPublic, nbSecret, st)
 				if err != nil {
-					return r, err
+					errs = append(errs, err)
 				}
+				subFields = append(subFields, embedded...)
+				continue
 			}
+			var err error
+			subFields, err = parse(subFields, fValue.Addr(), target, st.getFullName(parentFullName, name, nameTag), name, nameTag, visibility, nbPublic, nbSecret, st)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			return r, errors.Join(errs...)
 		}
 
 		if parentGoName == "" {
@@ -321,7 +509,9 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 
 	if tValue.Kind() == reflect.Slice || tValue.Kind() == reflect.Array {
 		if tValue.Len() == 0 {
-			if reflect.SliceOf(target) == tValue.Type() {
+			// a nil slice was likely not initialized before the compilation,
+			// while an empty slice or array is intentional.
+			if reflect.SliceOf(target) == tValue.Type() && tValue.IsNil() {
 				fmt.Printf("ignoring uninitialized slice: %s %s\n", parentGoName, reflect.SliceOf(target).String())
 			}
 			return r, nil
@@ -330,20 +520,31 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 		// []frontend.Variable
 		// [n]frontend.Variable
 		// [] / [n] of something else.
-		if reflect.SliceOf(target) == tValue.Type() || reflect.ArrayOf(tValue.Len(), target) == tValue.Type() {
+		if tElem := tValue.Type().Elem(); tElem == target || tElem == reflect.PointerTo(target) {
 			// if parentVisibility == Unset {
 			// 	parentVisibility = Secret // default visibility to Secret
 			// }
 
+			// the constant elements keep their slot in the array, and are
+			// marked as such
+			var constants []int
 			for j := 0; j < tValue.Len(); j++ {
 				val := tValue.Index(j)
 				if val.CanAddr() && val.Addr().CanInterface() {
-					fqn := getFullName(parentFullName, strconv.Itoa(j), "")
-					if _, err := parse(nil, val.Addr().Interface(), target, fqn, fqn, parentTagName, parentVisibility, nbPublic, nbSecret); err != nil {
+					fqn := st.getFullName(parentFullName, st.indexName(j, tValue.Len()), "")
+					leaves, err := parse(nil, val.Addr(), target, fqn, fqn, parentTagName, parentVisibility, nbPublic, nbSecret, st)
+					if err != nil {
 						return nil, err
 					}
+					if len(leaves) == 0 {
+						constants = append(constants, j)
+					}
 				}
 			}
+			if len(constants) == tValue.Len() {
+				// only constants
+				return r, nil
+			}
 
 			return append(r, Field{
 				Name:       parentGoName,
@@ -351,26 +552,35 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 				Type:       Array,
 				Visibility: parentVisibility,
 				ArraySize:  tValue.Len(),
+				Constants:  constants,
 			}), nil
 		}
 
 		// we have a slice / array of things that may contain variables
 		var subFields []Field
 		var err error
-		for j := 0; j < tValue.Len(); j++ {
-			val := tValue.Index(j)
-			if val.CanAddr() && val.Addr().CanInterface() {
-				fqn := getFullName(parentFullName, strconv.Itoa(j), "")
-				ival := val.Addr().Interface()
-				if ih, hasInitHook := ival.(InitHook); hasInitHook {
-					ih.GnarkInitHook()
-				}
-				subFields, err = parse(subFields, ival, target, fqn, fqn, parentTagName, parentVisibility, nbPublic, nbSecret)
-				if err != nil {
-					return nil, err
+		var errs []error
+		if st.parallelism > 1 && st.seen != nil && tValue.Len() > 1 {
+			subFields, errs = parseElemsParallel(tValue, target, parentFullName, parentTagName, parentVisibility, nbPublic, nbSecret, st)
+		} else {
+			for j := 0; j < tValue.Len(); j++ {
+				val := tValue.Index(j)
+				if val.CanAddr() && val.Addr().CanInterface() {
+					fqn := st.getFullName(parentFullName, st.indexName(j, tValue.Len()), "")
+					ival := val.Addr().Interface()
+					if ih, hasInitHook := ival.(InitHook); hasInitHook {
+						ih.GnarkInitHook()
+					}
+					subFields, err = parse(subFields, val.Addr(), target, fqn, fqn, parentTagName, parentVisibility, nbPublic, nbSecret, st)
+					if err != nil {
+						errs = append(errs, err)
+					}
 				}
 			}
 		}
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
 		if len(subFields) == 0 {
 			// nothing to add
 			return r, nil
@@ -386,18 +596,192 @@ func parse(r []Field, input interface{}, target reflect.Type, parentFullName, pa
 
 	}
 
+	// map, the elements are named by their keys and parsed in the order in
+	// which Walk visits them. The maps whose elements can not hold leaves are
+	// skipped.
+	if tValue.Kind() == reflect.Map {
+		if !containsType(tValue.Type().Elem(), target) {
+			return r, nil
+		}
+		if tValue.IsNil() {
+			fmt.Printf("ignoring uninitialized map: %s %s\n", parentGoName, tValue.Type().String())
+			return r, nil
+		}
+		if tValue.Len() == 0 {
+			return r, nil
+		}
+		tKey := tValue.Type().Key()
+		if !isNameableKey(tKey) {
+			return r, fmt.Errorf("%s: unsupported map key type %s, must be a string, an integer or implement encoding.TextMarshaler", parentFullName, tKey)
+		}
+		keys := tValue.MapKeys()
+		reflectwalk.SortKeys(keys)
+
+		var subFields []Field
+		var errs []error
+		for _, k := range keys {
+			name, err := mapKeyName(k)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", parentFullName, err))
+				continue
+			}
+			// the map values are not addressable, a copy is parsed and
+			// written back
+			val := reflect.New(tValue.Type().Elem())
+			val.Elem().Set(tValue.MapIndex(k))
+			subFields, err = parse(subFields, val, target, st.getFullName(parentFullName, name, ""), name, "", parentVisibility, nbPublic, nbSecret, st)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			tValue.SetMapIndex(k, val.Elem())
+		}
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+		if len(subFields) == 0 {
+			// nothing to add
+			return r, nil
+		}
+		return append(r, Field{
+			Name:       parentGoName,
+			NameTag:    parentTagName,
+			Type:       Map,
+			SubFields:  subFields,
+			Visibility: parentVisibility,
+			keyKind:    keyKind(tKey),
+		}), nil
+	}
+
 	return r, nil
 }
 
+// keyKind returns the kind of the keys of the instantiated maps with keys of
+// type t, such that Walk visits them in the same order: the integers are
+// sorted numerically, the other keys by their name. The integer keys
+// implementing encoding.TextMarshaler are named by their text, and kept as
+// strings.
+func keyKind(t reflect.Type) reflect.Kind {
+	if t.Kind() != reflect.String && t.Implements(tTextMarshaler) {
+		return reflect.String
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int64
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.Uint64
+	}
+	return reflect.String
+}
+
+// isEmbedded returns true if the fields of the struct field f are in the
+// namespace of its parent: f is an anonymous struct (or pointer to a struct)
+// field and its gnark tag does not set a name.
+func isEmbedded(f reflect.StructField, nameTag string) bool {
+	if !f.Anonymous || nameTag != "" {
+		return false
+	}
+	t := f.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// parseState is the state shared by the recursive calls of parse.
+type parseState struct {
+	seen  map[string]int // full names of the parsed leaves, and their index
+	names []leafName     // leaves parsed by a worker, which has no seen map
+	config
+}
+
+// leafName is the full name of a leaf parsed by a worker, and its index
+// relative to the first leaf of the element parsed by the worker.
+type leafName struct {
+	name  string
+	index int
+}
+
+// checkName returns an error if a leaf with the same full name was already
+// parsed, and records the name at index otherwise.
+func (st *parseState) checkName(name string, index int) error {
+	if first, ok := st.seen[name]; ok {
+		return fmt.Errorf("duplicate variable name %q at input %d, first at input %d", name, index, first)
+	}
+	st.seen[name] = index
+	return nil
+}
+
+// parseElemsParallel parses the elements of the slice or array tValue with
+// st.parallelism workers, and merges their fields, counts and names in the
+// order of the elements as the sequential parsing does.
+func parseElemsParallel(tValue reflect.Value, target reflect.Type, parentFullName, parentTagName string, parentVisibility Visibility, nbPublic, nbSecret *int, st *parseState) ([]Field, []error) {
+	type result struct {
+		fields             []Field
+		nbPublic, nbSecret int
+		names              []leafName
+		err                error
+	}
+	results := make([]result, tValue.Len())
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < st.parallelism && w < len(results); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				val := tValue.Index(j)
+				if !val.CanAddr() || !val.Addr().CanInterface() {
+					continue
+				}
+				fqn := st.getFullName(parentFullName, st.indexName(j, tValue.Len()), "")
+				if ih, hasInitHook := val.Addr().Interface().(InitHook); hasInitHook {
+					ih.GnarkInitHook()
+				}
+				// the elements are parsed sequentially in the worker
+				local := parseState{config: st.config}
+				local.parallelism = 0
+				res := &results[j]
+				res.fields, res.err = parse(nil, val.Addr(), target, fqn, fqn, parentTagName, parentVisibility, &res.nbPublic, &res.nbSecret, &local)
+				res.names = local.names
+			}
+		}()
+	}
+	for j := range results {
+		jobs <- j
+	}
+	close(jobs)
+	wg.Wait()
+
+	var subFields []Field
+	var errs []error
+	// the duplicates are counted by the workers but not by the sequential
+	// parsing, which stops at them
+	skipped := 0
+	for _, res := range results {
+		index := *nbPublic + *nbSecret - skipped
+		for _, n := range res.names {
+			if err := st.checkName(n.name, index+n.index); err != nil {
+				errs = append(errs, err)
+				index--
+				skipped++
+			}
+		}
+		if res.err != nil {
+			errs = append(errs, res.err)
+		}
+		subFields = append(subFields, res.fields...)
+		*nbPublic += res.nbPublic
+		*nbSecret += res.nbSecret
+	}
+	return subFields, errs
+}
+
 // specify parentName, name and tag
 // returns fully qualified name
-func getFullName(parentFullName, name, tagName string) string {
+func (c *config) getFullName(parentFullName, name, tagName string) string {
 	n := name
 	if tagName != "" {
 		n = tagName
 	}
-	if parentFullName == "" {
-		return n
-	}
-	return parentFullName + "_" + n
+	return c.joinName(parentFullName, n)
 }
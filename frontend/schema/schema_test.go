@@ -19,7 +19,12 @@ package schema
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -121,6 +126,13 @@ func TestSchemaCorrectness(t *testing.T) {
 	assert.Equal(expectedBuf.String(), instanceBuf.String())
 }
 
+func TestSchemaNotPointer(t *testing.T) {
+	assert := require.New(t)
+
+	_, err := New(Circuit{Z: make([]variable, 3)}, tVariable)
+	assert.EqualError(err, "schema: expected pointer to circuit, got schema.Circuit")
+}
+
 type circuitInherit1 struct {
 	X variable `gnark:"x"`
 	Y struct {
@@ -165,6 +177,49 @@ func TestSchemaInherit(t *testing.T) {
 	}
 }
 
+type circuitNestedVisibility struct {
+	Account struct {
+		Balance variable
+		ID      variable `gnark:",public"`
+	} `gnark:",secret"`
+	Header struct {
+		Root variable
+		Salt variable `gnark:",secret"`
+		Deep struct {
+			K variable
+		}
+	} `gnark:",public"`
+}
+
+func TestSchemaNestedVisibility(t *testing.T) {
+	assert := require.New(t)
+
+	var c circuitNestedVisibility
+	var public, secret []string
+	count, err := Walk(&c, tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+		if leaf.Visibility == Public {
+			public = append(public, leaf.FullName())
+		} else {
+			secret = append(secret, leaf.FullName())
+		}
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(3, count.Public)
+	assert.Equal(2, count.Secret)
+	assert.Equal([]string{"Account_ID", "Header_Root", "Header_Deep_K"}, public)
+	assert.Equal([]string{"Account_Balance", "Header_Salt"}, secret)
+
+	s, err := New(&c, tVariable)
+	assert.NoError(err)
+	assert.Equal(3, s.NbPublic)
+	assert.Equal(2, s.NbSecret)
+
+	assert.Equal(Public, s.Fields[0].SubFields[1].Visibility)
+	assert.Equal(Secret, s.Fields[1].SubFields[1].Visibility)
+	assert.Equal(Public, s.Fields[1].SubFields[2].SubFields[0].Visibility)
+}
+
 type initableVariable struct {
 	Val []variable
 }
@@ -260,8 +315,1049 @@ func BenchmarkArrayOfSliceOfStructSchema(b *testing.B) {
 
 }
 
+// BenchmarkStructFields walks a circuit of 50k variables in tagged struct
+// fields, whose tags are parsed once per struct type, or once per element
+// without the cache.
+func BenchmarkStructFields(b *testing.B) {
+	type point struct {
+		X variable `gnark:"x,public"`
+		Y variable `gnark:"y,secret,bits=64"`
+	}
+	t1 := struct {
+		Points []point
+	}{make([]point, 25000)}
+	for _, cached := range []bool{true, false} {
+		b.Run(fmt.Sprintf("cached=%t", cached), func(b *testing.B) {
+			cacheFields = cached
+			defer func() { cacheFields = true }()
+			for i := 0; i < b.N; i++ {
+				count, err := Walk(&t1, tVariable, nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if count.Public+count.Secret != 50000 {
+					b.Fatal("unexpected number of leaves")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParallelSchema(b *testing.B) {
+	circuit := newParallelCircuit(1 << 14)
+	for _, parallelism := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("parallelism=%d", parallelism), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := New(circuit, tVariable, WithParallelism(parallelism)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 var tVariable reflect.Type
 
 func init() {
 	tVariable = reflect.ValueOf(struct{ A variable }{}).FieldByName("A").Type()
 }
+
+type circuitTagOpts struct {
+	A variable `gnark:",secret,commit"`
+	B variable `gnark:",static"`
+	C struct {
+		D [2]variable
+	} `gnark:",public,static"`
+	E variable
+}
+
+func TestSchemaTagOpts(t *testing.T) {
+	assert := require.New(t)
+
+	var c circuitTagOpts
+	s, err := New(&c, tVariable)
+	assert.NoError(err)
+	assert.Equal(2, s.NbPublic)
+	assert.Equal(3, s.NbSecret)
+
+	var commit, static []string
+	_, err = Walk(&c, tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+		if leaf.Commit {
+			commit = append(commit, leaf.FullName())
+		}
+		if leaf.Static {
+			static = append(static, leaf.FullName())
+		}
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"A"}, commit)
+	assert.Equal([]string{"B", "C_D_0", "C_D_1"}, static)
+}
+
+type circuitConstraintTags struct {
+	A variable `gnark:"a,public,range=64"`
+	B struct {
+		C [2]variable
+		D variable `gnark:",range=8"`
+	} `gnark:",boolean"`
+}
+
+func TestSchemaConstraintTags(t *testing.T) {
+	assert := require.New(t)
+
+	var c circuitConstraintTags
+	s, err := New(&c, tVariable, WithStrictTags())
+	assert.NoError(err)
+	assert.Equal(1, s.NbPublic)
+	assert.Equal(3, s.NbSecret)
+
+	ranges := make(map[string]int)
+	var booleans []string
+	_, err = Walk(&c, tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+		if leaf.Range > 0 {
+			ranges[leaf.FullName()] = leaf.Range
+		}
+		if leaf.Boolean {
+			booleans = append(booleans, leaf.FullName())
+		}
+		return nil
+	}, WithStrictTags())
+	assert.NoError(err)
+	assert.Equal(map[string]int{"a": 64, "B_D": 8}, ranges)
+	assert.Equal([]string{"B_C_0", "B_C_1", "B_D"}, booleans)
+
+	_, err = Walk(&struct {
+		A variable `gnark:",range=-1"`
+	}{}, tVariable, nil)
+	assert.ErrorContains(err, `invalid "range" option "-1"`)
+}
+
+type gadget interface {
+	Gadget()
+}
+
+type gadgetPair struct {
+	X, Y variable
+}
+
+func (*gadgetPair) Gadget() {}
+
+// gadgetValue implements gadget on a value receiver, it can be stored in an
+// interface as a value
+type gadgetValue struct {
+	X variable
+}
+
+func (gadgetValue) Gadget() {}
+
+// gadgetConfig holds no leaves
+type gadgetConfig struct {
+	n int
+}
+
+func (gadgetConfig) Gadget() {}
+
+type circuitGadget struct {
+	A   variable
+	G   gadget `gnark:",public"`
+	Nil gadget
+}
+
+func TestSchemaInterface(t *testing.T) {
+	assert := require.New(t)
+
+	c := circuitGadget{G: &gadgetPair{}}
+	var names []string
+	count, err := Walk(&c, tVariable, func(leaf LeafInfo, tValue reflect.Value) error {
+		names = append(names, leaf.FullName())
+		tValue.Set(reflect.ValueOf(leaf.FullName()))
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(LeafCount{Secret: 1, Public: 2}, count)
+	assert.Equal([]string{"A", "G_X", "G_Y"}, names)
+	// the leaves of the dynamic value are set
+	assert.Equal("G_Y", c.G.(*gadgetPair).Y)
+
+	s, err := New(&c, tVariable)
+	assert.NoError(err)
+	assert.Equal(2, s.NbPublic)
+	assert.Equal(1, s.NbSecret)
+	assert.Len(s.Fields, 2)
+	assert.Equal("G", s.Fields[1].Name)
+	assert.Equal(Struct, s.Fields[1].Type)
+	assert.Len(s.Fields[1].SubFields, 2)
+
+	// the leaves of a value held by an interface can not be set, both parsers
+	// reject it
+	c = circuitGadget{G: gadgetValue{}}
+	_, err = Walk(&c, tVariable, func(leaf LeafInfo, tValue reflect.Value) error {
+		tValue.Set(reflect.ValueOf(leaf.FullName()))
+		return nil
+	})
+	assert.ErrorContains(err, "G: the interface holds a schema.gadgetValue value, it must hold a pointer")
+	_, err = New(&c, tVariable)
+	assert.ErrorContains(err, "G: the interface holds a schema.gadgetValue value, it must hold a pointer")
+
+	// a value without leaves is accepted
+	c = circuitGadget{G: gadgetConfig{n: 1}}
+	count, err = Walk(&c, tVariable, nil)
+	assert.NoError(err)
+	assert.Equal(LeafCount{Secret: 1}, count)
+	s, err = New(&c, tVariable)
+	assert.NoError(err)
+	assert.Equal(1, s.NbSecret)
+	assert.Equal(0, s.NbPublic)
+}
+
+func TestSchemaNilInterface(t *testing.T) {
+	assert := require.New(t)
+
+	// a nil gadget can not hold a variable, it is skipped quietly; a nil
+	// empty interface could, it is reported
+	c := struct {
+		A   variable
+		G   gadget
+		Any interface{}
+	}{}
+	out := captureStdout(t, func() {
+		_, err := Walk(&c, tVariable, nil)
+		assert.NoError(err)
+	})
+	assert.Equal("ignoring nil interface: Any interface {}\n", out)
+	out = captureStdout(t, func() {
+		_, err := New(&c, tVariable)
+		assert.NoError(err)
+	})
+	assert.Equal("ignoring nil interface: Any interface {}\n", out)
+}
+
+// compilerGadget has a method named like the API methods, but is not the API
+type compilerGadget interface {
+	Compiler() int
+}
+
+type compilerPair struct {
+	X variable
+}
+
+func (*compilerPair) Compiler() int { return 0 }
+
+type fakeAPI struct{}
+
+func (fakeAPI) GnarkAPI() {}
+
+type circuitAPIMarker struct {
+	API CircuitAPI
+	G   compilerGadget
+}
+
+func TestSchemaAPIMarker(t *testing.T) {
+	assert := require.New(t)
+
+	c := circuitAPIMarker{API: fakeAPI{}, G: &compilerPair{}}
+	var names []string
+	_, err := Walk(&c, tVariable, func(leaf LeafInfo, tValue reflect.Value) error {
+		names = append(names, leaf.FullName())
+		return nil
+	})
+	assert.NoError(err)
+	// the API is skipped, the gadget is walked
+	assert.Equal([]string{"G_X"}, names)
+
+	s, err := New(&c, tVariable)
+	assert.NoError(err)
+	assert.Equal(1, s.NbSecret)
+	assert.Len(s.Fields, 1)
+	assert.Equal("G", s.Fields[0].Name)
+}
+
+type mapAccount struct {
+	Balance variable
+	Keys    [2]variable
+}
+
+type mapKey [2]int
+
+type circuitMaps struct {
+	A map[string]variable `gnark:",public"`
+	B map[uint]mapAccount
+	C map[string]variable
+	D int
+}
+
+func TestWalkMap(t *testing.T) {
+	assert := require.New(t)
+
+	c := circuitMaps{
+		A: map[string]variable{"bob": nil, "alice": nil},
+		B: map[uint]mapAccount{10: {}, 9: {}},
+	}
+	var names []string
+	count, err := Walk(&c, tVariable, func(leaf LeafInfo, tValue reflect.Value) error {
+		names = append(names, leaf.FullName())
+		tValue.Set(reflect.ValueOf(leaf.FullName()))
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(2, count.Public)
+	assert.Equal(6, count.Secret)
+	// the elements are walked in the order of their keys
+	assert.Equal([]string{"A_alice", "A_bob", "B_9_Balance", "B_9_Keys_0", "B_9_Keys_1", "B_10_Balance", "B_10_Keys_0", "B_10_Keys_1"}, names)
+
+	// the values are written back in the maps
+	assert.Equal("A_alice", c.A["alice"])
+	assert.Equal("B_10_Keys_1", c.B[10].Keys[1])
+
+	// New parses the maps as Walk does, and instantiates them with their keys
+	s, err := New(&c, tVariable)
+	assert.NoError(err)
+	assert.Equal(count.Public, s.NbPublic)
+	assert.Equal(count.Secret, s.NbSecret)
+	var instNames []string
+	_, err = Walk(s.Instantiate(tVariable), tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+		instNames = append(instNames, leaf.FullName())
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(names, instNames)
+
+	_, err = Walk(&struct {
+		A map[mapKey]variable
+	}{A: map[mapKey]variable{{1, 2}: nil}}, tVariable, nil)
+	assert.Error(err)
+}
+
+type circuitMapsNoLeaves struct {
+	Params map[[2]int]int
+	Empty  map[string]variable
+	Nil    map[string]variable
+	A      variable
+}
+
+func TestWalkMapNoLeaves(t *testing.T) {
+	assert := require.New(t)
+
+	// the maps which can not hold leaves are skipped, whatever their keys, and
+	// the empty maps are quietly valid, only the nil ones are reported
+	c := circuitMapsNoLeaves{Params: map[[2]int]int{{1, 2}: 3}, Empty: map[string]variable{}}
+	out := captureStdout(t, func() {
+		count, err := Walk(&c, tVariable, nil)
+		assert.NoError(err)
+		assert.Equal(LeafCount{Secret: 1}, count)
+	})
+	assert.Equal("ignoring uninitialized map: Nil map[string]schema.variable\n", out)
+	out = captureStdout(t, func() {
+		s, err := New(&c, tVariable)
+		assert.NoError(err)
+		assert.Equal(1, s.NbSecret)
+		assert.Len(s.Fields, 1)
+	})
+	assert.Equal("ignoring uninitialized map: Nil map[string]schema.variable\n", out)
+	assert.Equal(map[[2]int]int{{1, 2}: 3}, c.Params)
+}
+
+type circuitUnderscore struct {
+	Outer struct {
+		My_Field variable
+		My       struct {
+			Field variable
+		}
+	}
+}
+
+func TestSeparatorUnderscore(t *testing.T) {
+	assert := require.New(t)
+	names := func(opts ...Option) ([]string, error) {
+		var res []string
+		_, err := Walk(&circuitUnderscore{}, tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+			res = append(res, leaf.FullName())
+			return nil
+		}, opts...)
+		return res, err
+	}
+
+	// with the default separator, the names collide
+	res, err := names()
+	assert.EqualError(err, `duplicate variable name "Outer_My_Field" at input 1, first at input 0`)
+	assert.Equal([]string{"Outer_My_Field"}, res)
+	_, err = New(&circuitUnderscore{}, tVariable)
+	assert.EqualError(err, `duplicate variable name "Outer_My_Field" at input 1, first at input 0`)
+
+	res, err = names(WithNameStrategy(Separator(".")))
+	assert.NoError(err)
+	assert.Equal([]string{"Outer.My_Field", "Outer.My.Field"}, res)
+	_, err = New(&circuitUnderscore{}, tVariable, WithNameStrategy(Separator(".")))
+	assert.NoError(err)
+}
+
+type merkleNode struct {
+	Hash variable
+}
+
+type circuitPadded struct {
+	Nodes  [100]merkleNode
+	Leaves []variable
+}
+
+func TestPaddedIndices(t *testing.T) {
+	assert := require.New(t)
+	c := &circuitPadded{Leaves: make([]variable, 8)}
+	names := func(opts ...Option) []string {
+		var res []string
+		_, err := Walk(c, tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+			res = append(res, leaf.FullName())
+			return nil
+		}, opts...)
+		assert.NoError(err)
+		return res
+	}
+
+	unpadded := names()
+	assert.Len(unpadded, 108)
+	assert.Equal("Nodes_0_Hash", unpadded[0])
+	assert.Equal("Nodes_99_Hash", unpadded[99])
+	assert.Equal("Leaves_7", unpadded[107])
+	assert.False(sort.StringsAreSorted(unpadded[:100]))
+
+	padded := names(WithNameStrategy(PaddedIndices{DefaultNameStrategy}))
+	assert.Len(padded, 108)
+	for i := 0; i < 100; i++ {
+		assert.Equal(fmt.Sprintf("Nodes_%03d_Hash", i), padded[i])
+	}
+	// the width is the number of digits of the length
+	assert.Equal("Leaves_0", padded[100])
+	assert.Equal("Leaves_7", padded[107])
+	// the lexical order is the order of the indexes
+	assert.True(sort.StringsAreSorted(padded[:100]))
+
+	// the parser names the elements the same way
+	s, err := New(c, tVariable, WithNameStrategy(PaddedIndices{DefaultNameStrategy}))
+	assert.NoError(err)
+	assert.Equal("Nodes_000_Hash", s.Fields[0].SubFields[0].SubFields[0].FullName)
+	assert.Equal(PaddedIndices{DefaultNameStrategy}, s.NameStrategy())
+
+	// the strategy is not global
+	assert.Equal(unpadded, names())
+}
+
+type AccountA struct {
+	Amount variable
+	Nonce  variable
+}
+
+type AccountB struct {
+	Amount  variable
+	Amounts [2]variable
+}
+
+type circuitDuplicate struct {
+	AccountA
+	AccountB
+	Amounts []variable
+}
+
+func TestSchemaDuplicateNames(t *testing.T) {
+	assert := require.New(t)
+
+	var names []string
+	count, err := Walk(&circuitDuplicate{Amounts: make([]variable, 3)}, tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+		names = append(names, leaf.FullName())
+		return nil
+	})
+	// the elements of the slices are checked one by one
+	assert.EqualError(err, `duplicate variable name "Amount" at input 2, first at input 0`+"\n"+
+		`duplicate variable name "Amounts_0" at input 4, first at input 2`+"\n"+
+		`duplicate variable name "Amounts_1" at input 4, first at input 3`)
+	assert.Equal([]string{"Amount", "Nonce", "Amounts_0", "Amounts_1", "Amounts_2"}, names)
+	assert.Equal(5, count.Secret)
+	_, err = New(&circuitDuplicate{Amounts: make([]variable, 3)}, tVariable)
+	assert.ErrorContains(err, `duplicate variable name "Amount" at input 2, first at input 0`)
+
+	// a tag may name a leaf as an element of a slice
+	_, err = Walk(&struct {
+		A [2]variable
+		B variable `gnark:"A_1"`
+	}{}, tVariable, nil)
+	assert.EqualError(err, `duplicate variable name "A_1" at input 2, first at input 1`)
+
+	// in a named field, the names are distinct
+	type circuit struct {
+		AccountA
+		B AccountB
+	}
+	_, err = Walk(&circuit{}, tVariable, nil)
+	assert.NoError(err)
+}
+
+type embeddedAccount struct {
+	Balance variable
+	Nonce   variable `gnark:",public"`
+}
+
+type circuitEmbedded struct {
+	embeddedAccount
+	AccountA  `gnark:"From"`
+	*AccountB `gnark:",public"`
+	X         variable
+}
+
+func TestSchemaEmbedded(t *testing.T) {
+	assert := require.New(t)
+
+	newCircuit := func() *circuitEmbedded {
+		return &circuitEmbedded{AccountB: new(AccountB)}
+	}
+	names := func(circuit interface{}) (public, secret []string) {
+		count, err := Walk(circuit, tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+			if leaf.Visibility == Public {
+				public = append(public, leaf.FullName())
+			} else {
+				secret = append(secret, leaf.FullName())
+			}
+			return nil
+		})
+		assert.NoError(err)
+		assert.Equal(LeafCount{Public: len(public), Secret: len(secret)}, count)
+		return
+	}
+
+	// the fields of the anonymous structs are promoted, unless the tag sets a
+	// name
+	public, secret := names(newCircuit())
+	assert.Equal([]string{"Nonce", "Amount", "Amounts_0", "Amounts_1"}, public)
+	assert.Equal([]string{"Balance", "From_Amount", "From_Nonce", "X"}, secret)
+
+	// the schema has the same leaves
+	s, err := New(newCircuit(), tVariable)
+	assert.NoError(err)
+	assert.Equal(4, s.NbPublic)
+	assert.Equal(4, s.NbSecret)
+	sPublic, sSecret := names(s.Instantiate(tVariable))
+	assert.Equal(public, sPublic)
+	assert.Equal(secret, sSecret)
+}
+
+type circuitBadFields struct {
+	A variable `gnark:",invalid"`
+	B variable
+	C struct {
+		D variable `gnark:",bad"`
+	}
+	E [2]struct {
+		F variable `gnark:",alsobad"`
+	}
+}
+
+func TestSchemaErrors(t *testing.T) {
+	assert := require.New(t)
+
+	// all the invalid fields are reported
+	_, err := New(&circuitBadFields{}, tVariable)
+	assert.Error(err)
+	assert.Contains(err.Error(), "invalid gnark struct tag option on A")
+	assert.Contains(err.Error(), "invalid gnark struct tag option on C_D")
+	assert.Contains(err.Error(), "invalid gnark struct tag option on E_1_F")
+
+	// the walk goes on after a handler error
+	var walked []string
+	_, err = Walk(&Circuit{Z: make([]variable, 2)}, tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+		walked = append(walked, leaf.FullName())
+		switch leaf.FullName() {
+		case "x", "Z_1", "I_1_E":
+			return fmt.Errorf("bad leaf %s", leaf.FullName())
+		}
+		return nil
+	})
+	assert.Error(err)
+	for _, name := range []string{"x", "Z_1", "I_1_E"} {
+		assert.Contains(err.Error(), "bad leaf "+name)
+	}
+	assert.Contains(walked, "I_1_P_0_M")
+}
+
+type circuitUnexported struct {
+	A variable
+	b variable
+	c struct {
+		D variable
+	}
+	e int
+	f variable `gnark:"-"`
+}
+
+func TestSchemaUnexported(t *testing.T) {
+	assert := require.New(t)
+
+	// the unexported fields holding variables are skipped, with a warning, by
+	// the walk
+	count, err := Walk(&circuitUnexported{}, tVariable, func(LeafInfo, reflect.Value) error { return nil })
+	assert.NoError(err)
+	assert.Equal(1, count.Secret)
+
+	// and reported with WithStrictUnexported, by the walk and the schema
+	for _, err := range []error{
+		func() error {
+			_, err := Walk(&circuitUnexported{}, tVariable, func(LeafInfo, reflect.Value) error { return nil }, WithStrictUnexported())
+			return err
+		}(),
+		func() error {
+			_, err := New(&circuitUnexported{}, tVariable, WithStrictUnexported())
+			return err
+		}(),
+	} {
+		assert.Error(err)
+		assert.Contains(err.Error(), "b: unexported field of type schema.variable")
+		assert.Contains(err.Error(), "c: unexported field of type struct { D schema.variable }")
+		assert.NotContains(err.Error(), "e:")
+		assert.NotContains(err.Error(), "f:")
+	}
+
+	// and skipped, with a warning, by the schema
+	s, err := New(&circuitUnexported{}, tVariable)
+	assert.NoError(err)
+	assert.Equal(1, s.NbSecret)
+	assert.Len(s.Fields, 1)
+}
+
+type circuitPointers struct {
+	A *variable `gnark:",public"`
+	B []*variable
+	C *variable
+}
+
+func TestSchemaPointers(t *testing.T) {
+	assert := require.New(t)
+
+	shared := variable(nil)
+	newCircuit := func() *circuitPointers {
+		// C is nil, as the second element of B
+		return &circuitPointers{A: &shared, B: []*variable{&shared, nil}}
+	}
+
+	c := newCircuit()
+	var names []string
+	count, err := Walk(c, tVariable, func(leaf LeafInfo, tValue reflect.Value) error {
+		names = append(names, leaf.FullName())
+		tValue.Set(reflect.ValueOf(leaf.FullName()))
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(1, count.Public)
+	assert.Equal(3, count.Secret)
+	assert.Equal([]string{"A", "B_0", "B_1", "C"}, names)
+	// the nil pointers are allocated, the others are written through
+	assert.Equal("B_1", *c.B[1])
+	assert.Equal("C", *c.C)
+	assert.Equal("B_0", shared)
+
+	s, err := New(newCircuit(), tVariable)
+	assert.NoError(err)
+	assert.Equal(1, s.NbPublic)
+	assert.Equal(3, s.NbSecret)
+	assert.Equal(Array, s.Fields[1].Type)
+	assert.Equal(2, s.Fields[1].ArraySize)
+}
+
+type subCircuit struct {
+	X variable
+	Y [2]variable `gnark:",public"`
+}
+
+type circuitNilStructs struct {
+	Sub    *subCircuit
+	Params *struct{ N int }
+	List   *listNode
+	G      *stateGadget
+	Z      variable
+}
+
+// stateGadget holds its variables in an unexported field, they are not inputs
+type stateGadget struct {
+	state []variable
+}
+
+type listNode struct {
+	V    variable
+	Next *listNode
+}
+
+func TestSchemaNilStructPointers(t *testing.T) {
+	assert := require.New(t)
+
+	c := &circuitNilStructs{}
+	var names []string
+	count, err := Walk(c, tVariable, func(leaf LeafInfo, tValue reflect.Value) error {
+		names = append(names, leaf.FullName())
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(2, count.Public)
+	assert.Equal(2, count.Secret)
+	assert.Equal([]string{"Sub_X", "Sub_Y_0", "Sub_Y_1", "Z"}, names)
+	// the struct holding variables is allocated, the struct without variables
+	// and the recursive list are left nil
+	assert.NotNil(c.Sub)
+	assert.Nil(c.Params)
+	assert.Nil(c.List)
+	assert.Nil(c.G)
+
+	// the nil struct with unexported leaves is not walked, even when they are
+	// reported
+	_, err = Walk(&circuitNilStructs{}, tVariable, nil, WithStrictUnexported())
+	assert.NoError(err)
+
+	c = &circuitNilStructs{}
+	s, err := New(c, tVariable)
+	assert.NoError(err)
+	assert.Equal(2, s.NbPublic)
+	assert.Equal(2, s.NbSecret)
+	assert.NotNil(c.Sub)
+	assert.Nil(c.Params)
+	assert.Nil(c.G)
+	assert.Equal("Sub", s.Fields[0].Name)
+	assert.Len(s.Fields[0].SubFields, 2)
+}
+
+type circuitStrictTags struct {
+	Balance variable `gnark:",publik"`
+	Amount  variable `gnark:"amount,public"`
+	Nonce   variable `gnark:",secret,commit,bits=64"`
+	Account struct {
+		Key variable `gnark:",static,unknown"`
+	}
+}
+
+func TestSchemaStrictTags(t *testing.T) {
+	assert := require.New(t)
+
+	// by default, the unknown options are ignored
+	count, err := Walk(&circuitStrictTags{}, tVariable, nil)
+	assert.NoError(err)
+	assert.Equal(LeafCount{Public: 1, Secret: 3}, count)
+
+	var names []string
+	count, err = Walk(&circuitStrictTags{}, tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+		names = append(names, leaf.FullName())
+		return nil
+	}, WithStrictTags())
+	assert.EqualError(err, `unknown gnark tag option "publik" on field Balance`+"\n"+
+		`unknown gnark tag option "unknown" on field Account_Key`)
+	assert.Equal([]string{"amount", "Nonce"}, names)
+	assert.Equal(LeafCount{Public: 1, Secret: 1}, count)
+
+	_, err = New(&circuitStrictTags{}, tVariable, WithStrictTags())
+	assert.ErrorContains(err, `unknown gnark tag option "publik" on field Balance`)
+	assert.ErrorContains(err, `unknown gnark tag option "unknown" on field Account_Key`)
+
+	// the valid options are accepted
+	s, err := New(&struct {
+		Amount variable `gnark:"amount,public"`
+		Nonce  variable `gnark:",secret,commit,bits=64"`
+	}{}, tVariable, WithStrictTags())
+	assert.NoError(err)
+	assert.Equal(1, s.NbPublic)
+	assert.Equal(1, s.NbSecret)
+}
+
+type circuitNumericTag struct {
+	Zero variable   `gnark:"0"`
+	Xs   []variable `gnark:",public"`
+	Y    variable
+}
+
+func TestSchemaTagNames(t *testing.T) {
+	assert := require.New(t)
+
+	// a name made only of digits would collide with the names of the
+	// elements of the slices
+	c := &circuitNumericTag{Xs: make([]variable, 2)}
+	var names []string
+	count, err := Walk(c, tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+		names = append(names, leaf.FullName())
+		return nil
+	})
+	assert.EqualError(err, `gnark tag name "0" on field Zero is reserved for slice and array indices`)
+	assert.Equal([]string{"Xs_0", "Xs_1", "Y"}, names)
+	assert.Equal(LeafCount{Public: 2, Secret: 1}, count)
+
+	_, err = New(c, tVariable)
+	assert.EqualError(err, `gnark tag name "0" on field Zero is reserved for slice and array indices`)
+
+	// an invalid name is an error instead of being silently ignored
+	invalid := &struct {
+		X variable `gnark:"a\\b,public"`
+	}{}
+	_, err = Walk(invalid, tVariable, nil)
+	assert.EqualError(err, `invalid gnark tag name "a\\b" on field X`)
+	_, err = New(invalid, tVariable)
+	assert.EqualError(err, `invalid gnark tag name "a\\b" on field X`)
+
+	// names with digits and an empty name are valid
+	s, err := New(&struct {
+		X variable `gnark:"x0,public"`
+		Y variable `gnark:",secret"`
+	}{}, tVariable)
+	assert.NoError(err)
+	assert.Equal("x0", s.Fields[0].NameTag)
+	assert.Equal("Y", s.Fields[1].Name)
+}
+
+type parallelElem struct {
+	A variable `gnark:",public"`
+	B []variable
+	C struct {
+		D [2]variable `gnark:"d"`
+		E *variable
+	}
+	AccountA
+}
+
+type parallelCircuit struct {
+	X     variable
+	Elems []parallelElem
+	Arr   [3]parallelElem `gnark:",public"`
+	Y     variable        `gnark:",public"`
+}
+
+func newParallelCircuit(n int) *parallelCircuit {
+	c := &parallelCircuit{Elems: make([]parallelElem, n)}
+	for i := range c.Elems {
+		c.Elems[i].B = make([]variable, 3)
+	}
+	for i := range c.Arr {
+		c.Arr[i].B = make([]variable, 2)
+	}
+	return c
+}
+
+func TestSchemaParallel(t *testing.T) {
+	assert := require.New(t)
+
+	expected, err := New(newParallelCircuit(100), tVariable)
+	assert.NoError(err)
+	expectedJSON, err := json.Marshal(expected)
+	assert.NoError(err)
+	for _, parallelism := range []int{2, 3, 16, 200} {
+		s, err := New(newParallelCircuit(100), tVariable, WithParallelism(parallelism))
+		assert.NoError(err)
+		sJSON, err := json.Marshal(s)
+		assert.NoError(err)
+		assert.Equal(string(expectedJSON), string(sJSON), "parallelism %d", parallelism)
+		assert.Equal(expected.NbPublic, s.NbPublic)
+		assert.Equal(expected.NbSecret, s.NbSecret)
+	}
+
+	// the duplicate names are detected when merging the elements
+	c := &struct {
+		Elems []circuitDuplicate
+	}{make([]circuitDuplicate, 4)}
+	for i := range c.Elems {
+		c.Elems[i].Amounts = make([]variable, 1)
+	}
+	_, err = New(c, tVariable, WithParallelism(4))
+	assert.ErrorContains(err, `duplicate variable name "Elems_0_Amount" at input 2, first at input 0`)
+	assert.ErrorContains(err, `duplicate variable name "Elems_3_Amount"`)
+
+	// the inputs are indexed from the leaves, not from the elements, which
+	// expand to several leaves
+	nested := &struct {
+		Elems []nestedDuplicate
+	}{make([]nestedDuplicate, 2)}
+	for i := range nested.Elems {
+		nested.Elems[i].M = [][]variable{make([]variable, 2), make([]variable, 2)}
+	}
+	expectedErr := `duplicate variable name "Elems_0_Amount" at input 6, first at input 4` + "\n" +
+		`duplicate variable name "Elems_1_Amount" at input 14, first at input 12`
+	_, err = New(nested, tVariable)
+	assert.EqualError(err, expectedErr)
+	_, err = New(nested, tVariable, WithParallelism(2))
+	assert.EqualError(err, expectedErr)
+}
+
+type nestedDuplicate struct {
+	M [][]variable
+	AccountA
+	AccountB
+}
+
+func TestParseVisibility(t *testing.T) {
+	assert := require.New(t)
+	for v, s := range map[Visibility]string{
+		Unset:    "unset",
+		Internal: "internal",
+		Secret:   "secret",
+		Public:   "public",
+		Virtual:  "virtual",
+	} {
+		assert.Equal(s, v.String())
+		parsed, err := ParseVisibility(s)
+		assert.NoError(err)
+		assert.Equal(v, parsed)
+	}
+	_, err := ParseVisibility("Public")
+	assert.EqualError(err, `invalid visibility "Public"`)
+
+	// only the public and secret visibilities can be set with tags
+	assert.Equal(Secret, tagOptions("public, secret").visibility())
+	assert.Equal(Public, tagOptions("commit,public").visibility())
+	assert.Equal(Unset, tagOptions("internal,inherit").visibility())
+}
+
+// textKey is named by its name, its default format starts with its id.
+type textKey struct {
+	id   int
+	name string
+}
+
+func (k *textKey) MarshalText() ([]byte, error) {
+	return []byte(k.name), nil
+}
+
+type circuitDeterministic struct {
+	Z        variable `gnark:",public"`
+	Accounts map[string]mapAccount
+	ByKey    map[*textKey]variable
+	Elems    []mapAccount
+	A        variable
+}
+
+func newCircuitDeterministic() *circuitDeterministic {
+	c := &circuitDeterministic{
+		Accounts: make(map[string]mapAccount),
+		ByKey:    make(map[*textKey]variable),
+		Elems:    make([]mapAccount, 10),
+	}
+	for i := 0; i < 20; i++ {
+		c.Accounts[fmt.Sprintf("account%d", i)] = mapAccount{}
+		c.ByKey[&textKey{id: i, name: fmt.Sprintf("key%d", 19-i)}] = nil
+	}
+	return c
+}
+
+func TestWalkDeterministic(t *testing.T) {
+	assert := require.New(t)
+	ids := func() map[string]int {
+		res := make(map[string]int)
+		_, err := Walk(newCircuitDeterministic(), tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+			res[leaf.FullName()] = len(res)
+			return nil
+		})
+		assert.NoError(err)
+		return res
+	}
+	expected := ids()
+	assert.Len(expected, 1+20*3+20+10*3+1)
+	// the struct fields are walked in their declaration order
+	assert.Equal(0, expected["Z"])
+	assert.Equal(len(expected)-1, expected["A"])
+	// the map elements are walked in the order of their names
+	assert.Equal(expected["ByKey_key0"]+1, expected["ByKey_key1"])
+	assert.Equal(expected["ByKey_key1"]+1, expected["ByKey_key10"])
+
+	expectedSchema, err := New(newCircuitDeterministic(), tVariable)
+	assert.NoError(err)
+	for i := 0; i < 100; i++ {
+		// new maps are iterated in a different order
+		assert.Equal(expected, ids(), "walk %d", i)
+		s, err := New(newCircuitDeterministic(), tVariable, WithParallelism(4))
+		assert.NoError(err)
+		assert.Equal(expectedSchema, s, "schema %d", i)
+	}
+}
+
+type circuitSlices struct {
+	Nil   []variable
+	Empty []variable
+	Zero  [0]variable
+	Vals  []variable `gnark:",public"`
+}
+
+// captureStdout returns what f prints on the standard output.
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	f()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestEmptySlices(t *testing.T) {
+	assert := require.New(t)
+	newCircuit := func() *circuitSlices {
+		// the elements of Vals are zero values, they are inputs
+		return &circuitSlices{Empty: []variable{}, Vals: make([]variable, 3)}
+	}
+
+	var names []string
+	var count LeafCount
+	var err error
+	out := captureStdout(t, func() {
+		count, err = Walk(newCircuit(), tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+			names = append(names, leaf.FullName())
+			return nil
+		})
+	})
+	assert.NoError(err)
+	assert.Equal(LeafCount{Public: 3}, count)
+	assert.Equal([]string{"Vals_0", "Vals_1", "Vals_2"}, names)
+	// only the nil slice is reported
+	assert.Equal("ignoring uninitialized slice: Nil []schema.variable\n", out)
+
+	var s *Schema
+	out = captureStdout(t, func() {
+		s, err = New(newCircuit(), tVariable)
+	})
+	assert.NoError(err)
+	assert.Equal(3, s.NbPublic)
+	assert.Equal([]Field{{Name: "Vals", FullName: "", Visibility: Public, Type: Array, ArraySize: 3}}, s.Fields)
+	assert.Equal("ignoring uninitialized slice: Nil []schema.variable\n", out)
+}
+
+type circuitBigInt struct {
+	Modulus *big.Int
+	Nil     *big.Int
+	Scalar  big.Int
+	Powers  []*big.Int
+	X       variable
+}
+
+func TestBigIntFields(t *testing.T) {
+	assert := require.New(t)
+	newCircuit := func() *circuitBigInt {
+		return &circuitBigInt{Modulus: big.NewInt(7), Powers: []*big.Int{big.NewInt(1), big.NewInt(7)}}
+	}
+
+	var names []string
+	var count LeafCount
+	var err error
+	out := captureStdout(t, func() {
+		count, err = Walk(newCircuit(), tVariable, func(leaf LeafInfo, _ reflect.Value) error {
+			names = append(names, leaf.FullName())
+			return nil
+		})
+	})
+	assert.NoError(err)
+	assert.Equal(LeafCount{Secret: 1}, count)
+	assert.Equal([]string{"X"}, names)
+	assert.Empty(out)
+
+	var s *Schema
+	out = captureStdout(t, func() {
+		s, err = New(newCircuit(), tVariable)
+	})
+	assert.NoError(err)
+	assert.Equal(1, s.NbSecret)
+	assert.Len(s.Fields, 1)
+	assert.Empty(out)
+}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"fmt"
 	"strings"
 	"unicode"
 )
@@ -15,7 +16,24 @@ import (
 //   - [TagOptInherit] ("inherit"): element's visibility is inherited from its
 //     parent visibility. Is useful for defining custom types to allow consistent
 //     visibility;
-//   - [TagOptOmit] ("-"): do not insert the element into a witness.
+//   - [TagOptOmit] ("-"): do not insert the element into a witness;
+//   - [TagOptCommit] ("commit"): secret element which must be bound to a public
+//     commitment. It is inherited by the children of the element;
+//   - [TagOptStatic] ("static"): element whose value is shared by many
+//     witnesses. It is inherited by the children of the element;
+//   - [TagOptBits] ("bits=n"): element whose assigned value must fit in n bits.
+//     It is inherited by the children of the element;
+//   - [TagOptRange] ("range=n"): element constrained to fit in n bits. Unlike
+//     [TagOptBits], which only checks the assignment when building the witness,
+//     the compiler emits the range check in the circuit. It is inherited by the
+//     children of the element;
+//   - [TagOptBoolean] ("boolean"): element constrained to be 0 or 1. The
+//     compiler emits the booleanity constraint in the circuit. It is inherited
+//     by the children of the element.
+//
+// The unknown options are ignored, unless the circuit is parsed with
+// [WithStrictTags]. An invalid name, or a name made only of digits which
+// would collide with the names of the elements of slices, is an error.
 //
 // # Examples
 //
@@ -70,6 +88,27 @@ import (
 //	type ListCircuit struct {
 //	    X List `gnark:",secret"`
 //	}
+//
+// The "commit" option marks secret elements which must be bound to a public
+// commitment, to prevent malleability of the secret inputs. The option only
+// marks the elements: the commitment itself is computed and asserted by a
+// gadget called from Define (see std/commitments/inputcommit). The compilation
+// fails if Define does not assert the commitment:
+//
+//	type CommitCircuit struct {
+//	    X          frontend.Variable `gnark:",secret,commit"`
+//	    Commitment frontend.Variable `gnark:",public"`
+//	}
+//
+// The "static" option marks elements which keep the same value across many
+// proofs, for example a lookup table. Their values can be given once and reused
+// when building the witnesses (see frontend.NewStaticWitness). The option does
+// not change the solving: all the wires are solved again for every proof.
+//
+//	type StaticCircuit struct {
+//	    Table []frontend.Variable `gnark:",public,static"`
+//	    X     frontend.Variable
+//	}
 type TagOpt string
 
 const (
@@ -77,6 +116,11 @@ const (
 	TagOptSecret  TagOpt = "secret"  // secret witness element
 	TagOptInherit TagOpt = "inherit" // inherit the visibility of the witness element from its parent.
 	TagOptOmit    TagOpt = "-"       // do not parse the field as witness element
+	TagOptCommit  TagOpt = "commit"  // secret witness element bound to a public commitment
+	TagOptStatic  TagOpt = "static"  // witness element shared by many witnesses
+	TagOptBits    TagOpt = "bits"    // expected bit width of the assigned value, as bits=n
+	TagOptRange   TagOpt = "range"   // bit width the element is constrained to, as range=n
+	TagOptBoolean TagOpt = "boolean" // element constrained to be a boolean
 )
 
 const (
@@ -119,6 +163,93 @@ func (o tagOptions) contains(optionName TagOpt) bool {
 	return false
 }
 
+// visibility returns the visibility set by the options, [Secret] or [Public],
+// and Unset if none is set. Secret wins if both are set.
+func (o tagOptions) visibility() Visibility {
+	res := Unset
+	for _, opt := range strings.Split(string(o), ",") {
+		v, err := ParseVisibility(strings.TrimSpace(opt))
+		if err != nil || (v != Secret && v != Public) {
+			continue
+		}
+		if res != Secret {
+			res = v
+		}
+	}
+	return res
+}
+
+// value returns the value of a key=value option and true if the option is set.
+func (o tagOptions) value(optionName TagOpt) (string, bool) {
+	if len(o) == 0 {
+		return "", false
+	}
+	for _, opt := range strings.Split(string(o), ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(opt), "="); ok && strings.TrimSpace(k) == string(optionName) {
+			return strings.TrimSpace(v), true
+		}
+	}
+	return "", false
+}
+
+// without returns the options without the given flags, also removing the
+// key=value options with the given keys.
+func (o tagOptions) without(optionNames ...TagOpt) tagOptions {
+	var res []string
+	for _, opt := range strings.Split(string(o), ",") {
+		keep := true
+		for _, name := range optionNames {
+			opt := strings.TrimSpace(opt)
+			if opt == string(name) || strings.HasPrefix(opt, string(name)+"=") {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			res = append(res, opt)
+		}
+	}
+	return tagOptions(strings.TrimSpace(strings.Join(res, ",")))
+}
+
+// unknown returns the options which are not valid tag options.
+func (o tagOptions) unknown() []string {
+	var res []string
+	for _, opt := range strings.Split(string(o), ",") {
+		opt = strings.TrimSpace(opt)
+		switch TagOpt(opt) {
+		case "", TagOptPublic, TagOptSecret, TagOptInherit, TagOptCommit, TagOptStatic, TagOptBoolean:
+			continue
+		}
+		if k, _, ok := strings.Cut(opt, "="); ok {
+			if k := TagOpt(strings.TrimSpace(k)); k == TagOptBits || k == TagOptRange {
+				continue
+			}
+		}
+		res = append(res, opt)
+	}
+	return res
+}
+
+// checkTagName returns an error if the name set by the gnark tag of field is
+// not valid. An empty name is valid: the field keeps its Go name. A name made
+// only of digits is rejected, the elements of slices and arrays are named
+// after their index and it would collide with them, e.g. a field tagged "0"
+// of the first element of a slice of structs and the first element of a
+// nested slice.
+func checkTagName(name, field string) error {
+	if name == "" {
+		return nil
+	}
+	if !isValidTag(name) {
+		return fmt.Errorf("invalid gnark tag name %q on field %s", name, field)
+	}
+	if strings.Trim(name, "0123456789") == "" {
+		return fmt.Errorf("gnark tag name %q on field %s is reserved for slice and array indices", name, field)
+	}
+	return nil
+}
+
 func isValidTag(s string) bool {
 	if s == "" {
 		return false
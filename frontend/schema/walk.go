@@ -1,7 +1,10 @@
 package schema
 
 import (
+	"encoding"
+	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
@@ -12,16 +15,35 @@ import (
 // Walk walks through the provided object and stops when it encounters objects of type tLeaf
 //
 // It returns the number of secret and public leafs encountered during the walk.
-func Walk(circuit interface{}, tLeaf reflect.Type, handler LeafHandler) (count LeafCount, err error) {
+//
+// The errors returned by the handler and the invalid struct tags do not stop
+// the walk: the rest of the structure is walked and the returned error joins
+// all of them. Two leaves with the same full name, for example the fields of
+// two embedded structs, are an error: they would shadow each other in the
+// witness.
+//
+// The order of the walk only depends on the type and the values of the
+// circuit: the fields of structs are walked in their declaration order, the
+// elements of slices and arrays in the order of their indices and the
+// elements of maps in the order of their keys. The ids of the inputs of a
+// compiled circuit, which are assigned in this order, are then the same at
+// each compilation.
+func Walk(circuit interface{}, tLeaf reflect.Type, handler LeafHandler, opts ...Option) (count LeafCount, err error) {
 	w := walker{
 		target:      tLeaf,
 		targetSlice: reflect.SliceOf(tLeaf),
 		handler:     handler,
+		seen:        make(map[string]int),
+		config:      newConfig(opts),
 	}
 	err = reflectwalk.Walk(circuit, &w)
 	if err == reflectwalk.ErrSkipEntry {
 		err = nil
 	}
+	if err != nil {
+		w.errs = append(w.errs, err)
+	}
+	err = errors.Join(w.errs...)
 	count.Public = w.nbPublic
 	count.Secret = w.nbSecret
 	return
@@ -43,24 +65,56 @@ type walker struct {
 	targetSlice        reflect.Type
 	path               pathStack
 	nbPublic, nbSecret int
+	errs               []error        // errors of the leaves, reported at the end of the walk
+	seen               map[string]int // full names of the walked leaves, and their index
+	structs            [][]fieldInfo  // parsed tags of the fields of the walked structs
+	lengths            []int          // lengths of the walked arrays and slices, to name their elements
+	config
+}
+
+// checkName returns an error if a leaf with the same full name was already
+// walked, and records the name of the leaf at index otherwise. It is called
+// for each element of the slices and arrays of leaves.
+func (w *walker) checkName(name string, index int) error {
+	if first, ok := w.seen[name]; ok {
+		return fmt.Errorf("duplicate variable name %q at input %d, first at input %d", name, index, first)
+	}
+	w.seen[name] = index
+	return nil
 }
 
 // Interface handles interface values as they are encountered during the walk.
 // That's where we handle leaves.
 func (w *walker) Interface(value reflect.Value) error {
 	if value.Type() != w.target {
+		if isAPI(value.Type()) {
+			// do not walk through the circuit API
+			return reflectwalk.ErrSkipEntry
+		}
+		if value.Kind() == reflect.Interface {
+			return w.dynamicValue(value)
+		}
 		// keep walking.
 		return nil
 	}
+	if isConstant(value) {
+		return reflectwalk.ErrSkipEntry
+	}
 	v := w.visibility()
 	if v == Unset {
 		v = Secret
 	}
+	// the name built for the check is the one given to the handler
+	name := w.name()
+	if err := w.checkName(name, w.nbPublic+w.nbSecret); err != nil {
+		w.errs = append(w.errs, err)
+		return reflectwalk.ErrSkipEntry
+	}
 
 	// call the handler.
 	if w.handler != nil {
-		if err := w.handler(LeafInfo{Visibility: v, FullName: w.name, name: ""}, value); err != nil {
-			return err
+		if err := w.handler(LeafInfo{Visibility: v, FullName: func() string { return name }, Commit: w.commit(), Static: w.static(), Bits: w.bits(), Range: w.rangeBits(), Boolean: w.boolean(), name: ""}, value); err != nil {
+			w.errs = append(w.errs, err)
 		}
 	}
 
@@ -74,25 +128,72 @@ func (w *walker) Interface(value reflect.Value) error {
 	return reflectwalk.ErrSkipEntry
 }
 
+// dynamicValue handles the interfaces which are not leaves, for example a
+// gadget stored in a field of an interface type. The walk goes on through
+// their dynamic value, nil interfaces are ignored. See [checkDynamicValue] for
+// the values which are not pointers.
+func (w *walker) dynamicValue(value reflect.Value) error {
+	if value.IsNil() {
+		if canHold(value.Type(), w.target) {
+			fmt.Printf("ignoring nil interface: %s %s\n", w.name(), value.Type().String())
+		}
+		return reflectwalk.ErrSkipEntry
+	}
+	if err := checkDynamicValue(w.name(), value, w.target); err != nil {
+		w.errs = append(w.errs, err)
+		return reflectwalk.ErrSkipEntry
+	}
+	return nil
+}
+
+// canHold returns true if the interfaces of type t can hold a value of type
+// target, the nil ones are reported as likely not initialized.
+func canHold(t, target reflect.Type) bool {
+	return target.Implements(t)
+}
+
+// checkDynamicValue returns an error if the non-nil interface value, named
+// name, holds leaves in a value which is not a pointer. Such a value can not
+// be set: the leaves could not be assigned by [Walk], and [New] would only
+// parse a copy of them.
+func checkDynamicValue(name string, value reflect.Value, target reflect.Type) error {
+	if elem := value.Elem(); elem.Kind() != reflect.Ptr && containsType(elem.Type(), target) {
+		return fmt.Errorf("%s: the interface holds a %s value, it must hold a pointer for its inputs to be set", name, elem.Type())
+	}
+	return nil
+}
+
+// Pointer handles pointers as they are encountered during the walk. The walk
+// goes on through the pointed value; nil pointers to a leaf, or to a struct
+// holding leaves, are set to a new value when possible, so that they are
+// walked like their value counterparts.
 func (w *walker) Pointer(value reflect.Value) error {
+	if value.IsNil() && value.CanSet() && allocOnNil(value.Type(), w.target) {
+		value.Set(reflect.New(value.Type().Elem()))
+	}
 	return w.Interface(value)
 }
 
-// Slice handles slice elements found within complex structures.
+// Slice handles slice elements found within complex structures. Nil slices of
+// leaves are reported, as they were likely not initialized before the
+// compilation; empty slices are skipped quietly.
 func (w *walker) Slice(value reflect.Value) error {
 	if value.Type() == w.targetSlice {
 		if value.Len() == 0 {
-			fmt.Printf("ignoring uninitialized slice: %s %s\n", w.name(), reflect.SliceOf(w.target).String())
+			if value.IsNil() {
+				fmt.Printf("ignoring uninitialized slice: %s %s\n", w.name(), reflect.SliceOf(w.target).String())
+			}
+			w.lengths = append(w.lengths, 0)
 			return nil
 		}
 		return w.handleLeaves(value)
 	}
-
+	w.lengths = append(w.lengths, value.Len())
 	return nil
 }
 
-func (w *walker) arraySliceElem(index int, v reflect.Value) error {
-	w.path.push(LeafInfo{Visibility: w.visibility(), name: strconv.Itoa(index)})
+func (w *walker) arraySliceElem(name string, v reflect.Value) error {
+	w.path.push(LeafInfo{Visibility: w.visibility(), Commit: w.commit(), Static: w.static(), Bits: w.bits(), Range: w.rangeBits(), Boolean: w.boolean(), name: name})
 	if v.CanAddr() && v.Addr().CanInterface() {
 		// TODO @gbotrel don't like that hook, undesirable side effects
 		// will be hard to detect; (for example calling Parse multiple times will init multiple times!)
@@ -105,18 +206,82 @@ func (w *walker) arraySliceElem(index int, v reflect.Value) error {
 }
 
 func (w *walker) SliceElem(index int, v reflect.Value) error {
-	return w.arraySliceElem(index, v)
+	return w.arraySliceElem(w.indexName(index, w.lengths[len(w.lengths)-1]), v)
 }
 
 // Array handles array elements found within complex structures.
 func (w *walker) Array(value reflect.Value) error {
-	if value.Type() == reflect.ArrayOf(value.Len(), w.target) {
+	if value.Type().Elem() == w.target {
 		return w.handleLeaves(value)
 	}
+	w.lengths = append(w.lengths, value.Len())
 	return nil
 }
 func (w *walker) ArrayElem(index int, v reflect.Value) error {
-	return w.arraySliceElem(index, v)
+	return w.arraySliceElem(w.indexName(index, w.lengths[len(w.lengths)-1]), v)
+}
+
+// Map handles maps found within complex structures. The elements are named by
+// their keys. The maps whose elements can not hold leaves are skipped, their
+// keys are not checked and their elements are not written back.
+func (w *walker) Map(value reflect.Value) error {
+	if !containsType(value.Type().Elem(), w.target) {
+		return reflectwalk.ErrSkipEntry
+	}
+	if value.IsNil() {
+		fmt.Printf("ignoring uninitialized map: %s %s\n", w.name(), value.Type().String())
+		return reflectwalk.ErrSkipEntry
+	}
+	if !isNameableKey(value.Type().Key()) {
+		return fmt.Errorf("%s: unsupported map key type %s, must be a string, an integer or implement encoding.TextMarshaler", w.name(), value.Type().Key())
+	}
+	return nil
+}
+
+func (w *walker) MapElem(k, v reflect.Value) error {
+	name, err := mapKeyName(k)
+	if err != nil {
+		return fmt.Errorf("%s: %w", w.name(), err)
+	}
+	return w.arraySliceElem(name, v)
+}
+
+var tTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// isNameableKey returns true if the map keys of type t can be converted to a
+// name, with the same rules as encoding/json.
+func isNameableKey(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return t.Implements(tTextMarshaler)
+}
+
+// mapKeyName returns the string form of the map key k.
+func mapKeyName(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("map key %v: %w", k.Interface(), err)
+		}
+		return string(b), nil
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %s", k.Type())
 }
 
 // process an array or slice of leaves; since it's quite common to have large array/slices
@@ -127,38 +292,68 @@ func (w *walker) handleLeaves(value reflect.Value) error {
 		v = Secret
 	}
 
-	// call the handler.
-	if w.handler != nil {
-		n := w.name()
-		for i := 0; i < value.Len(); i++ {
+	n := w.name()
+	commit, static, bits, rng, boolean := w.commit(), w.static(), w.bits(), w.rangeBits(), w.boolean()
+	index := w.nbPublic + w.nbSecret
+	nbLeaves := 0
+	for i := 0; i < value.Len(); i++ {
+		vv := value.Index(i)
+		if isConstant(vv) {
+			continue
+		}
+		// the name of the element is built once, for the checks and the
+		// handler
+		name := w.joinName(n, w.indexName(i, value.Len()))
+		if len(w.constants) > 0 && w.constants[name] {
+			continue
+		}
+		if err := w.checkName(name, index+nbLeaves); err != nil {
+			w.errs = append(w.errs, err)
+			continue
+		}
+		nbLeaves++
+		// call the handler.
+		if w.handler != nil {
 			fName := func() string {
-				return n + "_" + strconv.Itoa(i)
+				return name
 			}
-			vv := value.Index(i)
-			if err := w.handler(LeafInfo{Visibility: v, FullName: fName, name: ""}, vv); err != nil {
-				return err
+			if err := w.handler(LeafInfo{Visibility: v, FullName: fName, Commit: commit, Static: static, Bits: bits, Range: rng, Boolean: boolean, name: ""}, vv); err != nil {
+				w.errs = append(w.errs, err)
 			}
 		}
 	}
 
 	if v == Secret {
-		w.nbSecret += value.Len()
+		w.nbSecret += nbLeaves
 	} else if v == Public {
-		w.nbPublic += value.Len()
+		w.nbPublic += nbLeaves
 	}
 
 	return reflectwalk.ErrSkipEntry
 }
 
-func (w *walker) Struct(reflect.Value) error {
+func (w *walker) Struct(v reflect.Value) error {
+	if isOpaque(v.Type()) {
+		// the fields are not walked, but the struct is exited
+		w.structs = append(w.structs, nil)
+		return reflectwalk.ErrSkipEntry
+	}
+	w.structs = append(w.structs, structFields(v.Type(), w.target))
 	return nil
 }
 
 func (w *walker) StructField(sf reflect.StructField, v reflect.Value) error {
-	// check if the gnark tag is set
-	tag, ok := sf.Tag.Lookup(string(tagKey))
-	if ok && tag == string(TagOptOmit) {
-		return reflectwalk.ErrSkipEntry // skipping "-"
+	f := &w.structs[len(w.structs)-1][sf.Index[0]]
+	if f.omit || f.opaque {
+		return reflectwalk.ErrSkipEntry // skipping "-" and the opaque types
+	}
+
+	// the leaves of unexported fields can not be set, they are skipped
+	if f.unexportedLeaf {
+		if err := w.unexported(w.joinName(w.name(), sf.Name), sf.Type); err != nil {
+			w.errs = append(w.errs, err)
+		}
+		return reflectwalk.ErrSkipEntry
 	}
 
 	if v.CanAddr() && v.Addr().CanInterface() {
@@ -170,60 +365,184 @@ func (w *walker) StructField(sf reflect.StructField, v reflect.Value) error {
 		}
 	}
 
-	// default visibility: parent (or unset)
-	parentVisibility := w.visibility()
+	// default visibility: parent (or unset), an explicit tag on the field wins
 	info := LeafInfo{
 		name:       sf.Name,
-		Visibility: parentVisibility,
+		Visibility: w.visibility(),
+		Commit:     w.commit() || f.commit,
+		Static:     w.static() || f.static,
+		Bits:       w.bits(),
+		Range:      w.rangeBits(),
+		Boolean:    w.boolean() || f.boolean,
 	}
 
-	var nameInTag string
-
-	if ok && tag != "" {
-		// gnark tag is set
-		var opts tagOptions
-		nameInTag, opts = parseTag(tag)
-		if !isValidTag(nameInTag) {
-			nameInTag = ""
+	if !f.validName {
+		w.errs = append(w.errs, checkTagName(f.nameTag, w.joinName(w.name(), sf.Name)))
+		return reflectwalk.ErrSkipEntry
+	}
+	if w.strictTags && len(f.unknown) > 0 {
+		for _, opt := range f.unknown {
+			w.errs = append(w.errs, fmt.Errorf("unknown gnark tag option %q on field %s", opt, w.joinName(w.name(), sf.Name)))
 		}
-		if nameInTag != "" {
-			info.name = nameInTag
+		return reflectwalk.ErrSkipEntry
+	}
+	if f.nameTag != "" {
+		info.name = f.nameTag
+	}
+	if f.visibility != Unset {
+		info.Visibility = f.visibility
+	}
+	if f.hasBits {
+		if f.nbBits == 0 {
+			w.errs = append(w.errs, fmt.Errorf("%s: invalid %q option %q, must be a positive integer", sf.Name, TagOptBits, f.bits))
+			return reflectwalk.ErrSkipEntry
 		}
-		opts = tagOptions(strings.TrimSpace(string(opts)))
-		switch {
-		case opts.contains(TagOptSecret):
-			info.Visibility = Secret
-		case opts.contains(TagOptPublic):
-			info.Visibility = Public
+		info.Bits = f.nbBits
+	}
+	if f.hasRange {
+		if f.nbRange == 0 {
+			w.errs = append(w.errs, fmt.Errorf("%s: invalid %q option %q, must be a positive integer", sf.Name, TagOptRange, f.rng))
+			return reflectwalk.ErrSkipEntry
 		}
+		info.Range = f.nbRange
 	}
 
-	if parentVisibility != Unset && parentVisibility != info.Visibility {
-		parentName := w.name()
-		if parentName == "" {
-			parentName = info.name
-		} else {
-			parentName += "_" + info.name
-		}
-		return fmt.Errorf("conflicting visibility. %s (%s) has a parent with different visibility attribute", parentName, info.Visibility.String())
+	if info.Commit && info.Visibility == Public {
+		name := w.joinName(w.name(), info.name)
+		w.errs = append(w.errs, fmt.Errorf("%s: only secret elements can be tagged with %q", name, TagOptCommit))
+		return reflectwalk.ErrSkipEntry
 	}
 
+	// the fields of an embedded struct are in the namespace of its parent,
+	// unless the tag sets a name
+	info.embedded = f.embedded
+
 	w.path.push(info)
 
 	return nil
 }
 
+// containsType returns true if t is target or if values of type t can hold
+// values of type target.
+func containsType(t, target reflect.Type) bool {
+	return containsTypeRec(t, target, false, make(map[reflect.Type]bool))
+}
+
+// containsSettable is as containsType, but ignores the unexported fields of
+// the structs, whose leaves can not be set.
+func containsSettable(t, target reflect.Type) bool {
+	return containsTypeRec(t, target, true, make(map[reflect.Type]bool))
+}
+
+func containsTypeRec(t, target reflect.Type, exportedOnly bool, visited map[reflect.Type]bool) bool {
+	if t == target {
+		return true
+	}
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return containsTypeRec(t.Elem(), target, exportedOnly, visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Tag.Get(string(tagKey)) == string(TagOptOmit) || (exportedOnly && !f.IsExported() && !f.Anonymous) {
+				continue
+			}
+			if containsTypeRec(f.Type, target, exportedOnly, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allocOnNil returns true if a nil pointer of type t is set to a new value by
+// the walk: pointers to a leaf, and pointers to structs which hold leaves in
+// exported fields. The unexported leaves would not be inputs of the circuit.
+// A struct which holds a pointer to itself is left nil, its allocation would
+// not end.
+func allocOnNil(t, target reflect.Type) bool {
+	elem := t.Elem()
+	if elem == target {
+		return true
+	}
+	if elem.Kind() != reflect.Struct || isOpaque(elem) || !containsSettable(elem, target) {
+		return false
+	}
+	return !pointsTo(elem, elem, make(map[reflect.Type]bool))
+}
+
+// pointsTo returns true if the values of type t hold, through their fields,
+// arrays and nested pointers, a pointer to a value of type target.
+func pointsTo(t, target reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+	switch t.Kind() {
+	case reflect.Pointer:
+		return t.Elem() == target || pointsTo(t.Elem(), target, visited)
+	case reflect.Array:
+		return pointsTo(t.Elem(), target, visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if pointsTo(t.Field(i).Type, target, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (w *walker) Enter(l reflectwalk.Location) error {
 	return nil
 }
 
 func (w *walker) Exit(l reflectwalk.Location) error {
-	if l == reflectwalk.StructField || l == reflectwalk.ArrayElem || l == reflectwalk.SliceElem {
+	if l == reflectwalk.StructField || l == reflectwalk.ArrayElem || l == reflectwalk.SliceElem || l == reflectwalk.MapValue {
 		w.path.pop()
 	}
+	if l == reflectwalk.Struct {
+		w.structs = w.structs[:len(w.structs)-1]
+	}
+	if l == reflectwalk.Slice || l == reflectwalk.Array {
+		w.lengths = w.lengths[:len(w.lengths)-1]
+	}
 	return nil
 }
 
+var tBigInt = reflect.TypeOf(big.Int{})
+
+// isOpaque returns true if t is big.Int or *big.Int. They are often set in
+// the circuits as parameters, for example a modulus, but never hold leaves:
+// their fields are not walked.
+func isOpaque(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t == tBigInt
+}
+
+// CircuitAPI is the marker of the interfaces of the circuit API, for example
+// frontend.API and frontend.Compiler. The gadgets often keep the API in a
+// field: the walk and the schema do not go through the fields whose type is
+// an interface implementing CircuitAPI.
+type CircuitAPI interface {
+	GnarkAPI()
+}
+
+var tCircuitAPI = reflect.TypeOf((*CircuitAPI)(nil)).Elem()
+
+// isAPI returns true if t is an interface type of the circuit API. We can not
+// refer to these types directly (import cycle), so they are recognized by the
+// [CircuitAPI] marker.
+func isAPI(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && t.Implements(tCircuitAPI)
+}
+
 // defaults to unset
 func (w *walker) visibility() Visibility {
 	if !w.path.isEmpty() {
@@ -232,22 +551,75 @@ func (w *walker) visibility() Visibility {
 	return Unset
 }
 
+// commit returns true if the current element is bound to the public commitment.
+func (w *walker) commit() bool {
+	if !w.path.isEmpty() {
+		return w.path.top().Commit
+	}
+	return false
+}
+
+// static returns true if the current element is shared by many witnesses.
+func (w *walker) static() bool {
+	if !w.path.isEmpty() {
+		return w.path.top().Static
+	}
+	return false
+}
+
+// bits returns the expected bit width of the current element, 0 if unset.
+func (w *walker) bits() int {
+	if !w.path.isEmpty() {
+		return w.path.top().Bits
+	}
+	return 0
+}
+
+// rangeBits returns the bit width the current element is constrained to, 0 if
+// unset.
+func (w *walker) rangeBits() int {
+	if !w.path.isEmpty() {
+		return w.path.top().Range
+	}
+	return 0
+}
+
+// boolean returns true if the current element is constrained to be a boolean.
+func (w *walker) boolean() bool {
+	if !w.path.isEmpty() {
+		return w.path.top().Boolean
+	}
+	return false
+}
+
 func (w *walker) name() string {
 	if w.path.isEmpty() {
 		return ""
 	}
-	var sbb strings.Builder
-	sbb.Grow(w.path.len() * 10)
-	first := true
-	for i := 0; i < w.path.len(); i++ {
-		if !first {
-			sbb.WriteByte('_')
-		} else {
+	strategy := w.names
+	if sep, ok := strategy.(Separator); ok {
+		var sbb strings.Builder
+		sbb.Grow(w.path.len() * 10)
+		first := true
+		for i := 0; i < w.path.len(); i++ {
+			if w.path[i].embedded {
+				continue
+			}
+			if !first {
+				sbb.WriteString(string(sep))
+			}
+			sbb.WriteString(w.path[i].name)
 			first = false
 		}
-		sbb.WriteString(w.path[i].name)
+		return sbb.String()
+	}
+	name := ""
+	for i := 0; i < w.path.len(); i++ {
+		if !w.path[i].embedded {
+			name = w.joinName(name, w.path[i].name)
+		}
 	}
-	return sbb.String()
+	return name
 }
 
 type pathStack []LeafInfo
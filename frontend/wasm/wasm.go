@@ -0,0 +1,69 @@
+// Package wasm provides an entry point for solving witnesses in environments
+// without a filesystem or native threads, such as GOOS=js GOARCH=wasm in a
+// browser.
+//
+// The witness is built from a JSON assignment, checked against the constraint
+// system of the circuit and returned in its binary encoding, which can be
+// passed to a prover. The constraint system is compiled once, or shipped in
+// its serialized form and read with [ReadConstraintSystem].
+package wasm
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+var tVariable = reflect.TypeOf((*frontend.Variable)(nil)).Elem()
+
+// Solve builds the full witness of circuit from the JSON assignment, with the
+// format of [witness.Witness.FromJSON], and checks that it satisfies ccs, the
+// constraint system of circuit. It returns the binary encoding of the witness,
+// see [witness.Witness.MarshalBinary].
+//
+// The circuit only gives the layout of the inputs, its slices must be
+// allocated with their sizes.
+func Solve(ccs constraint.ConstraintSystem, circuit frontend.Circuit, assignmentJSON string) ([]byte, error) {
+	s, err := schema.New(circuit, tVariable)
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	w, err := witness.New(ccs.Field())
+	if err != nil {
+		return nil, err
+	}
+	if err := w.FromJSON(s, []byte(assignmentJSON)); err != nil {
+		return nil, fmt.Errorf("parse assignment: %w", err)
+	}
+	if err := ccs.IsSolved(w); err != nil {
+		return nil, fmt.Errorf("solve: %w", err)
+	}
+	return w.MarshalBinary()
+}
+
+// ReadConstraintSystem decodes the constraint system over curve for the given
+// backend, Groth16 or PLONK, serialized with its WriteTo method.
+func ReadConstraintSystem(curve ecc.ID, b backend.ID, data []byte) (constraint.ConstraintSystem, error) {
+	var ccs constraint.ConstraintSystem
+	switch b {
+	case backend.GROTH16:
+		ccs = groth16.NewCS(curve)
+	case backend.PLONK:
+		ccs = plonk.NewCS(curve)
+	default:
+		return nil, fmt.Errorf("unsupported backend %s", b)
+	}
+	if _, err := ccs.ReadFrom(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("read constraint system: %w", err)
+	}
+	return ccs, nil
+}
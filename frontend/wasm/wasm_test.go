@@ -0,0 +1,64 @@
+package wasm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+func TestSolve(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	compiled, err := frontend.Compile(field, scs.NewBuilder, &cubicCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := compiled.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	ccs, err := ReadConstraintSystem(ecc.BN254, backend.PLONK, buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Solve(ccs, &cubicCircuit{}, `{"X": 3, "Y": 35}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, field)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := w.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, expected) {
+		t.Fatal("unexpected witness encoding")
+	}
+
+	if _, err := Solve(ccs, &cubicCircuit{}, `{"X": 3, "Y": 36}`); err == nil {
+		t.Fatal("expected an error for an invalid assignment")
+	}
+	if _, err := Solve(ccs, &cubicCircuit{}, `{"X": 3`); err == nil {
+		t.Fatal("expected an error for an invalid JSON")
+	}
+	if _, err := ReadConstraintSystem(ecc.BN254, backend.PLONK, buf.Bytes()[:10]); err == nil {
+		t.Fatal("expected an error for a truncated constraint system")
+	}
+}
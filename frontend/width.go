@@ -0,0 +1,17 @@
+package frontend
+
+// AnnotateWidth declares that v is expected to fit in nbBits bits. The
+// annotation is only recorded in the constraint system: it adds no constraint
+// nor wire and is not a range check. It is verified when solving with
+// [github.com/consensys/gnark/constraint/solver.WithWidthChecks], to detect
+// modeling bugs where a supposedly small value wrapped around the field
+// modulus. It is ignored by the compilers which do not implement
+// [WidthAnnotator].
+func AnnotateWidth(api API, v Variable, nbBits int) {
+	if nbBits < 0 {
+		panic("negative width")
+	}
+	if a, ok := api.Compiler().(WidthAnnotator); ok {
+		a.AnnotateWidth(v, nbBits)
+	}
+}
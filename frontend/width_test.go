@@ -0,0 +1,65 @@
+package frontend_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+type widthCircuit struct {
+	X, Y     frontend.Variable
+	annotate bool
+}
+
+func (c *widthCircuit) Define(api frontend.API) error {
+	// X - Y wraps around when Y > X
+	d := api.Sub(c.X, c.Y)
+	if c.annotate {
+		frontend.AnnotateWidth(api, d, 8)
+		frontend.AnnotateWidth(api, 255, 8)
+	}
+	api.AssertIsDifferent(d, 0)
+	return nil
+}
+
+func TestAnnotateWidth(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	valid, err := frontend.NewWitness(&widthCircuit{X: 3, Y: 1}, field)
+	if err != nil {
+		t.Fatal(err)
+	}
+	overflow, err := frontend.NewWitness(&widthCircuit{X: 1, Y: 3}, field)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, newBuilder := range map[string]frontend.NewBuilder{"r1cs": r1cs.NewBuilder, "scs": scs.NewBuilder} {
+		t.Run(name, func(t *testing.T) {
+			ccs, err := frontend.Compile(field, newBuilder, &widthCircuit{annotate: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			plain, err := frontend.Compile(field, newBuilder, &widthCircuit{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the annotations are neither constraints nor wires
+			if ccs.GetNbInternalVariables() != plain.GetNbInternalVariables() || ccs.GetNbConstraints() != plain.GetNbConstraints() {
+				t.Fatalf("annotations added wires or constraints: %d/%d, expected %d/%d",
+					ccs.GetNbInternalVariables(), ccs.GetNbConstraints(), plain.GetNbInternalVariables(), plain.GetNbConstraints())
+			}
+			if err := ccs.IsSolved(valid, solver.WithWidthChecks()); err != nil {
+				t.Fatal(err)
+			}
+			if err := ccs.IsSolved(overflow); err != nil {
+				t.Fatal(err)
+			}
+			if err := ccs.IsSolved(overflow, solver.WithWidthChecks()); err == nil {
+				t.Fatal("expected overflow to be detected")
+			}
+		})
+	}
+}
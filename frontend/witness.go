@@ -1,17 +1,30 @@
 package frontend
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 	"reflect"
+	"strings"
 
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/utils"
 )
 
 // NewWitness build an ordered vector of field elements from the given assignment (Circuit)
 // if PublicOnly is specified, returns the public part of the witness only
 // else returns [public | secret]. The result can then be serialized to / from json & binary.
 //
+// The values of the inputs tagged with [schema.TagOptBits] are checked to fit
+// in the declared width, and an error is returned otherwise.
+//
+// The values may be given as []byte, interpreted in big-endian order, or as
+// hex strings prefixed with 0x, for example for hash digests or addresses.
+// Unlike the integers, these values are not reduced modulo field: an error is
+// returned if they are larger than or equal to the modulus, as a reduced
+// digest would give a valid proof for another value.
+//
 // See ExampleWitness in witness package for usage.
 func NewWitness(assignment Circuit, field *big.Int, opts ...WitnessOption) (witness.Witness, error) {
 	opt, err := options(opts...)
@@ -20,10 +33,32 @@ func NewWitness(assignment Circuit, field *big.Int, opts ...WitnessOption) (witn
 	}
 
 	// count the leaves
-	s, err := schema.Walk(assignment, tVariable, nil)
+	var nbStaticPublic, nbStaticSecret int
+	s, err := schema.Walk(assignment, tVariable, func(leaf schema.LeafInfo, tValue reflect.Value) error {
+		if leaf.Static && leaf.Visibility == schema.Public {
+			nbStaticPublic++
+		} else if leaf.Static {
+			nbStaticSecret++
+		}
+		// the values taken from the static witness were checked when building it.
+		if (opt.static != nil && leaf.Static) || (opt.publicOnly && leaf.Visibility == schema.Secret) || tValue.IsNil() {
+			return nil
+		}
+		if err := checkValue(leaf, tValue.Interface(), field); err != nil {
+			return err
+		}
+		if leaf.Bits == 0 {
+			return nil
+		}
+		v := utils.FromInterface(tValue.Interface())
+		return checkBits(leaf, &v)
+	})
 	if err != nil {
 		return nil, err
 	}
+	if opt.static != nil && (len(opt.static.public) != nbStaticPublic || len(opt.static.secret) != nbStaticSecret) {
+		return nil, errors.New("static witness does not match the assignment")
+	}
 	if opt.publicOnly {
 		s.Secret = 0
 	}
@@ -34,20 +69,34 @@ func NewWitness(assignment Circuit, field *big.Int, opts ...WitnessOption) (witn
 		return nil, err
 	}
 
+	// the values of the static leaves are taken from the static witness, if any.
+	var staticPublic, staticSecret []any
+	if opt.static != nil {
+		staticPublic, staticSecret = opt.static.public, opt.static.secret
+	}
+	value := func(leaf schema.LeafInfo, tValue reflect.Value, static *[]any) any {
+		if opt.static == nil || !leaf.Static {
+			return tValue.Interface()
+		}
+		v := (*static)[0]
+		*static = (*static)[1:]
+		return v
+	}
+
 	// write the public | secret values in a chan
 	chValues := make(chan any)
 	go func() {
 		defer close(chValues)
 		schema.Walk(assignment, tVariable, func(leaf schema.LeafInfo, tValue reflect.Value) error {
 			if leaf.Visibility == schema.Public {
-				chValues <- tValue.Interface()
+				chValues <- value(leaf, tValue, &staticPublic)
 			}
 			return nil
 		})
 		if !opt.publicOnly {
 			schema.Walk(assignment, tVariable, func(leaf schema.LeafInfo, tValue reflect.Value) error {
 				if leaf.Visibility == schema.Secret {
-					chValues <- tValue.Interface()
+					chValues <- value(leaf, tValue, &staticSecret)
 				}
 				return nil
 			})
@@ -60,11 +109,100 @@ func NewWitness(assignment Circuit, field *big.Int, opts ...WitnessOption) (witn
 	return w, nil
 }
 
+// StaticWitness stores the values of the inputs of an assignment tagged with
+// [schema.TagOptStatic]. It allows to build many witnesses sharing these values
+// with [WithStatic], without setting them in every assignment. It can be
+// stored across runs with a [WitnessCache].
+//
+// Only the input values are cached, to save parsing and reducing them for
+// every witness. No solved wire is reused: the solver still solves all the
+// wires for every proof, including the ones which only depend on static inputs.
+type StaticWitness struct {
+	public, secret []any
+}
+
+// NewStaticWitness returns the values of the inputs of assignment tagged with
+// [schema.TagOptStatic]. The values are reduced modulo field once, so that
+// they are not parsed again for every witness.
+func NewStaticWitness(assignment Circuit, field *big.Int) (*StaticWitness, error) {
+	res := new(StaticWitness)
+	_, err := schema.Walk(assignment, tVariable, func(leaf schema.LeafInfo, tValue reflect.Value) error {
+		if !leaf.Static {
+			return nil
+		}
+		if tValue.IsNil() {
+			return errors.New("static input " + leaf.FullName() + " is not assigned")
+		}
+		if err := checkValue(leaf, tValue.Interface(), field); err != nil {
+			return err
+		}
+		v := utils.FromInterface(tValue.Interface())
+		if err := checkBits(leaf, &v); err != nil {
+			return err
+		}
+		v.Mod(&v, field)
+		if leaf.Visibility == schema.Public {
+			res.public = append(res.public, &v)
+		} else {
+			res.secret = append(res.secret, &v)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// checkValue returns an error if the value assigned to the leaf can not be
+// converted to a field element: the accepted values are the integers, big.Int,
+// *big.Int, []byte, the strings encoding an integer and the field elements.
+// The []byte and the hex strings must be smaller than the modulus field.
+func checkValue(leaf schema.LeafInfo, v any, field *big.Int) error {
+	switch v := v.(type) {
+	case int, int64, uint64, *big.Int, int8, int16, int32, uint, uint8, uint16, uint32, big.Int:
+		return nil
+	case []byte:
+		return checkModulus(leaf, new(big.Int).SetBytes(v), field)
+	case string:
+		b, ok := new(big.Int).SetString(v, 0)
+		if !ok {
+			return fmt.Errorf("%s: can't parse %q as an integer", leaf.FullName(), v)
+		}
+		if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+			return checkModulus(leaf, b, field)
+		}
+		return nil
+	case interface{ ToBigIntRegular(*big.Int) *big.Int }:
+		// field elements
+		return nil
+	}
+	return fmt.Errorf("%s: can't assign a value of type %T, expected an integer, a big.Int, a string or a field element", leaf.FullName(), v)
+}
+
+// checkModulus returns an error if v is not smaller than the modulus field.
+func checkModulus(leaf schema.LeafInfo, v, field *big.Int) error {
+	if v.Cmp(field) >= 0 {
+		return fmt.Errorf("%s: value 0x%s does not fit in the field of modulus 0x%s", leaf.FullName(), v.Text(16), field.Text(16))
+	}
+	return nil
+}
+
+// checkBits returns an error if the leaf is tagged with [schema.TagOptBits] and
+// v is not in [0, 2^leaf.Bits).
+func checkBits(leaf schema.LeafInfo, v *big.Int) error {
+	if leaf.Bits > 0 && (v.Sign() < 0 || v.BitLen() > leaf.Bits) {
+		return fmt.Errorf("%s: value %s does not fit in %d bits", leaf.FullName(), v.String(), leaf.Bits)
+	}
+	return nil
+}
+
 // NewSchema returns the schema corresponding to the circuit structure.
 //
-// This is used to JSON (un)marshall witnesses.
-func NewSchema(circuit Circuit) (*schema.Schema, error) {
-	return schema.New(circuit, tVariable)
+// This is used to JSON (un)marshall witnesses. The options must name the
+// inputs as the compiler does, see [WithNameStrategy].
+func NewSchema(circuit Circuit, opts ...schema.Option) (*schema.Schema, error) {
+	return schema.New(circuit, tVariable, opts...)
 }
 
 // default options
@@ -87,6 +225,7 @@ type WitnessOption func(*witnessConfig) error
 
 type witnessConfig struct {
 	publicOnly bool
+	static     *StaticWitness
 }
 
 // PublicOnly enables to instantiate a witness with the public part only of the assignment
@@ -96,3 +235,18 @@ func PublicOnly() WitnessOption {
 		return nil
 	}
 }
+
+// WithStatic takes the values of the inputs tagged with [schema.TagOptStatic]
+// from static instead of the assignment, which may leave them nil (slices must
+// still have their length set). static must have been built from an assignment
+// of the same circuit.
+//
+// It only saves building the static values of the witness. The wires which
+// depend on the static inputs only are not reused: proving solves all the
+// wires of the circuit for every witness, see [StaticWitness].
+func WithStatic(static *StaticWitness) WitnessOption {
+	return func(opt *witnessConfig) error {
+		opt.static = static
+		return nil
+	}
+}
@@ -0,0 +1,170 @@
+package frontend
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+)
+
+// WitnessCache stores a [StaticWitness] in a file, so that the values of the
+// inputs tagged with [schema.TagOptStatic], for example the Merkle paths in a
+// static tree, are computed once and reused across runs. Only the dynamic
+// inputs are assigned for every proof, with [WithStatic].
+//
+// It adds no caching of its own to [StaticWitness]: it only serializes one, so
+// that the cost of computing the static values, not the one of parsing them,
+// is paid once per file instead of once per process. As for [StaticWitness],
+// only the input values are stored, no solved wire.
+//
+// The file holds the modulus of the field and the reduced values, it is bound
+// to the field but not to the circuit: loading it for another circuit with as
+// many static inputs is not detected.
+type WitnessCache struct {
+	path  string
+	field *big.Int
+}
+
+// NewWitnessCache returns a cache of the static witness over field, stored in
+// the file at path.
+func NewWitnessCache(path string, field *big.Int) *WitnessCache {
+	return &WitnessCache{path: path, field: new(big.Int).Set(field)}
+}
+
+// Static returns the cached static witness. If the file does not exist, the
+// assignment returned by compute is used to build it and it is saved in the
+// cache before it is returned.
+func (c *WitnessCache) Static(compute func() (Circuit, error)) (*StaticWitness, error) {
+	static, err := c.Load()
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return static, err
+	}
+	assignment, err := compute()
+	if err != nil {
+		return nil, err
+	}
+	if static, err = NewStaticWitness(assignment, c.field); err != nil {
+		return nil, err
+	}
+	if err := c.Save(static); err != nil {
+		return nil, err
+	}
+	return static, nil
+}
+
+// Save writes static to the file of the cache, replacing the previous one.
+func (c *WitnessCache) Save(static *StaticWitness) error {
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	w := bufio.NewWriter(tmp)
+	if err := c.write(w, static); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// the file is replaced at once, a concurrent Load never reads a partial one
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// Load reads the static witness from the file of the cache. The error wraps
+// [os.ErrNotExist] if the file does not exist.
+func (c *WitnessCache) Load() (*StaticWitness, error) {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	static, err := c.read(bufio.NewReader(f), info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("witness cache %s: %w", c.path, err)
+	}
+	return static, nil
+}
+
+// write encodes static as the modulus and the number of public and secret
+// values, followed by the values, each on as many bytes as the modulus.
+func (c *WitnessCache) write(w io.Writer, static *StaticWitness) error {
+	modulus := c.field.Bytes()
+	if err := binary.Write(w, binary.BigEndian, uint32(len(modulus))); err != nil {
+		return err
+	}
+	if _, err := w.Write(modulus); err != nil {
+		return err
+	}
+	nbValues := [2]uint32{uint32(len(static.public)), uint32(len(static.secret))}
+	if err := binary.Write(w, binary.BigEndian, nbValues); err != nil {
+		return err
+	}
+	buf := make([]byte, len(modulus))
+	for _, values := range [][]any{static.public, static.secret} {
+		for _, v := range values {
+			v.(*big.Int).FillBytes(buf)
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// read decodes a static witness written by write. size is the number of bytes
+// of r, it bounds the number of values read.
+func (c *WitnessCache) read(r io.Reader, size int64) (*StaticWitness, error) {
+	var nbBytes uint32
+	if err := binary.Read(r, binary.BigEndian, &nbBytes); err != nil {
+		return nil, err
+	}
+	modulus := c.field.Bytes()
+	if int(nbBytes) != len(modulus) {
+		return nil, errors.New("cached for another field")
+	}
+	buf := make([]byte, nbBytes)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	if new(big.Int).SetBytes(buf).Cmp(c.field) != 0 {
+		return nil, errors.New("cached for another field")
+	}
+	var nbValues [2]uint32
+	if err := binary.Read(r, binary.BigEndian, &nbValues); err != nil {
+		return nil, err
+	}
+	// the counts are checked before allocating, a corrupted file must not
+	// allocate more values than it holds
+	remaining := size - 4 - int64(nbBytes) - 8
+	if int64(nbValues[0])+int64(nbValues[1]) != remaining/int64(nbBytes) {
+		return nil, fmt.Errorf("%d values in %d bytes", int64(nbValues[0])+int64(nbValues[1]), remaining)
+	}
+	res := new(StaticWitness)
+	for i, values := range []*[]any{&res.public, &res.secret} {
+		*values = make([]any, nbValues[i])
+		for j := range *values {
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return nil, err
+			}
+			v := new(big.Int).SetBytes(buf)
+			if v.Cmp(c.field) >= 0 {
+				return nil, errors.New("cached value larger than the modulus")
+			}
+			(*values)[j] = v
+		}
+	}
+	return res, nil
+}
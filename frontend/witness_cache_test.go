@@ -0,0 +1,83 @@
+package frontend_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+func TestWitnessCache(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, &staticCircuit{Table: make([]frontend.Variable, staticTableSize)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "static.bin")
+	if _, err := frontend.NewWitnessCache(path, field).Load(); !os.IsNotExist(err) {
+		t.Fatalf("expected missing cache, got %v", err)
+	}
+	nbComputed := 0
+	compute := func() (frontend.Circuit, error) {
+		nbComputed++
+		return newStaticAssignment(0), nil
+	}
+
+	// the static values are computed on the first run only, and loaded from
+	// the file in the next ones
+	for run := 0; run < 3; run++ {
+		static, err := frontend.NewWitnessCache(path, field).Static(compute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x := run + 1
+		dynamic := &staticCircuit{Table: make([]frontend.Variable, staticTableSize), X: x, Y: 7 * x}
+		w, err := frontend.NewWitness(dynamic, field, frontend.WithStatic(static))
+		if err != nil {
+			t.Fatal(err)
+		}
+		proof, err := groth16.Prove(ccs, pk, w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicWitness, err := w.Public()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := groth16.Verify(proof, vk, publicWitness); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if nbComputed != 1 {
+		t.Fatalf("static witness computed %d times, expected once", nbComputed)
+	}
+
+	// the cache is bound to the field
+	if _, err := frontend.NewWitnessCache(path, ecc.BLS12_381.ScalarField()).Load(); err == nil {
+		t.Fatal("expected error for a cache of another field")
+	}
+
+	// the counts of values are bounded by the size of the file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := 4 + len(field.Bytes())
+	copy(data[counts:], []byte{0xff, 0xff, 0xff, 0xff})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := frontend.NewWitnessCache(path, field).Load(); err == nil || !strings.Contains(err.Error(), "values in") {
+		t.Fatalf("expected error for corrupted counts, got %v", err)
+	}
+}
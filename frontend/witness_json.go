@@ -0,0 +1,176 @@
+package frontend
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// witnessJSON is the JSON form of an assignment: the values of the public and
+// secret inputs, keyed by their full names.
+type witnessJSON struct {
+	Public map[string]string `json:"public"`
+	Secret map[string]string `json:"secret"`
+}
+
+// MarshalWitnessJSON returns the JSON encoding of the assignment of a circuit,
+// an object with the values of the public and of the secret inputs keyed by
+// their full names, as named by the compiler:
+//
+//	{"public": {"X": "35"}, "secret": {"Path_0": "1", "Path_1": "0"}}
+//
+// The values are encoded as decimal strings, so that the large field elements
+// are not rounded by the JSON decoders. They are not reduced: a negative
+// integer is encoded with its sign. The assignment must be a pointer.
+//
+// An error is returned for every unassigned input, unless [AllowUnassigned] is
+// set, in which case they are omitted.
+func MarshalWitnessJSON(assignment interface{}, opts ...WitnessJSONOption) ([]byte, error) {
+	cfg := newWitnessJSONConfig(opts)
+	res := witnessJSON{Public: make(map[string]string), Secret: make(map[string]string)}
+	err := walk(assignment, func(f schema.LeafInfo, v *Variable) error {
+		if *v == nil {
+			if cfg.allowUnassigned {
+				return nil
+			}
+			return fmt.Errorf("%s: input not assigned", f.FullName())
+		}
+		if IsCanonical(*v) {
+			return fmt.Errorf("%s: input holds a wire of a compiled circuit, not a value", f.FullName())
+		}
+		value, err := decimal(*v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.FullName(), err)
+		}
+		if f.Visibility == schema.Public {
+			res.Public[f.FullName()] = value
+		} else {
+			res.Secret[f.FullName()] = value
+		}
+		return nil
+	}, schema.WithNameStrategy(cfg.names))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(res)
+}
+
+// UnmarshalWitnessJSON sets the inputs of the assignment of a circuit to the
+// values of their full names in data, as encoded by [MarshalWitnessJSON]. The
+// assignment must be a pointer, with the sizes of its slices set.
+//
+// An error is returned for every name of data which is not an input of the
+// circuit, for every input given with another visibility, for example a secret
+// input in the public object, and for every input left unassigned, unless
+// [AllowUnassigned] is set. The inputs absent from data keep their values.
+func UnmarshalWitnessJSON(data []byte, assignment interface{}, opts ...WitnessJSONOption) error {
+	if reflect.ValueOf(assignment).Kind() != reflect.Ptr {
+		return errors.New("circuit must be a pointer")
+	}
+	cfg := newWitnessJSONConfig(opts)
+	var w witnessJSON
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&w); err != nil {
+		return err
+	}
+	used := make(map[string]bool)
+	err := walk(assignment, func(f schema.LeafInfo, v *Variable) error {
+		values, others := w.Secret, w.Public
+		if f.Visibility == schema.Public {
+			values, others = w.Public, w.Secret
+		}
+		name := f.FullName()
+		if _, ok := others[name]; ok {
+			used[name] = true
+			return fmt.Errorf("%s: %s input given with another visibility", name, f.Visibility)
+		}
+		s, ok := values[name]
+		if !ok {
+			if *v == nil && !cfg.allowUnassigned {
+				return fmt.Errorf("%s: input not assigned", name)
+			}
+			return nil
+		}
+		used[name] = true
+		value, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return fmt.Errorf("%s: can't parse %q as a decimal integer", name, s)
+		}
+		*v = value
+		return nil
+	}, schema.WithNameStrategy(cfg.names))
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	// the names which are not inputs of the circuit, in a deterministic order
+	var unknown []string
+	for _, values := range []map[string]string{w.Public, w.Secret} {
+		for name := range values {
+			if !used[name] {
+				unknown = append(unknown, name)
+			}
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		errs = append(errs, fmt.Errorf("%s: not an input of the circuit", name))
+	}
+	return errors.Join(errs...)
+}
+
+// decimal returns the decimal string of the value v.
+func decimal(v Variable) (s string, err error) {
+	// FromInterface panics on the unsupported types
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("can't encode a value of type %s", reflect.TypeOf(v))
+		}
+	}()
+	b := utils.FromInterface(v)
+	return b.String(), nil
+}
+
+// WitnessJSONOption sets optional parameters of the JSON encoding of the
+// assignments.
+type WitnessJSONOption func(*witnessJSONConfig)
+
+type witnessJSONConfig struct {
+	allowUnassigned bool
+	names           schema.NameStrategy
+}
+
+func newWitnessJSONConfig(opts []WitnessJSONOption) witnessJSONConfig {
+	var cfg witnessJSONConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// AllowUnassigned makes [MarshalWitnessJSON] omit the unassigned inputs
+// instead of returning an error, and [UnmarshalWitnessJSON] accept the inputs
+// left unassigned.
+func AllowUnassigned() WitnessJSONOption {
+	return func(cfg *witnessJSONConfig) {
+		cfg.allowUnassigned = true
+	}
+}
+
+// WithJSONNameStrategy makes [MarshalWitnessJSON] and [UnmarshalWitnessJSON]
+// key the inputs by their full names built with s, which must be the strategy
+// the circuit is compiled with (see [WithNameStrategy]). By default, the
+// [schema.DefaultNameStrategy] is used.
+func WithJSONNameStrategy(s schema.NameStrategy) WitnessJSONOption {
+	return func(cfg *witnessJSONConfig) {
+		cfg.names = s
+	}
+}
@@ -0,0 +1,365 @@
+package frontend_test
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+const staticTableSize = 1000
+
+type staticCircuit struct {
+	Table []frontend.Variable `gnark:",public,static"`
+	Key   frontend.Variable   `gnark:",secret,static"`
+	X     frontend.Variable   `gnark:",public"`
+	Y     frontend.Variable
+}
+
+func (c *staticCircuit) Define(api frontend.API) error {
+	for i := range c.Table {
+		api.AssertIsDifferent(c.Table[i], c.X)
+	}
+	api.AssertIsEqual(api.Mul(c.X, c.Key), c.Y)
+	return nil
+}
+
+func newStaticAssignment(x int) *staticCircuit {
+	res := &staticCircuit{Table: make([]frontend.Variable, staticTableSize), Key: 7, X: x, Y: 7 * x}
+	for i := range res.Table {
+		res.Table[i] = fmt.Sprintf("%d", 1000000+i)
+	}
+	return res
+}
+
+func TestStaticWitness(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	static, err := frontend.NewStaticWitness(newStaticAssignment(0), field)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 1; x < 4; x++ {
+		expected, err := frontend.NewWitness(newStaticAssignment(x), field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dynamic := &staticCircuit{Table: make([]frontend.Variable, staticTableSize), X: x, Y: 7 * x}
+		w, err := frontend.NewWitness(dynamic, field, frontend.WithStatic(static))
+		if err != nil {
+			t.Fatal(err)
+		}
+		eb, _ := expected.MarshalBinary()
+		wb, _ := w.MarshalBinary()
+		if string(eb) != string(wb) {
+			t.Fatalf("witness with static values differs for x=%d", x)
+		}
+	}
+
+	// the static witness must match the assignment
+	dynamic := &staticCircuit{Table: make([]frontend.Variable, staticTableSize-1), X: 1, Y: 7}
+	if _, err := frontend.NewWitness(dynamic, field, frontend.WithStatic(static)); err == nil {
+		t.Fatal("expected error for mismatching static witness")
+	}
+}
+
+type bitsCircuit struct {
+	Amount   frontend.Variable    `gnark:",public,bits=64"`
+	Balances [2]frontend.Variable `gnark:",bits=8"`
+	X        frontend.Variable
+}
+
+func (c *bitsCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(c.Balances[0], c.Balances[1]), api.Add(c.Amount, c.X))
+	return nil
+}
+
+func TestWitnessBits(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	if _, err := frontend.Compile(field, r1cs.NewBuilder, &bitsCircuit{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := frontend.NewSchema(&bitsCircuit{}); err != nil {
+		t.Fatal(err)
+	}
+	valid := &bitsCircuit{Amount: "18446744073709551615", Balances: [2]frontend.Variable{255, 0}, X: -1}
+	if _, err := frontend.NewWitness(valid, field); err != nil {
+		t.Fatal(err)
+	}
+	for _, invalid := range []*bitsCircuit{
+		{Amount: "18446744073709551616", Balances: [2]frontend.Variable{255, 0}, X: 1},
+		{Amount: 1, Balances: [2]frontend.Variable{256, 0}, X: 1},
+		{Amount: 1, Balances: [2]frontend.Variable{0, -1}, X: 1},
+	} {
+		if _, err := frontend.NewWitness(invalid, field); err == nil {
+			t.Fatalf("expected error for over-width assignment %v", invalid)
+		}
+	}
+	// the secret part is not checked when building the public witness
+	public := &bitsCircuit{Amount: 1, Balances: [2]frontend.Variable{256, 0}}
+	if _, err := frontend.NewWitness(public, field, frontend.PublicOnly()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// BenchmarkStaticWitness compares building the witnesses with and without a
+// static witness, and the same followed by solving the circuit. The static
+// witness saves the parsing of the static values only, the solving is the
+// same.
+func BenchmarkStaticWitness(b *testing.B) {
+	field := ecc.BN254.ScalarField()
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, &staticCircuit{Table: make([]frontend.Variable, staticTableSize)})
+	if err != nil {
+		b.Fatal(err)
+	}
+	static, err := frontend.NewStaticWitness(newStaticAssignment(0), field)
+	if err != nil {
+		b.Fatal(err)
+	}
+	full := func(i int) (witness.Witness, error) {
+		return frontend.NewWitness(newStaticAssignment(i+1), field)
+	}
+	withStatic := func(i int) (witness.Witness, error) {
+		dynamic := &staticCircuit{Table: make([]frontend.Variable, staticTableSize), X: i + 1, Y: 7 * (i + 1)}
+		return frontend.NewWitness(dynamic, field, frontend.WithStatic(static))
+	}
+	for _, bc := range []struct {
+		name    string
+		witness func(int) (witness.Witness, error)
+	}{{"full", full}, {"static", withStatic}} {
+		b.Run("witness/"+bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := bc.witness(i); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run("solve/"+bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				w, err := bc.witness(i)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := ccs.Solve(w); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+type valueCircuit struct {
+	A, B, C, D frontend.Variable
+}
+
+func (c *valueCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(c.A, c.B, c.C), c.D)
+	return nil
+}
+
+func TestWitnessValueType(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	var e fr.Element
+	e.SetUint64(4)
+	valid := &valueCircuit{A: uint8(1), B: "0x2", C: big.NewInt(3), D: &e}
+	if _, err := frontend.NewWitness(valid, field); err != nil {
+		t.Fatal(err)
+	}
+
+	// all the invalid values are reported, with their field and type
+	invalid := &valueCircuit{A: 1.5, B: "two", C: struct{}{}, D: 4}
+	_, err := frontend.NewWitness(invalid, field)
+	if err == nil {
+		t.Fatal("expected error for invalid values")
+	}
+	for _, msg := range []string{
+		"A: can't assign a value of type float64",
+		`B: can't parse "two" as an integer`,
+		"C: can't assign a value of type struct {}",
+	} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Fatalf("expected %q in error %q", msg, err)
+		}
+	}
+}
+
+func TestWitnessBytesAndHex(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	last := new(big.Int).Sub(field, big.NewInt(1))
+	oversized := make([]byte, 33)
+	oversized[0] = 1
+
+	for _, c := range []struct {
+		value any
+		valid bool
+	}{
+		{last.FillBytes(make([]byte, 32)), true},
+		{"0x" + last.Text(16), true},
+		{field.FillBytes(make([]byte, 32)), false},
+		{"0x" + field.Text(16), false},
+		{"0X" + field.Text(16), false},
+		{oversized, false},
+		// the integers are reduced modulo the field
+		{field.String(), true},
+		{field, true},
+	} {
+		_, err := frontend.NewWitness(&valueCircuit{A: 1, B: c.value, C: 3, D: 4}, field)
+		if c.valid && err != nil {
+			t.Fatalf("%v: %v", c.value, err)
+		}
+		if !c.valid && (err == nil || !strings.Contains(err.Error(), "B: value 0x")) {
+			t.Fatalf("%v: expected a modulus error, got %v", c.value, err)
+		}
+	}
+
+	// the []byte are big-endian
+	w, err := frontend.NewWitness(&valueCircuit{A: []byte{1, 0}, B: "0x100", C: 3, D: 4}, field)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := w.Vector().(fr.Vector)
+	if values[0].Uint64() != 256 || values[1].Uint64() != 256 {
+		t.Fatalf("expected 256, got %s and %s", values[0].String(), values[1].String())
+	}
+}
+
+func BenchmarkWitnessIntegers(b *testing.B) {
+	field := ecc.BN254.ScalarField()
+	assignment := &staticCircuit{Table: make([]frontend.Variable, staticTableSize), Key: 2, X: 3, Y: 6}
+	for i := range assignment.Table {
+		assignment.Table[i] = i
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := frontend.NewWitness(assignment, field); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type jsonAccount struct {
+	Balance frontend.Variable
+	Keys    [2]frontend.Variable `gnark:",public"`
+}
+
+type jsonCircuit struct {
+	Accounts []jsonAccount
+	Root     frontend.Variable `gnark:"root,public"`
+	Big      frontend.Variable
+}
+
+func (c *jsonCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.Root, c.Big)
+	return nil
+}
+
+func TestMarshalWitnessJSON(t *testing.T) {
+	large, _ := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495616", 10)
+	var e fr.Element
+	e.SetUint64(42)
+	assignment := &jsonCircuit{
+		Accounts: []jsonAccount{{Balance: -3, Keys: [2]frontend.Variable{"0x10", []byte{1, 0}}}},
+		Root:     e,
+		Big:      large,
+	}
+	b, err := frontend.MarshalWitnessJSON(assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the values are decimal strings, the large ones are not rounded
+	expected := `{"public":{"Accounts_0_Keys_0":"16","Accounts_0_Keys_1":"256","root":"42"},` +
+		`"secret":{"Accounts_0_Balance":"-3","Big":"21888242871839275222246405745257275088548364400416034343698204186575808495616"}}`
+	if string(b) != expected {
+		t.Fatalf("expected %s, got %s", expected, b)
+	}
+
+	// the unassigned inputs are an error, unless they are allowed
+	assignment.Accounts[0].Keys[1] = nil
+	if _, err := frontend.MarshalWitnessJSON(assignment); err == nil || err.Error() != "Accounts_0_Keys_1: input not assigned" {
+		t.Fatalf("expected an error for the unassigned input, got %v", err)
+	}
+	if b, err = frontend.MarshalWitnessJSON(assignment, frontend.AllowUnassigned()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "Accounts_0_Keys_1") {
+		t.Fatalf("expected the unassigned input to be omitted, got %s", b)
+	}
+
+	assignment.Big = 1.5
+	if _, err := frontend.MarshalWitnessJSON(assignment); err == nil {
+		t.Fatal("expected an error for an unsupported value")
+	}
+}
+
+func TestUnmarshalWitnessJSON(t *testing.T) {
+	newCircuit := func() *jsonCircuit {
+		return &jsonCircuit{Accounts: make([]jsonAccount, 2)}
+	}
+	assignment := newCircuit()
+	for i := range assignment.Accounts {
+		assignment.Accounts[i] = jsonAccount{Balance: i - 5, Keys: [2]frontend.Variable{10 * i, 10*i + 1}}
+	}
+	assignment.Root = "12345678901234567890123456789"
+	assignment.Big = 1
+
+	// round trip
+	b, err := frontend.MarshalWitnessJSON(assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := newCircuit()
+	if err := frontend.UnmarshalWitnessJSON(b, decoded); err != nil {
+		t.Fatal(err)
+	}
+	b2, err := frontend.MarshalWitnessJSON(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != string(b2) {
+		t.Fatalf("round trip differs: %s, %s", b, b2)
+	}
+	field := ecc.BN254.ScalarField()
+	expected, err := frontend.NewWitness(assignment, field)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := frontend.NewWitness(decoded, field)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eb, _ := expected.MarshalBinary()
+	wb, _ := w.MarshalBinary()
+	if string(eb) != string(wb) {
+		t.Fatal("the witness of the decoded assignment differs")
+	}
+
+	for _, tc := range []struct {
+		name, data, err string
+	}{
+		{"missing key", `{"public": {"root": "1"}, "secret": {"Big": "2"}}`, "Accounts_0_Balance: input not assigned"},
+		{"extra key", `{"public": {"root": "1", "X": "3"}}`, "X: not an input of the circuit"},
+		{"visibility mismatch", `{"public": {"root": "1", "Big": "2"}}`, "Big: secret input given with another visibility"},
+		{"invalid value", `{"secret": {"Big": "0x10"}}`, `Big: can't parse "0x10" as a decimal integer`},
+		{"unknown object", `{"private": {"Big": "2"}}`, "unknown field"},
+	} {
+		err := frontend.UnmarshalWitnessJSON([]byte(tc.data), newCircuit())
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Fatalf("%s: expected error %q, got %v", tc.name, tc.err, err)
+		}
+	}
+
+	// the unassigned inputs can be allowed
+	partial := newCircuit()
+	data := `{"public": {"root": "1"}, "secret": {"Big": "2"}}`
+	if err := frontend.UnmarshalWitnessJSON([]byte(data), partial, frontend.AllowUnassigned()); err != nil {
+		t.Fatal(err)
+	}
+	if partial.Big.(*big.Int).Int64() != 2 || partial.Accounts[0].Balance != nil {
+		t.Fatal("unexpected values of the partial assignment")
+	}
+}
@@ -0,0 +1,27 @@
+// Package circuitcommit records in the builder that the inputs tagged with
+// commit have been bound to their public commitment.
+package circuitcommit
+
+import (
+	"github.com/consensys/gnark/internal/kvstore"
+)
+
+type assertedKey struct{}
+
+// Assert marks the commitment to the tagged inputs as asserted in builder.
+func Assert(builder any) {
+	kv, ok := builder.(kvstore.Store)
+	if !ok {
+		panic("builder does not implement kvstore.Store")
+	}
+	kv.SetKeyValue(assertedKey{}, true)
+}
+
+// Asserted returns true if [Assert] was called on builder.
+func Asserted(builder any) bool {
+	kv, ok := builder.(kvstore.Store)
+	if !ok {
+		panic("builder does not implement kvstore.Store")
+	}
+	return kv.GetKeyValue(assertedKey{}) != nil
+}
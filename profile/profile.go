@@ -156,20 +156,55 @@ func (p *Profile) Top() string {
 	return buf.String()
 }
 
+// Regions returns the number of constraints attributed to each named region (see
+// [RecordConstraintInRegion]). Nested regions are identified by their full path, for example "merkle/hash"; a constraint is
+// attributed to the innermost region only. Constraints recorded outside of any region are not
+// included.
+func (p *Profile) Regions() map[string]int {
+	r := make(map[string]int)
+	for _, s := range p.pprof.Sample {
+		if region, ok := s.Label[regionLabel]; ok && len(region) == 1 {
+			r[region[0]]++
+		}
+	}
+	return r
+}
+
+// Active returns true if a profiling session is active, in which case the
+// recorded constraints are sampled.
+func Active() bool {
+	return atomic.LoadUint32(&activeSessions) != 0
+}
+
 // RecordConstraint add a sample (with count == 1) to all the active profiling sessions.
 func RecordConstraint() {
+	recordConstraint("")
+}
+
+// RecordConstraintInRegion is RecordConstraint, attributing the constraint to
+// the named region, for example "merkle/hash" for the region hash nested in
+// the region merkle. The regions are opened by the builders, see
+// [github.com/consensys/gnark/frontend.Regioner].
+//
+// The region is stored as a pprof sample label ("region"), such that one can for example use
+// go tool pprof -tagfocus region=merkle gnark.pprof to focus the report on a region.
+func RecordConstraintInRegion(region string) {
+	recordConstraint(region)
+}
+
+func recordConstraint(region string) {
 	if n := atomic.LoadUint32(&activeSessions); n == 0 {
 		return // do nothing, no active session.
 	}
 
 	// collect the stack and send it async to the worker
 	pc := make([]uintptr, 20)
-	n := runtime.Callers(3, pc)
+	n := runtime.Callers(4, pc)
 	if n == 0 {
 		return
 	}
 	pc = pc[:n]
-	chCommands <- command{pc: pc}
+	chCommands <- command{pc: pc, region: region}
 }
 
 func (p *Profile) getLocation(frame *runtime.Frame) *profile.Location {
@@ -4,6 +4,10 @@ package profile_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/frontend"
@@ -31,11 +35,12 @@ func (o *obj) Define(api frontend.API, A frontend.Variable) error {
 }
 
 func Example() {
-	// default options generate gnark.pprof in current dir
+	// default options generate gnark.pprof in current dir, here it is
+	// written in the temporary directory.
 	// use pprof as usual (go tool pprof -http=:8080 gnark.pprof) to read the profile file
 	// overlapping profiles are allowed (define profiles inside Define or subfunction to profile
 	// part of the circuit only)
-	p := profile.Start()
+	p := profile.Start(profile.WithPath(filepath.Join(os.TempDir(), "gnark.pprof")))
 	_, _ = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &Circuit{})
 	p.Stop()
 
@@ -59,3 +64,72 @@ func Example() {
 	// Output:
 	// 2
 }
+
+type regionCircuit struct {
+	A, B frontend.Variable
+}
+
+func (circuit *regionCircuit) Define(api frontend.API) error {
+	r, ok := api.Compiler().(frontend.Regioner)
+	if !ok {
+		return fmt.Errorf("the builder does not implement frontend.Regioner")
+	}
+	r.EnterRegion("square")
+	a2 := api.Mul(circuit.A, circuit.A)
+	r.EnterRegion("check")
+	api.AssertIsEqual(a2, circuit.A)
+	r.LeaveRegion()
+	r.LeaveRegion()
+	api.AssertIsEqual(api.Mul(circuit.B, circuit.B), circuit.B)
+	return nil
+}
+
+func TestRegions(t *testing.T) {
+	p := profile.Start(profile.WithPath(filepath.Join(t.TempDir(), "gnark.pprof")))
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &regionCircuit{})
+	p.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.NbConstraints() != 4 {
+		t.Fatalf("expected 4 constraints, got %d", p.NbConstraints())
+	}
+	regions := p.Regions()
+	if len(regions) != 2 {
+		t.Fatalf("expected 2 regions, got %v", regions)
+	}
+	if regions["square"] != 1 {
+		t.Fatalf("expected 1 constraint in region square, got %d", regions["square"])
+	}
+	if regions["square/check"] != 1 {
+		t.Fatalf("expected 1 constraint in region square/check, got %d", regions["square/check"])
+	}
+}
+
+func TestRegionsConcurrent(t *testing.T) {
+	// the regions are held by the builders, the circuits compiled
+	// concurrently do not share them
+	p := profile.Start(profile.WithNoOutput())
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &regionCircuit{})
+		}(i)
+	}
+	wg.Wait()
+	p.Stop()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	regions := p.Regions()
+	if len(regions) != 2 || regions["square"] != len(errs) || regions["square/check"] != len(errs) {
+		t.Fatalf("expected %d constraints in each region, got %v", len(errs), regions)
+	}
+}
@@ -20,9 +20,13 @@ var onceInit sync.Once
 type command struct {
 	p      *Profile
 	pc     []uintptr
+	region string
 	remove bool
 }
 
+// regionLabel is the pprof sample label key used to store the region of a constraint.
+const regionLabel = "region"
+
 func worker() {
 	for c := range chCommands {
 		if c.p != nil {
@@ -45,17 +49,20 @@ func worker() {
 		}
 
 		// it's a sampling of event
-		collectSample(c.pc)
+		collectSample(c.pc, c.region)
 	}
 
 }
 
 // collectSample must be called from the worker go routine
-func collectSample(pc []uintptr) {
+func collectSample(pc []uintptr, region string) {
 	// for each session we may have a distinct sample, since ids of functions and locations may mismatch
 	samples := make([]*profile.Sample, len(sessions))
 	for i := 0; i < len(samples); i++ {
 		samples[i] = &profile.Sample{Value: []int64{1}} // for now, we just collect new constraints count
+		if region != "" {
+			samples[i].Label = map[string][]string{regionLabel: {region}}
+		}
 	}
 
 	frames := runtime.CallersFrames(pc)
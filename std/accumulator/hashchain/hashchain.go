@@ -0,0 +1,44 @@
+// Package hashchain implements verification of appends to a hash chain
+// (running hash) commitment log.
+//
+// The head of the log after appending an entry is
+//
+//	newHead = H(oldHead || entry)
+//
+// where H is a [hash.FieldHasher]. The initial head is chosen by the
+// application, for example 0.
+package hashchain
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// Append returns the head of the log after appending entry to the log with
+// head head. An entry may consist of several field elements. The hasher is
+// reset before and after use.
+func Append(h hash.FieldHasher, head frontend.Variable, entry ...frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(head)
+	h.Write(entry...)
+	res := h.Sum()
+	h.Reset()
+	return res
+}
+
+// AssertAppend asserts that newHead is the head of the log obtained by
+// appending entry to the log with head oldHead.
+func AssertAppend(api frontend.API, h hash.FieldHasher, oldHead, newHead frontend.Variable, entry ...frontend.Variable) {
+	api.AssertIsEqual(Append(h, oldHead, entry...), newHead)
+}
+
+// AssertAppendAll asserts that newHead is the head of the log obtained by
+// appending the single element entries one after another to the log with head
+// oldHead.
+func AssertAppendAll(api frontend.API, h hash.FieldHasher, oldHead, newHead frontend.Variable, entries []frontend.Variable) {
+	head := oldHead
+	for i := range entries {
+		head = Append(h, head, entries[i])
+	}
+	api.AssertIsEqual(head, newHead)
+}
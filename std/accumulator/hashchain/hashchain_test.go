@@ -0,0 +1,71 @@
+package hashchain
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/frontend"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type appendCircuit struct {
+	Heads   []frontend.Variable `gnark:",public"`
+	Entries []frontend.Variable
+}
+
+func (c *appendCircuit) Define(api frontend.API) error {
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	for i := range c.Entries {
+		AssertAppend(api, &h, c.Heads[i], c.Heads[i+1], c.Entries[i])
+	}
+	AssertAppendAll(api, &h, c.Heads[0], c.Heads[len(c.Heads)-1], c.Entries)
+	return nil
+}
+
+func TestAppend(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbEntries = 4
+
+	// off-circuit hash chain
+	heads := make([]fr.Element, nbEntries+1)
+	entries := make([]fr.Element, nbEntries)
+	for i := range entries {
+		entries[i].SetRandom()
+		h := mimc.NewMiMC()
+		b := heads[i].Bytes()
+		h.Write(b[:])
+		b = entries[i].Bytes()
+		h.Write(b[:])
+		heads[i+1].SetBytes(h.Sum(nil))
+	}
+
+	circuit := appendCircuit{
+		Heads:   make([]frontend.Variable, nbEntries+1),
+		Entries: make([]frontend.Variable, nbEntries),
+	}
+	valid := appendCircuit{
+		Heads:   make([]frontend.Variable, nbEntries+1),
+		Entries: make([]frontend.Variable, nbEntries),
+	}
+	for i := range heads {
+		valid.Heads[i] = heads[i].String()
+	}
+	for i := range entries {
+		valid.Entries[i] = entries[i].String()
+	}
+	invalid := appendCircuit{
+		Heads:   append([]frontend.Variable{}, valid.Heads...),
+		Entries: append([]frontend.Variable{}, valid.Entries...),
+	}
+	invalid.Entries[0], invalid.Entries[1] = valid.Entries[1], valid.Entries[0]
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&valid),
+		test.WithInvalidAssignment(&invalid),
+		test.WithCurves(ecc.BN254))
+}
@@ -42,7 +42,8 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package merkle provides a ZKP-circuit function to verify merkle proofs.
+// Package merkle provides ZKP-circuit functions to verify merkle proofs and to
+// compute merkle roots.
 package merkle
 
 import (
@@ -82,14 +83,58 @@ func nodeSum(api frontend.API, h hash.FieldHasher, a, b frontend.Variable) front
 	return res
 }
 
+// ComputeRoot builds in-circuit the full Merkle tree over the leaves data and
+// returns its root. Leaves and nodes are hashed as in [VerifyProof], and the
+// tree has the same shape as the one of gnark-crypto merkletree package: when
+// the number of leaves is not a power of two, the left subtree is the largest
+// complete tree and the right subtree is built over the remaining leaves.
+func ComputeRoot(api frontend.API, h hash.FieldHasher, leaves []frontend.Variable) frontend.Variable {
+	if len(leaves) == 0 {
+		panic("no leaves")
+	}
+	if len(leaves) == 1 {
+		return leafSum(api, h, leaves[0])
+	}
+	k := 1
+	for 2*k < len(leaves) {
+		k *= 2
+	}
+	left := ComputeRoot(api, h, leaves[:k])
+	right := ComputeRoot(api, h, leaves[k:])
+	return nodeSum(api, h, left, right)
+}
+
 // VerifyProof takes a Merkle root, a proofSet, and a proofIndex and returns
 // true if the first element of the proof set is a leaf of data in the Merkle
 // root. False is returned if the proof set or Merkle root is nil, and if
 // 'numLeaves' equals 0.
 func (mp *MerkleProof) VerifyProof(api frontend.API, h hash.FieldHasher, leaf frontend.Variable) {
+	root, _ := mp.roots(api, h, leaf, nil)
 
+	// Compare our calculated Merkle root to the desired Merkle root.
+	api.AssertIsEqual(root, mp.RootHash)
+}
+
+// ReplaceLeaf asserts that the proof is valid for the leaf at index, as
+// [MerkleProof.VerifyProof] does, and returns the root of the tree in which the
+// data of this leaf is replaced by newData. Both roots are computed along the
+// same path, the index is decomposed only once.
+func (mp *MerkleProof) ReplaceLeaf(api frontend.API, h hash.FieldHasher, index, newData frontend.Variable) frontend.Variable {
+	root, newRoot := mp.roots(api, h, index, newData)
+	api.AssertIsEqual(root, mp.RootHash)
+	return newRoot
+}
+
+// roots returns the root computed from the proof for the leaf at position
+// leaf and, if newData is not nil, the root computed along the same path with
+// newData as leaf data.
+func (mp *MerkleProof) roots(api frontend.API, h hash.FieldHasher, leaf, newData frontend.Variable) (frontend.Variable, frontend.Variable) {
 	depth := len(mp.Path) - 1
 	sum := leafSum(api, h, mp.Path[0])
+	var newSum frontend.Variable
+	if newData != nil {
+		newSum = leafSum(api, h, newData)
+	}
 
 	// The binary decomposition is the bitwise negation of the order of hashes ->
 	// If the path in the plain go code is 					0 1 1 0 1 0
@@ -100,8 +145,11 @@ func (mp *MerkleProof) VerifyProof(api frontend.API, h hash.FieldHasher, leaf fr
 		d1 := api.Select(binLeaf[i-1], mp.Path[i], sum)
 		d2 := api.Select(binLeaf[i-1], sum, mp.Path[i])
 		sum = nodeSum(api, h, d1, d2)
+		if newData != nil {
+			d1 = api.Select(binLeaf[i-1], mp.Path[i], newSum)
+			d2 = api.Select(binLeaf[i-1], newSum, mp.Path[i])
+			newSum = nodeSum(api, h, d1, d2)
+		}
 	}
-
-	// Compare our calculated Merkle root to the desired Merkle root.
-	api.AssertIsEqual(sum, mp.RootHash)
+	return sum, newSum
 }
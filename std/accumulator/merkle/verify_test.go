@@ -19,6 +19,7 @@ package merkle
 import (
 	"bytes"
 	"crypto/rand"
+	"math/big"
 	"os"
 	"testing"
 
@@ -120,3 +121,99 @@ func TestVerify(t *testing.T) {
 	}
 
 }
+
+type rootCircuit struct {
+	Leaves []frontend.Variable
+	Root   frontend.Variable `gnark:",public"`
+}
+
+func (c *rootCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(ComputeRoot(api, &h, c.Leaves), c.Root)
+	return nil
+}
+
+func TestComputeRoot(t *testing.T) {
+	assert := test.NewAssert(t)
+	mod := ecc.BN254.ScalarField()
+	modNbBytes := len(mod.Bytes())
+
+	for _, numLeaves := range []int{1, 5, 8} {
+		tree := merkletree.New(hash.MIMC_BN254.New())
+		witness := rootCircuit{Leaves: make([]frontend.Variable, numLeaves)}
+		for i := 0; i < numLeaves; i++ {
+			leaf, err := rand.Int(rand.Reader, mod)
+			assert.NoError(err)
+			b := make([]byte, modNbBytes)
+			leaf.FillBytes(b)
+			tree.Push(b)
+			witness.Leaves[i] = leaf
+		}
+		witness.Root = tree.Root()
+
+		invalid := rootCircuit{Leaves: make([]frontend.Variable, numLeaves), Root: witness.Root}
+		copy(invalid.Leaves, witness.Leaves)
+		invalid.Leaves[numLeaves-1] = 0
+
+		assert.CheckCircuit(&rootCircuit{Leaves: make([]frontend.Variable, numLeaves)},
+			test.WithValidAssignment(&witness),
+			test.WithInvalidAssignment(&invalid),
+			test.WithCurves(ecc.BN254))
+	}
+}
+
+type replaceLeafCircuit struct {
+	M       MerkleProof
+	Index   frontend.Variable
+	NewData frontend.Variable
+	NewRoot frontend.Variable `gnark:",public"`
+}
+
+func (c *replaceLeafCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(c.M.ReplaceLeaf(api, &h, c.Index, c.NewData), c.NewRoot)
+	return nil
+}
+
+func TestReplaceLeaf(t *testing.T) {
+	assert := test.NewAssert(t)
+	const numLeaves, depth, index = 8, 3, 5
+	modNbBytes := len(ecc.BN254.ScalarField().Bytes())
+
+	buildTree := func(leaves []int64) ([]byte, [][]byte) {
+		tree := merkletree.New(hash.MIMC_BN254.New())
+		assert.NoError(tree.SetIndex(index))
+		for _, l := range leaves {
+			tree.Push(big.NewInt(l).FillBytes(make([]byte, modNbBytes)))
+		}
+		root, path, _, _ := tree.Prove()
+		return root, path
+	}
+	leaves := []int64{1, 2, 3, 4, 5, 6, 7, 8}
+	root, path := buildTree(leaves)
+	leaves[index] = 42
+	newRoot, _ := buildTree(leaves)
+
+	witness := replaceLeafCircuit{Index: index, NewData: 42, NewRoot: newRoot}
+	witness.M.RootHash = root
+	witness.M.Path = make([]frontend.Variable, depth+1)
+	for i := range path {
+		witness.M.Path[i] = path[i]
+	}
+	wrongRoot := witness
+	wrongRoot.M.RootHash = newRoot
+	wrongNewRoot := witness
+	wrongNewRoot.NewRoot = root
+
+	assert.CheckCircuit(&replaceLeafCircuit{M: MerkleProof{Path: make([]frontend.Variable, depth+1)}},
+		test.WithValidAssignment(&witness),
+		test.WithInvalidAssignment(&wrongRoot),
+		test.WithInvalidAssignment(&wrongNewRoot),
+		test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,124 @@
+// Package mmr provides ZKP-circuit functions to verify inclusion proofs in a
+// Merkle mountain range (MMR).
+//
+// An MMR is an append-only accumulator: the n leaves are stored in a list of
+// perfect Merkle trees, the peaks, one per bit set in n, from the highest to
+// the lowest. The first 2^h_0 leaves are in the first peak of height h_0, and
+// so on. The leaves and the nodes are hashed as in the package
+// [github.com/consensys/gnark/std/accumulator/merkle],
+//
+//	leaf = H(data), node = H(left || right),
+//
+// and the root of the MMR bags the peaks from the right:
+//
+//	root = H(peak_0 || H(peak_1 || ... H(peak_{k-2} || peak_{k-1})))
+//
+// with root = peak_0 for a single peak. The number of leaves n is known when
+// compiling the circuit, the index of the proven leaf is a variable.
+package mmr
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// Proof is an inclusion proof in an MMR.
+type Proof struct {
+	// Root is the root of the MMR.
+	Root frontend.Variable
+	// Peaks are the roots of the perfect trees of the MMR, from the highest
+	// to the lowest.
+	Peaks []frontend.Variable
+	// Path are the siblings of the nodes from the leaf to its peak. It is
+	// padded to the height of the highest peak, the padding values are
+	// ignored.
+	Path []frontend.Variable
+}
+
+// Heights returns the heights of the peaks of an MMR of size leaves, from the
+// highest to the lowest. The proofs for this size have len(Heights(size))
+// peaks and a path of length Heights(size)[0].
+func Heights(size int) []int {
+	var heights []int
+	for h := bits.Len(uint(size)) - 1; h >= 0; h-- {
+		if size>>h&1 == 1 {
+			heights = append(heights, h)
+		}
+	}
+	return heights
+}
+
+// VerifyProof asserts that data is the leaf at position index in the MMR of
+// size leaves with root p.Root. It returns an error if the shape of the proof
+// does not match the size.
+func (p *Proof) VerifyProof(api frontend.API, h hash.FieldHasher, size int, index, data frontend.Variable) error {
+	if size <= 0 {
+		return fmt.Errorf("invalid size %d", size)
+	}
+	heights := Heights(size)
+	if len(p.Peaks) != len(heights) {
+		return fmt.Errorf("expected %d peaks, got %d", len(heights), len(p.Peaks))
+	}
+	depth := heights[0]
+	if len(p.Path) != depth {
+		return fmt.Errorf("expected a path of length %d, got %d", depth, len(p.Path))
+	}
+
+	// index < 2^(depth+1). As the first leaves of a peak are at a multiple of
+	// its number of leaves, the position of the leaf in its peak of height
+	// h_j is given by the h_j low bits of index.
+	binIndex := api.ToBinary(index, depth+1)
+
+	// sums[l] is the node at level l above the leaf along the path.
+	sums := make([]frontend.Variable, depth+1)
+	sums[0] = hashLeaf(h, data)
+	for l := 0; l < depth; l++ {
+		left := api.Select(binIndex[l], p.Path[l], sums[l])
+		right := api.Select(binIndex[l], sums[l], p.Path[l])
+		sums[l+1] = hashNode(h, left, right)
+	}
+
+	// The leaves of the peak j have the bits of size above h_j and the bit
+	// h_j unset. Exactly one peak holds the leaf iff index < size.
+	var nbSelected frontend.Variable = 0
+	for j, hj := range heights {
+		selected := api.Sub(1, binIndex[hj])
+		for l := hj + 1; l <= depth; l++ {
+			if size>>l&1 == 1 {
+				selected = api.Mul(selected, binIndex[l])
+			} else {
+				selected = api.Mul(selected, api.Sub(1, binIndex[l]))
+			}
+		}
+		api.AssertIsEqual(api.Mul(selected, api.Sub(p.Peaks[j], sums[hj])), 0)
+		nbSelected = api.Add(nbSelected, selected)
+	}
+	api.AssertIsEqual(nbSelected, 1)
+
+	api.AssertIsEqual(bagPeaks(h, p.Peaks), p.Root)
+	return nil
+}
+
+// bagPeaks returns the root of the MMR with the given peaks.
+func bagPeaks(h hash.FieldHasher, peaks []frontend.Variable) frontend.Variable {
+	root := peaks[len(peaks)-1]
+	for j := len(peaks) - 2; j >= 0; j-- {
+		root = hashNode(h, peaks[j], root)
+	}
+	return root
+}
+
+func hashLeaf(h hash.FieldHasher, data frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(data)
+	return h.Sum()
+}
+
+func hashNode(h hash.FieldHasher, left, right frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(left, right)
+	return h.Sum()
+}
@@ -0,0 +1,195 @@
+package mmr
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+// refMMR is an out of circuit MMR, storing all the nodes of its peaks.
+type refMMR struct {
+	// trees[j][l] are the nodes at level l of the peak j
+	trees [][][]*big.Int
+}
+
+func refHash(values ...*big.Int) *big.Int {
+	h := cryptohash.MIMC_BN254.New()
+	for _, v := range values {
+		var buf [32]byte
+		v.FillBytes(buf[:])
+		h.Write(buf[:])
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+func newRefMMR(leaves []*big.Int) *refMMR {
+	m := new(refMMR)
+	start := 0
+	for _, hj := range Heights(len(leaves)) {
+		level := make([]*big.Int, 1<<hj)
+		for i := range level {
+			level[i] = refHash(leaves[start+i])
+		}
+		tree := [][]*big.Int{level}
+		for len(level) > 1 {
+			next := make([]*big.Int, len(level)/2)
+			for i := range next {
+				next[i] = refHash(level[2*i], level[2*i+1])
+			}
+			tree = append(tree, next)
+			level = next
+		}
+		m.trees = append(m.trees, tree)
+		start += 1 << hj
+	}
+	return m
+}
+
+func (m *refMMR) peaks() []*big.Int {
+	peaks := make([]*big.Int, len(m.trees))
+	for j, tree := range m.trees {
+		peaks[j] = tree[len(tree)-1][0]
+	}
+	return peaks
+}
+
+func (m *refMMR) root() *big.Int {
+	peaks := m.peaks()
+	root := peaks[len(peaks)-1]
+	for j := len(peaks) - 2; j >= 0; j-- {
+		root = refHash(peaks[j], root)
+	}
+	return root
+}
+
+// proof returns the proof of the leaf at index, with the path padded with
+// zeroes to the height of the highest peak.
+func (m *refMMR) proof(index int) Proof {
+	depth := len(m.trees[0]) - 1
+	p := Proof{Root: m.root(), Path: make([]frontend.Variable, depth)}
+	for _, peak := range m.peaks() {
+		p.Peaks = append(p.Peaks, peak)
+	}
+	for l := range p.Path {
+		p.Path[l] = 0
+	}
+	start := 0
+	for _, tree := range m.trees {
+		if index >= start+len(tree[0]) {
+			start += len(tree[0])
+			continue
+		}
+		pos := index - start
+		for l := 0; l < len(tree)-1; l++ {
+			p.Path[l] = tree[l][pos^1]
+			pos >>= 1
+		}
+		break
+	}
+	return p
+}
+
+type mmrCircuit struct {
+	P     Proof
+	Index frontend.Variable
+	Data  frontend.Variable
+	size  int
+}
+
+func (c *mmrCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return c.P.VerifyProof(api, &h, c.size, c.Index, c.Data)
+}
+
+func newMMRCircuit(size int) *mmrCircuit {
+	heights := Heights(size)
+	return &mmrCircuit{
+		P: Proof{
+			Peaks: make([]frontend.Variable, len(heights)),
+			Path:  make([]frontend.Variable, heights[0]),
+		},
+		size: size,
+	}
+}
+
+// knownRoots are the roots of the MMRs of the leaves 100, 101, ... with MiMC
+// over BN254, computed independently of refMMR by appending the leaves one by
+// one and merging the peaks of equal heights.
+var knownRoots = map[int]string{
+	1:  "20370067689261511688289967978544823130432235585709842144916192060767982363628",
+	4:  "10191998276508554137236566037591024212750880738405359783417290730185715121426",
+	11: "11338806285967904597327823959593255749439996579810363318390322819973082947387",
+}
+
+func TestVerifyProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, size := range []int{1, 4, 11} {
+		leaves := make([]*big.Int, size)
+		for i := range leaves {
+			leaves[i] = big.NewInt(int64(100 + i))
+		}
+		m := newRefMMR(leaves)
+		root, ok := new(big.Int).SetString(knownRoots[size], 10)
+		assert.True(ok)
+		assert.Equal(root.String(), m.root().String(), "root of %d leaves", size)
+		opts := []test.TestingOption{test.WithCurves(ecc.BN254)}
+		for index := 0; index < size; index++ {
+			opts = append(opts, test.WithValidAssignment(&mmrCircuit{P: m.proof(index), Index: index, Data: leaves[index]}))
+		}
+
+		// wrong data
+		opts = append(opts, test.WithInvalidAssignment(&mmrCircuit{P: m.proof(0), Index: 0, Data: 42}))
+		// data at another index
+		if size > 1 {
+			opts = append(opts, test.WithInvalidAssignment(&mmrCircuit{P: m.proof(0), Index: 1, Data: leaves[0]}))
+		}
+		// tampered path
+		if p := m.proof(0); len(p.Path) > 0 {
+			p.Path[0] = 42
+			opts = append(opts, test.WithInvalidAssignment(&mmrCircuit{P: p, Index: 0, Data: leaves[0]}))
+		}
+		// tampered peak
+		p := m.proof(size - 1)
+		p.Peaks[0] = 42
+		opts = append(opts, test.WithInvalidAssignment(&mmrCircuit{P: p, Index: size - 1, Data: leaves[size-1]}))
+		// tampered root
+		p = m.proof(size - 1)
+		p.Root = 42
+		opts = append(opts, test.WithInvalidAssignment(&mmrCircuit{P: p, Index: size - 1, Data: leaves[size-1]}))
+
+		assert.CheckCircuit(newMMRCircuit(size), opts...)
+	}
+}
+
+func TestVerifyProofOutOfRange(t *testing.T) {
+	assert := test.NewAssert(t)
+	// 11 leaves, peaks of heights 3, 1 and 0. The index 11 has the position 1
+	// in a peak of height 0, and 12 the position 0 in a peak of height 2,
+	// which the proof could fit in with a forged peak.
+	leaves := make([]*big.Int, 11)
+	for i := range leaves {
+		leaves[i] = big.NewInt(int64(100 + i))
+	}
+	m := newRefMMR(leaves)
+	assert.CheckCircuit(newMMRCircuit(11),
+		test.WithValidAssignment(&mmrCircuit{P: m.proof(10), Index: 10, Data: leaves[10]}),
+		test.WithInvalidAssignment(&mmrCircuit{P: m.proof(10), Index: 11, Data: leaves[10]}),
+		test.WithInvalidAssignment(&mmrCircuit{P: m.proof(10), Index: 15, Data: leaves[10]}),
+		test.WithCurves(ecc.BN254))
+}
+
+func TestHeights(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.Equal([]int{3, 1, 0}, Heights(11))
+	assert.Equal([]int{2}, Heights(4))
+	assert.Equal([]int{0}, Heights(1))
+	assert.Empty(Heights(0))
+}
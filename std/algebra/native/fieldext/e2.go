@@ -0,0 +1,175 @@
+// Package fieldext implements arithmetic in a quadratic extension of the
+// native field of the circuit.
+//
+// The extension is defined as
+//
+//	𝔽r²[u] = 𝔽r/u²-β
+//
+// where 𝔽r is the scalar field of the circuit and β is a quadratic non-residue
+// in 𝔽r chosen by the caller. Every element is represented by its two
+// coordinates over 𝔽r, and the arithmetic is implemented with constraints on
+// the coordinates.
+package fieldext
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in this package
+func GetHints() []solver.Hint {
+	return []solver.Hint{inverseHint}
+}
+
+// E2 is an element A0 + A1*u of the quadratic extension.
+type E2 struct {
+	A0, A1 frontend.Variable
+}
+
+// Ext2 implements the arithmetic of the quadratic extension 𝔽r[u]/(u²-β).
+type Ext2 struct {
+	api        frontend.API
+	nonResidue *big.Int
+}
+
+// New returns a new instance of the quadratic extension defined by the
+// non-residue β. It returns an error if β is a square in the native field.
+func New(api frontend.API, nonResidue *big.Int) (*Ext2, error) {
+	q := api.Compiler().Field()
+	nr := new(big.Int).Mod(nonResidue, q)
+	if big.Jacobi(nr, q) != -1 {
+		return nil, fmt.Errorf("%s is not a quadratic non-residue", nonResidue.String())
+	}
+	return &Ext2{api: api, nonResidue: nr}, nil
+}
+
+// Zero returns the zero element.
+func (e *Ext2) Zero() E2 {
+	return E2{A0: 0, A1: 0}
+}
+
+// One returns the unit element.
+func (e *Ext2) One() E2 {
+	return E2{A0: 1, A1: 0}
+}
+
+// FromBase returns the element a + 0*u.
+func (e *Ext2) FromBase(a frontend.Variable) E2 {
+	return E2{A0: a, A1: 0}
+}
+
+// Add returns x+y.
+func (e *Ext2) Add(x, y E2) E2 {
+	return E2{
+		A0: e.api.Add(x.A0, y.A0),
+		A1: e.api.Add(x.A1, y.A1),
+	}
+}
+
+// Sub returns x-y.
+func (e *Ext2) Sub(x, y E2) E2 {
+	return E2{
+		A0: e.api.Sub(x.A0, y.A0),
+		A1: e.api.Sub(x.A1, y.A1),
+	}
+}
+
+// Neg returns -x.
+func (e *Ext2) Neg(x E2) E2 {
+	return E2{
+		A0: e.api.Neg(x.A0),
+		A1: e.api.Neg(x.A1),
+	}
+}
+
+// Conjugate returns A0 - A1*u.
+func (e *Ext2) Conjugate(x E2) E2 {
+	return E2{
+		A0: x.A0,
+		A1: e.api.Neg(x.A1),
+	}
+}
+
+// Mul returns x*y. It costs 3 multiplications in the native field.
+func (e *Ext2) Mul(x, y E2) E2 {
+	// Karatsuba
+	u := e.api.Mul(e.api.Add(x.A0, x.A1), e.api.Add(y.A0, y.A1))
+	ac := e.api.Mul(x.A0, y.A0)
+	bd := e.api.Mul(x.A1, y.A1)
+	return E2{
+		A0: e.api.Add(ac, e.api.Mul(bd, e.nonResidue)),
+		A1: e.api.Sub(u, ac, bd),
+	}
+}
+
+// Square returns x². It costs 2 multiplications in the native field.
+func (e *Ext2) Square(x E2) E2 {
+	// (a0+a1)(a0+β*a1) = a0² + β*a1² + (1+β)*a0*a1
+	c0 := e.api.Mul(e.api.Add(x.A0, x.A1), e.api.Add(x.A0, e.api.Mul(x.A1, e.nonResidue)))
+	ab := e.api.Mul(x.A0, x.A1)
+	onePlusNr := new(big.Int).Add(e.nonResidue, big.NewInt(1))
+	return E2{
+		A0: e.api.Sub(c0, e.api.Mul(ab, onePlusNr)),
+		A1: e.api.Add(ab, ab),
+	}
+}
+
+// MulByBase returns c*x where c is an element of the native field.
+func (e *Ext2) MulByBase(x E2, c frontend.Variable) E2 {
+	return E2{
+		A0: e.api.Mul(x.A0, c),
+		A1: e.api.Mul(x.A1, c),
+	}
+}
+
+// Inverse returns 1/x. The result is computed by a hint and constrained by
+// x * (1/x) == 1, so the solver fails if x is zero.
+func (e *Ext2) Inverse(x E2) E2 {
+	res, err := e.api.NewHint(inverseHint, 2, e.nonResidue, x.A0, x.A1)
+	if err != nil {
+		// err is non-nil only for invalid number of inputs
+		panic(err)
+	}
+	inv := E2{A0: res[0], A1: res[1]}
+	e.AssertIsEqual(e.Mul(inv, x), e.One())
+	return inv
+}
+
+// AssertIsEqual asserts that x == y.
+func (e *Ext2) AssertIsEqual(x, y E2) {
+	e.api.AssertIsEqual(x.A0, y.A0)
+	e.api.AssertIsEqual(x.A1, y.A1)
+}
+
+// Select returns x if b is true and y otherwise.
+func (e *Ext2) Select(b frontend.Variable, x, y E2) E2 {
+	return E2{
+		A0: e.api.Select(b, x.A0, y.A0),
+		A1: e.api.Select(b, x.A1, y.A1),
+	}
+}
+
+// inverseHint computes 1/(a0+a1*u) = (a0-a1*u)/(a0²-β*a1²).
+func inverseHint(q *big.Int, inputs, outputs []*big.Int) error {
+	if len(inputs) != 3 || len(outputs) != 2 {
+		return fmt.Errorf("expected 3 inputs and 2 outputs")
+	}
+	nr, a0, a1 := inputs[0], inputs[1], inputs[2]
+	norm := new(big.Int).Mul(a1, a1)
+	norm.Mul(norm, nr)
+	norm.Sub(new(big.Int).Mul(a0, a0), norm)
+	norm.Mod(norm, q)
+	if norm.ModInverse(norm, q) == nil {
+		return fmt.Errorf("inverse of zero")
+	}
+	outputs[0].Mul(a0, norm).Mod(outputs[0], q)
+	outputs[1].Mul(a1, norm).Neg(outputs[1]).Mod(outputs[1], q)
+	return nil
+}
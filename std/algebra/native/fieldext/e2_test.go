@@ -0,0 +1,76 @@
+package fieldext
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// the scalar field of BW6-761 is the base field of BLS12-377, whose quadratic
+// extension 𝔽p²[u] = 𝔽p/u²+5 is implemented in gnark-crypto and used as
+// reference.
+var nonResidue = big.NewInt(-5)
+
+type e2Circuit struct {
+	A, B                 E2
+	Mul, Square, Inverse E2
+}
+
+func (c *e2Circuit) Define(api frontend.API) error {
+	ext, err := New(api, nonResidue)
+	if err != nil {
+		return err
+	}
+	ext.AssertIsEqual(ext.Mul(c.A, c.B), c.Mul)
+	ext.AssertIsEqual(ext.Square(c.A), c.Square)
+	ext.AssertIsEqual(ext.Inverse(c.A), c.Inverse)
+	return nil
+}
+
+func assign(a *bls12377.E2) E2 {
+	return E2{A0: a.A0.String(), A1: a.A1.String()}
+}
+
+func TestE2(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var a, b, mul, square, inverse bls12377.E2
+	a.SetRandom()
+	b.SetRandom()
+	mul.Mul(&a, &b)
+	square.Square(&a)
+	inverse.Inverse(&a)
+
+	valid := e2Circuit{
+		A: assign(&a), B: assign(&b),
+		Mul: assign(&mul), Square: assign(&square), Inverse: assign(&inverse),
+	}
+	invalid := valid
+	invalid.Mul = assign(&square)
+
+	assert.CheckCircuit(&e2Circuit{},
+		test.WithValidAssignment(&valid),
+		test.WithInvalidAssignment(&invalid),
+		test.WithCurves(ecc.BW6_761))
+}
+
+type newCircuit struct {
+	A frontend.Variable
+}
+
+func (c *newCircuit) Define(api frontend.API) error {
+	// 4 is a square in any field
+	_, err := New(api, big.NewInt(4))
+	return err
+}
+
+func TestNewSquare(t *testing.T) {
+	if err := test.IsSolved(&newCircuit{}, &newCircuit{A: 1}, ecc.BW6_761.ScalarField()); err == nil {
+		t.Fatal("expected error for a square non-residue")
+	}
+}
+
@@ -0,0 +1,60 @@
+// Package auction implements the verification of the outcome of a sealed-bid
+// auction.
+//
+// Each bidder commits to its bid b with a random salt s as
+//
+//	commitment = H(b || s)
+//
+// and publishes the commitment. After the bidding phase, the auctioneer
+// proves in zero knowledge that a given commitment holds the highest bid,
+// with the bids and the salts as secret inputs: the losing bids are not
+// revealed. The winning amount is returned to the caller, which may reveal it
+// (for example as a public input in a first-price auction) or keep it secret.
+package auction
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/cmp"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// AssertWinner asserts that bids[i] and salts[i] open commitments[i] for all
+// i, and that winner is the commitment of the highest bid. On ties, the first
+// of the highest bids in the order of the commitments wins. It returns the
+// winning bid.
+//
+// The bids are range checked to nbBits bits. It returns an error if the
+// lengths do not match, if there are no bids or if nbBits is too large for
+// the scalar field.
+func AssertWinner(api frontend.API, h hash.FieldHasher, commitments, bids, salts []frontend.Variable, winner frontend.Variable, nbBits int) (frontend.Variable, error) {
+	if len(commitments) == 0 || len(bids) != len(commitments) || len(salts) != len(commitments) {
+		return nil, fmt.Errorf("expected as many bids and salts as commitments, got %d commitments, %d bids and %d salts", len(commitments), len(bids), len(salts))
+	}
+	if nbBits <= 0 || nbBits+2 >= api.Compiler().FieldBitLen() {
+		return nil, fmt.Errorf("invalid number of bits %d", nbBits)
+	}
+
+	rchecker := rangecheck.New(api)
+	for i := range bids {
+		h.Reset()
+		h.Write(bids[i], salts[i])
+		api.AssertIsEqual(h.Sum(), commitments[i])
+		rchecker.Check(bids[i], nbBits)
+	}
+
+	// running maximum, the commitment of the maximum follows it. A later bid
+	// replaces the maximum only if it is strictly higher.
+	comparator := cmp.NewBoundedComparator(api, new(big.Int).Lsh(big.NewInt(1), uint(nbBits)), false)
+	highest, highestCommitment := bids[0], commitments[0]
+	for i := 1; i < len(bids); i++ {
+		higher := comparator.IsLess(highest, bids[i])
+		highest = api.Select(higher, bids[i], highest)
+		highestCommitment = api.Select(higher, commitments[i], highestCommitment)
+	}
+	api.AssertIsEqual(highestCommitment, winner)
+	return highest, nil
+}
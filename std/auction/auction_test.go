@@ -0,0 +1,84 @@
+package auction
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const nbBids = 4
+
+type auctionCircuit struct {
+	Commitments [nbBids]frontend.Variable `gnark:",public"`
+	Winner      frontend.Variable         `gnark:",public"`
+	Amount      frontend.Variable         `gnark:",public"`
+	Bids        [nbBids]frontend.Variable
+	Salts       [nbBids]frontend.Variable
+}
+
+func (c *auctionCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	amount, err := AssertWinner(api, &h, c.Commitments[:], c.Bids[:], c.Salts[:], c.Winner, 32)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(amount, c.Amount)
+	return nil
+}
+
+func commit(bid, salt int64) *big.Int {
+	h := cryptohash.MIMC_BN254.New()
+	var buf [32]byte
+	big.NewInt(bid).FillBytes(buf[:])
+	h.Write(buf[:])
+	big.NewInt(salt).FillBytes(buf[:])
+	h.Write(buf[:])
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+func newAssignment(bids, salts [nbBids]int64, winner int, amount int64) *auctionCircuit {
+	var a auctionCircuit
+	for i := range bids {
+		a.Commitments[i] = commit(bids[i], salts[i])
+		a.Bids[i] = bids[i]
+		a.Salts[i] = salts[i]
+	}
+	a.Winner = a.Commitments[winner]
+	a.Amount = amount
+	return &a
+}
+
+func TestAssertWinner(t *testing.T) {
+	assert := test.NewAssert(t)
+	bids := [nbBids]int64{120, 450, 300, 449}
+	salts := [nbBids]int64{11, 22, 33, 44}
+	ties := [nbBids]int64{120, 450, 300, 450}
+	tooLarge := [nbBids]int64{120, 450, 300, 1 << 32}
+
+	assert.CheckCircuit(&auctionCircuit{},
+		test.WithValidAssignment(newAssignment(bids, salts, 1, 450)),
+		// the first of the highest bids wins
+		test.WithValidAssignment(newAssignment(ties, salts, 1, 450)),
+		// false winner claims
+		test.WithInvalidAssignment(newAssignment(bids, salts, 3, 449)),
+		test.WithInvalidAssignment(newAssignment(bids, salts, 3, 450)),
+		test.WithInvalidAssignment(newAssignment(ties, salts, 3, 450)),
+		// wrong amount
+		test.WithInvalidAssignment(newAssignment(bids, salts, 1, 449)),
+		// the bids must be range checked
+		test.WithInvalidAssignment(newAssignment(tooLarge, salts, 3, 1<<32)),
+		test.WithCurves(ecc.BN254))
+
+	// a bid which does not open its commitment
+	a := newAssignment(bids, salts, 1, 450)
+	a.Bids[0] = 500
+	assert.CheckCircuit(&auctionCircuit{}, test.WithInvalidAssignment(a), test.WithCurves(ecc.BN254))
+}
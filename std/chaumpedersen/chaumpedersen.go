@@ -0,0 +1,66 @@
+// Package chaumpedersen provides a ZKP-circuit function to verify a
+// non-interactive Chaum-Pedersen proof of equality of discrete logarithms over
+// the twisted Edwards curve embedded in the native field.
+//
+// For points g, h and a secret x, the prover shows that y1 = [x]g and
+// y2 = [x]h without revealing x. With a random nonce k the proof is
+//
+//	A1 = [k]g, A2 = [k]h, e = H("e", g, h, y1, y2, A1, A2), s = k + e*x mod l
+//
+// where l is the order of the prime order subgroup and the challenge e is
+// derived with a Fiat-Shamir [fiatshamir.Transcript]. The proof is valid if
+//
+//	[s]g = A1 + [e]y1 and [s]h = A2 + [e]y2.
+package chaumpedersen
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// ChallengeID is the name of the Fiat-Shamir challenge, written to the hash
+// as a domain separator before the bound points.
+const ChallengeID = "e"
+
+// Proof stores a Chaum-Pedersen proof (to be used in gnark circuit)
+type Proof struct {
+	A1, A2 twistededwards.Point
+	S      frontend.Variable
+}
+
+// AssertEqualDLog asserts that proof shows log_g(y1) == log_h(y2). The hasher
+// must be the one used by the prover to compute the challenge.
+//
+// The method asserts that y1, y2 and the commitments of the proof are on the
+// curve, but does not check that they are in the prime order subgroup.
+func AssertEqualDLog(curve twistededwards.Curve, g, h, y1, y2 twistededwards.Point, proof Proof, hash hash.FieldHasher) error {
+	api := curve.API()
+	for _, p := range []twistededwards.Point{y1, y2, proof.A1, proof.A2} {
+		curve.AssertIsOnCurve(p)
+	}
+
+	transcript := fiatshamir.NewTranscript(api, hash, []string{ChallengeID})
+	for _, p := range []twistededwards.Point{g, h, y1, y2, proof.A1, proof.A2} {
+		if err := transcript.Bind(ChallengeID, []frontend.Variable{p.X, p.Y}); err != nil {
+			return fmt.Errorf("bind: %w", err)
+		}
+	}
+	e, err := transcript.ComputeChallenge(ChallengeID)
+	if err != nil {
+		return fmt.Errorf("compute challenge: %w", err)
+	}
+
+	// [s]g - [e]y1 == A1
+	q1 := curve.DoubleBaseScalarMul(g, curve.Neg(y1), proof.S, e)
+	api.AssertIsEqual(q1.X, proof.A1.X)
+	api.AssertIsEqual(q1.Y, proof.A1.Y)
+	// [s]h - [e]y2 == A2
+	q2 := curve.DoubleBaseScalarMul(h, curve.Neg(y2), proof.S, e)
+	api.AssertIsEqual(q2.X, proof.A2.X)
+	api.AssertIsEqual(q2.Y, proof.A2.Y)
+	return nil
+}
@@ -0,0 +1,117 @@
+package chaumpedersen
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	gtwistededwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type dleqCircuit struct {
+	G, H, Y1, Y2 gtwistededwards.Point `gnark:",public"`
+	Proof        Proof
+}
+
+func (c *dleqCircuit) Define(api frontend.API) error {
+	curve, err := gtwistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return AssertEqualDLog(curve, c.G, c.H, c.Y1, c.Y2, c.Proof, &h)
+}
+
+func randomScalar(t *testing.T) *big.Int {
+	params := twistededwards.GetEdwardsCurve()
+	k, err := rand.Int(rand.Reader, &params.Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func randomPoint(t *testing.T) twistededwards.PointAffine {
+	params := twistededwards.GetEdwardsCurve()
+	var p twistededwards.PointAffine
+	p.ScalarMultiplication(&params.Base, randomScalar(t))
+	return p
+}
+
+// challenge computes the Fiat-Shamir challenge as the in-circuit transcript.
+func challenge(points ...*twistededwards.PointAffine) *big.Int {
+	h := mimc.NewMiMC()
+	var id fr.Element
+	id.SetBytes([]byte(ChallengeID))
+	b := id.Bytes()
+	h.Write(b[:])
+	for _, p := range points {
+		for _, e := range []*fr.Element{&p.X, &p.Y} {
+			b := e.Bytes()
+			h.Write(b[:])
+		}
+	}
+	var res fr.Element
+	res.SetBytes(h.Sum(nil))
+	return res.BigInt(new(big.Int))
+}
+
+// prove is a reference out-of-circuit Chaum-Pedersen prover for y1 = [x]g and
+// y2 = [x]h.
+func prove(t *testing.T, x *big.Int, g, h, y1, y2 *twistededwards.PointAffine) (a1, a2 twistededwards.PointAffine, s *big.Int) {
+	params := twistededwards.GetEdwardsCurve()
+	k := randomScalar(t)
+	a1.ScalarMultiplication(g, k)
+	a2.ScalarMultiplication(h, k)
+	e := challenge(g, h, y1, y2, &a1, &a2)
+	s = new(big.Int).Mul(e, x)
+	s.Add(s, k).Mod(s, &params.Order)
+	return a1, a2, s
+}
+
+func point(p *twistededwards.PointAffine) gtwistededwards.Point {
+	return gtwistededwards.Point{X: p.X, Y: p.Y}
+}
+
+func assignment(g, h, y1, y2, a1, a2 *twistededwards.PointAffine, s *big.Int) *dleqCircuit {
+	return &dleqCircuit{
+		G: point(g), H: point(h), Y1: point(y1), Y2: point(y2),
+		Proof: Proof{A1: point(a1), A2: point(a2), S: s},
+	}
+}
+
+func TestAssertEqualDLog(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	g, h := randomPoint(t), randomPoint(t)
+	x := randomScalar(t)
+	var y1, y2 twistededwards.PointAffine
+	y1.ScalarMultiplication(&g, x)
+	y2.ScalarMultiplication(&h, x)
+	a1, a2, s := prove(t, x, &g, &h, &y1, &y2)
+
+	// y2 has a different discrete logarithm, the prover uses x for both.
+	var otherY2 twistededwards.PointAffine
+	otherY2.ScalarMultiplication(&h, new(big.Int).Add(x, big.NewInt(1)))
+	oa1, oa2, os := prove(t, x, &g, &h, &y1, &otherY2)
+
+	wrongS := new(big.Int).Add(s, big.NewInt(1))
+
+	assert.CheckCircuit(&dleqCircuit{},
+		test.WithValidAssignment(assignment(&g, &h, &y1, &y2, &a1, &a2, s)),
+		test.WithInvalidAssignment(assignment(&g, &h, &y1, &otherY2, &oa1, &oa2, os)),
+		test.WithInvalidAssignment(assignment(&g, &h, &y1, &y2, &a1, &a2, wrongS)),
+		test.WithInvalidAssignment(assignment(&g, &h, &y2, &y1, &a1, &a2, s)),
+		test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,71 @@
+// Package blinding provides sources of blinding factors (nonces) for preparing
+// the witnesses of commitment and encryption gadgets.
+//
+// A deterministic source derives the blinding factors from a seed and a label,
+// so that a witness can be reproduced exactly when debugging a circuit. In
+// production, the random source should be used instead, or the seed must be
+// kept secret and never reused for different values. The sources are
+// interchangeable through the [Source] interface.
+package blinding
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+// Source provides blinding factors.
+type Source interface {
+	// Blinding returns a blinding factor in [0, modulus) for the given label.
+	Blinding(label string, modulus *big.Int) (*big.Int, error)
+}
+
+type deterministic struct {
+	seed []byte
+}
+
+// NewDeterministic returns a source deriving the blinding factors from the
+// seed and the label. The same seed and label always give the same blinding
+// factor for a given modulus.
+func NewDeterministic(seed []byte) Source {
+	return &deterministic{seed: append([]byte{}, seed...)}
+}
+
+// Blinding expands HMAC-SHA256(seed, label || counter) to 128 bits more than
+// the bit length of the modulus and reduces it modulo the modulus, so that the
+// result is statistically close to uniform.
+func (d *deterministic) Blinding(label string, modulus *big.Int) (*big.Int, error) {
+	if modulus.Sign() <= 0 {
+		return nil, errors.New("modulus must be positive")
+	}
+	nbBytes := (modulus.BitLen()+7)/8 + 16
+	buf := make([]byte, 0, nbBytes+sha256.Size)
+	var counter [4]byte
+	for i := uint32(0); len(buf) < nbBytes; i++ {
+		mac := hmac.New(sha256.New, d.seed)
+		mac.Write([]byte(label))
+		binary.BigEndian.PutUint32(counter[:], i)
+		mac.Write(counter[:])
+		buf = mac.Sum(buf)
+	}
+	res := new(big.Int).SetBytes(buf[:nbBytes])
+	return res.Mod(res, modulus), nil
+}
+
+type random struct{}
+
+// NewRandom returns a source sampling the blinding factors uniformly at
+// random. The label is ignored.
+func NewRandom() Source {
+	return random{}
+}
+
+func (random) Blinding(_ string, modulus *big.Int) (*big.Int, error) {
+	if modulus.Sign() <= 0 {
+		return nil, errors.New("modulus must be positive")
+	}
+	return rand.Int(rand.Reader, modulus)
+}
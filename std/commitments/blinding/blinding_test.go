@@ -0,0 +1,93 @@
+package blinding
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	gtwistededwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/commitments/pedersen"
+	"github.com/consensys/gnark/test"
+)
+
+func TestDeterministic(t *testing.T) {
+	assert := test.NewAssert(t)
+	order := twistededwards.GetEdwardsCurve().Order
+
+	r1, err := NewDeterministic([]byte("seed")).Blinding("amount", &order)
+	assert.NoError(err)
+	r2, err := NewDeterministic([]byte("seed")).Blinding("amount", &order)
+	assert.NoError(err)
+	assert.Equal(0, r1.Cmp(r2), "same seed and label must give the same blinding")
+	assert.Equal(-1, r1.Cmp(&order))
+
+	r3, err := NewDeterministic([]byte("seed")).Blinding("balance", &order)
+	assert.NoError(err)
+	assert.NotEqual(0, r1.Cmp(r3), "different labels must give different blindings")
+	r4, err := NewDeterministic([]byte("other seed")).Blinding("amount", &order)
+	assert.NoError(err)
+	assert.NotEqual(0, r1.Cmp(r4), "different seeds must give different blindings")
+
+	_, err = NewDeterministic([]byte("seed")).Blinding("amount", big.NewInt(0))
+	assert.Error(err)
+}
+
+type openingCircuit struct {
+	H, Commitment gtwistededwards.Point `gnark:",public"`
+	V, R          frontend.Variable
+}
+
+func (c *openingCircuit) Define(api frontend.API) error {
+	curve, err := gtwistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	pedersen.AssertOpening(curve, c.H, c.Commitment, c.V, c.R)
+	return nil
+}
+
+func TestCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+	curve := twistededwards.GetEdwardsCurve()
+	src := NewDeterministic([]byte("debug seed"))
+
+	// for testing only: the discrete logarithm of H is known.
+	dlogH, err := src.Blinding("H", &curve.Order)
+	assert.NoError(err)
+	var h twistededwards.PointAffine
+	h.ScalarMultiplication(&curve.Base, dlogH)
+
+	v := big.NewInt(42)
+	commit := func(r *big.Int) gtwistededwards.Point {
+		var c, rH twistededwards.PointAffine
+		c.ScalarMultiplication(&curve.Base, v)
+		rH.ScalarMultiplication(&h, r)
+		c.Add(&c, &rH)
+		return gtwistededwards.Point{X: c.X, Y: c.Y}
+	}
+	r, err := src.Blinding("v", &curve.Order)
+	assert.NoError(err)
+	commitment := commit(r)
+
+	// the witness can be prepared again from the seed for the same commitment.
+	rAgain, err := NewDeterministic([]byte("debug seed")).Blinding("v", &curve.Order)
+	assert.NoError(err)
+	wrongR, err := src.Blinding("w", &curve.Order)
+	assert.NoError(err)
+	H := gtwistededwards.Point{X: h.X, Y: h.Y}
+	assert.CheckCircuit(&openingCircuit{},
+		test.WithValidAssignment(&openingCircuit{H: H, Commitment: commitment, V: v, R: rAgain}),
+		test.WithInvalidAssignment(&openingCircuit{H: H, Commitment: commitment, V: v, R: wrongR}),
+		test.WithCurves(ecc.BN254))
+}
+
+func TestRandom(t *testing.T) {
+	assert := test.NewAssert(t)
+	order := twistededwards.GetEdwardsCurve().Order
+	r, err := NewRandom().Blinding("amount", &order)
+	assert.NoError(err)
+	assert.Equal(-1, r.Cmp(&order))
+}
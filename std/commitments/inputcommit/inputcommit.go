@@ -0,0 +1,104 @@
+// Package inputcommit binds the secret inputs of a circuit to a public
+// commitment.
+//
+// The secret inputs to commit to are marked by the "commit" tag option (see
+// [schema.TagOptCommit]):
+//
+//	type Circuit struct {
+//	    X          frontend.Variable `gnark:",secret,commit"`
+//	    Y          frontend.Variable
+//	    Commitment frontend.Variable `gnark:",public"`
+//	}
+//
+//	func (c *Circuit) Define(api frontend.API) error {
+//	    h, _ := mimc.NewMiMC(api)
+//	    return inputcommit.AssertCommitment(api, &h, c, c.Commitment)
+//	}
+//
+// The commitment is the hash of the tagged inputs, in the order of their
+// declaration in the circuit. It is computed off-circuit with [Compute].
+//
+// The tag does not make the compiler emit the commitment, as the hash and the
+// public commitment are chosen by the circuit. Instead, the compilation of a
+// circuit with tagged inputs fails if Define does not call [AssertCommitment].
+package inputcommit
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+	"reflect"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/circuitcommit"
+	"github.com/consensys/gnark/internal/utils"
+	stdhash "github.com/consensys/gnark/std/hash"
+)
+
+var tVariable = reflect.ValueOf(struct{ A frontend.Variable }{}).FieldByName("A").Type()
+
+// tagged returns the values of the leaves of circuit tagged with
+// [schema.TagOptCommit].
+func tagged(circuit frontend.Circuit) ([]interface{}, error) {
+	var res []interface{}
+	_, err := schema.Walk(circuit, tVariable, func(leaf schema.LeafInfo, tValue reflect.Value) error {
+		if leaf.Commit {
+			res = append(res, tValue.Interface())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, errors.New("no input tagged with commit")
+	}
+	return res, nil
+}
+
+// commit returns the in-circuit commitment to the inputs of circuit tagged with
+// [schema.TagOptCommit], hashed with h.
+func commit(h stdhash.FieldHasher, circuit frontend.Circuit) (frontend.Variable, error) {
+	vals, err := tagged(circuit)
+	if err != nil {
+		return nil, err
+	}
+	h.Reset()
+	for i := range vals {
+		h.Write(vals[i])
+	}
+	return h.Sum(), nil
+}
+
+// AssertCommitment asserts that commitment is the commitment to the inputs of
+// circuit tagged with [schema.TagOptCommit]. It is called from circuit.Define.
+func AssertCommitment(api frontend.API, h stdhash.FieldHasher, circuit frontend.Circuit, commitment frontend.Variable) error {
+	c, err := commit(h, circuit)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(c, commitment)
+	circuitcommit.Assert(api)
+	return nil
+}
+
+// Compute returns the commitment to the inputs of assignment tagged with
+// [schema.TagOptCommit]. The inputs are reduced modulo field and written to h
+// in big-endian as full field elements. h must be the off-circuit counterpart
+// of the hasher used in-circuit, for example gnark-crypto MiMC over field.
+func Compute(assignment frontend.Circuit, field *big.Int, h hash.Hash) (*big.Int, error) {
+	vals, err := tagged(assignment)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, (field.BitLen()+7)/8)
+	h.Reset()
+	for i := range vals {
+		v := utils.FromInterface(vals[i])
+		v.Mod(&v, field)
+		v.FillBytes(buf)
+		h.Write(buf)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil)), nil
+}
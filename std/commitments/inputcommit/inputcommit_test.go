@@ -0,0 +1,94 @@
+package inputcommit
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type nested struct {
+	C [2]frontend.Variable
+}
+
+type commitCircuit struct {
+	A          frontend.Variable `gnark:",secret,commit"`
+	B          frontend.Variable
+	N          nested            `gnark:",secret,commit"`
+	Commitment frontend.Variable `gnark:",public"`
+}
+
+func (c *commitCircuit) Define(api frontend.API) error {
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(c.B, 0)
+	return AssertCommitment(api, &h, c, c.Commitment)
+}
+
+func TestCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+
+	assignment := &commitCircuit{A: 1, B: 2, N: nested{C: [2]frontend.Variable{3, 4}}}
+	commitment, err := Compute(assignment, field, mimc.NewMiMC())
+	assert.NoError(err)
+	assignment.Commitment = commitment
+
+	// the commitment is computed over A, N.C[0] and N.C[1] only
+	h := mimc.NewMiMC()
+	for _, v := range []byte{1, 3, 4} {
+		buf := make([]byte, 32)
+		buf[31] = v
+		h.Write(buf)
+	}
+	assert.Equal(h.Sum(nil), commitment.FillBytes(make([]byte, 32)))
+
+	otherB := *assignment
+	otherB.B = 5
+	otherA := *assignment
+	otherA.A = 5
+
+	assert.CheckCircuit(&commitCircuit{},
+		test.WithValidAssignment(assignment),
+		test.WithValidAssignment(&otherB),
+		test.WithInvalidAssignment(&otherA),
+		test.WithCurves(ecc.BN254))
+}
+
+type publicCommitCircuit struct {
+	A frontend.Variable `gnark:",public,commit"`
+}
+
+func (c *publicCommitCircuit) Define(api frontend.API) error {
+	return nil
+}
+
+func TestPublicCommit(t *testing.T) {
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &publicCommitCircuit{})
+	if err == nil {
+		t.Fatal("expected error for public input tagged with commit")
+	}
+}
+
+type uncommittedCircuit struct {
+	A          frontend.Variable `gnark:",secret,commit"`
+	Commitment frontend.Variable `gnark:",public"`
+}
+
+func (c *uncommittedCircuit) Define(api frontend.API) error {
+	api.AssertIsDifferent(c.A, c.Commitment)
+	return nil
+}
+
+func TestUncommitted(t *testing.T) {
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &uncommittedCircuit{})
+	if err == nil {
+		t.Fatal("expected error for tagged inputs without asserted commitment")
+	}
+}
@@ -0,0 +1,44 @@
+// Package pedersen implements Pedersen commitments on a twisted Edwards curve
+// embedded in the native field, and range proofs of committed values.
+//
+// A commitment to the value v with blinding factor r is
+//
+//	C = [v]G + [r]H
+//
+// where G is the base point of the curve and H is a second generator whose
+// discrete logarithm in base G must be unknown, for example obtained by
+// hashing to the curve.
+package pedersen
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// Commit returns the commitment [v]G + [r]H.
+func Commit(curve twistededwards.Curve, h twistededwards.Point, v, r frontend.Variable) twistededwards.Point {
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+	return curve.DoubleBaseScalarMul(base, h, v, r)
+}
+
+// AssertOpening asserts that commitment opens to v with blinding factor r.
+func AssertOpening(curve twistededwards.Curve, h, commitment twistededwards.Point, v, r frontend.Variable) {
+	api := curve.API()
+	c := Commit(curve, h, v, r)
+	api.AssertIsEqual(c.X, commitment.X)
+	api.AssertIsEqual(c.Y, commitment.Y)
+}
+
+// AssertIsInRange asserts that commitment opens to a value v in [0, 2^nbBits)
+// with blinding factor r. The value is decomposed into nbBits boolean-
+// constrained bits and the commitment is opened to the bit-weighted sum, so
+// that the range is bound to the commitment.
+func AssertIsInRange(curve twistededwards.Curve, h, commitment twistededwards.Point, v, r frontend.Variable, nbBits int) {
+	api := curve.API()
+	b := bits.ToBinary(api, v, bits.WithNbDigits(nbBits))
+	AssertOpening(curve, h, commitment, bits.FromBinary(api, b), r)
+}
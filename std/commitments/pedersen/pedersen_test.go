@@ -0,0 +1,70 @@
+package pedersen
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	gtwistededwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+const nbBits = 16
+
+type rangeCircuit struct {
+	H, Commitment gtwistededwards.Point `gnark:",public"`
+	V, R          frontend.Variable
+}
+
+func (c *rangeCircuit) Define(api frontend.API) error {
+	curve, err := gtwistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	AssertIsInRange(curve, c.H, c.Commitment, c.V, c.R, nbBits)
+	return nil
+}
+
+func randomScalar(t *testing.T) *big.Int {
+	order := twistededwards.GetEdwardsCurve().Order
+	r, err := rand.Int(rand.Reader, &order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func commit(h *twistededwards.PointAffine, v, r *big.Int) gtwistededwards.Point {
+	base := twistededwards.GetEdwardsCurve().Base
+	var c, rH twistededwards.PointAffine
+	c.ScalarMultiplication(&base, v)
+	rH.ScalarMultiplication(h, r)
+	c.Add(&c, &rH)
+	return gtwistededwards.Point{X: c.X, Y: c.Y}
+}
+
+func TestRangeProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	// for testing only: the discrete logarithm of H is known.
+	base := twistededwards.GetEdwardsCurve().Base
+	var h twistededwards.PointAffine
+	h.ScalarMultiplication(&base, randomScalar(t))
+	H := gtwistededwards.Point{X: h.X, Y: h.Y}
+
+	r := randomScalar(t)
+	inRange := big.NewInt(1000)
+	outOfRange := big.NewInt(1<<nbBits + 5)
+	upper := big.NewInt(1<<nbBits - 1)
+
+	assert.CheckCircuit(&rangeCircuit{},
+		test.WithValidAssignment(&rangeCircuit{H: H, Commitment: commit(&h, inRange, r), V: inRange, R: r}),
+		test.WithValidAssignment(&rangeCircuit{H: H, Commitment: commit(&h, upper, r), V: upper, R: r}),
+		test.WithInvalidAssignment(&rangeCircuit{H: H, Commitment: commit(&h, outOfRange, r), V: outOfRange, R: r}),
+		test.WithInvalidAssignment(&rangeCircuit{H: H, Commitment: commit(&h, inRange, r), V: 1001, R: r}),
+		test.WithInvalidAssignment(&rangeCircuit{H: H, Commitment: commit(&h, inRange, r), V: inRange, R: 1}),
+		test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,69 @@
+// Package selectiveopening implements the selective disclosure of committed
+// inputs.
+//
+// The prover commits to a vector of values with a blinding factor
+//
+//	commitment = H(blinding || values[0] || ... || values[n-1])
+//
+// and publishes the commitment. Afterwards, only a subset of the values is
+// revealed to a given verifier as public inputs, together with their indexes,
+// while the other values stay hidden by the commitment. The commitment is
+// computed off-circuit with [Compute].
+package selectiveopening
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	stdhash "github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Commit returns the in-circuit commitment to values with the blinding
+// factor.
+func Commit(h stdhash.FieldHasher, values []frontend.Variable, blinding frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(blinding)
+	h.Write(values...)
+	return h.Sum()
+}
+
+// AssertOpening asserts that commitment is the commitment to values with the
+// blinding factor, and that values[indexes[i]] == opened[i] for every i. The
+// indexes may be variables, they must be smaller than len(values) otherwise the
+// proof fails.
+func AssertOpening(api frontend.API, h stdhash.FieldHasher, commitment frontend.Variable, values []frontend.Variable, blinding frontend.Variable, indexes, opened []frontend.Variable) error {
+	if len(values) == 0 {
+		return errors.New("no committed values")
+	}
+	if len(indexes) != len(opened) {
+		return fmt.Errorf("got %d indexes for %d opened values", len(indexes), len(opened))
+	}
+	api.AssertIsEqual(Commit(h, values, blinding), commitment)
+	for i := range opened {
+		api.AssertIsEqual(selector.Mux(api, indexes[i], values...), opened[i])
+	}
+	return nil
+}
+
+// Compute returns the commitment H(blinding || values[0] || ... ||
+// values[n-1]) which the prover publishes, and which [AssertOpening] checks
+// in-circuit with [Commit]. The blinding factor and the values are reduced
+// modulo field and each written to h as a big-endian field element, so that h
+// must hash as the in-circuit hasher, for example gnark-crypto MiMC over
+// field.
+//
+// The blinding factor must be random and kept secret: the values which are
+// never opened are otherwise recoverable by hashing the candidate values.
+func Compute(values []*big.Int, blinding *big.Int, field *big.Int, h hash.Hash) *big.Int {
+	buf := make([]byte, (field.BitLen()+7)/8)
+	h.Reset()
+	for _, v := range append([]*big.Int{blinding}, values...) {
+		new(big.Int).Mod(v, field).FillBytes(buf)
+		h.Write(buf)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
@@ -0,0 +1,61 @@
+package selectiveopening
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/frontend"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type openingCircuit struct {
+	Values     [5]frontend.Variable
+	Blinding   frontend.Variable
+	Commitment frontend.Variable    `gnark:",public"`
+	Indexes    [2]frontend.Variable `gnark:",public"`
+	Opened     [2]frontend.Variable `gnark:",public"`
+}
+
+func (c *openingCircuit) Define(api frontend.API) error {
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return AssertOpening(api, &h, c.Commitment, c.Values[:], c.Blinding, c.Indexes[:], c.Opened[:])
+}
+
+func TestAssertOpening(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	values := []*big.Int{big.NewInt(1990), big.NewInt(250), big.NewInt(33), big.NewInt(7), big.NewInt(1)}
+	blinding, _ := new(big.Int).SetString("1c3a59e8f1b2d4c6a7e9f0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f708192a3b", 16)
+	commitment := Compute(values, blinding, ecc.BN254.ScalarField(), mimc.NewMiMC())
+
+	valid := &openingCircuit{Blinding: blinding, Commitment: commitment}
+	for i := range values {
+		valid.Values[i] = values[i]
+	}
+	valid.Indexes = [2]frontend.Variable{3, 0}
+	valid.Opened = [2]frontend.Variable{7, 1990}
+
+	tamperedValue := *valid
+	tamperedValue.Opened = [2]frontend.Variable{8, 1990}
+	tamperedIndex := *valid
+	tamperedIndex.Indexes = [2]frontend.Variable{2, 0}
+	outOfRange := *valid
+	outOfRange.Indexes = [2]frontend.Variable{5, 0}
+	// the opened values are correct but a hidden value does not match the commitment
+	tamperedCommitment := *valid
+	tamperedCommitment.Values[1] = 251
+
+	assert.CheckCircuit(&openingCircuit{},
+		test.WithValidAssignment(valid),
+		test.WithInvalidAssignment(&tamperedValue),
+		test.WithInvalidAssignment(&tamperedIndex),
+		test.WithInvalidAssignment(&outOfRange),
+		test.WithInvalidAssignment(&tamperedCommitment),
+		test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,93 @@
+// Package countmin implements the verification of the updates of a
+// Count-Min sketch [CM05] committed with a hash function.
+//
+// The sketch has depth rows of width counters. Adding an amount to an item
+// increments, in each row r, the counter at the index given by the
+// log2(width) least significant bits of
+//
+//	H(r || item).
+//
+// The counters are elements of the scalar field. The sketch is committed as
+// the hash of its counters in row-major order, so that a circuit updating it
+// only needs the commitments before and after the update as public inputs.
+//
+// [CM05]: https://doi.org/10.1016/j.jalgor.2003.12.001
+package countmin
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Sketch is a Count-Min sketch in a circuit.
+type Sketch struct {
+	api      frontend.API
+	h        hash.FieldHasher
+	nbBits   int
+	counters [][]frontend.Variable
+}
+
+// New returns a sketch with the given counters, counters[r] being row r. The
+// rows must have the same width, a power of two. The hasher h derives the
+// indices and the commitments. New does not copy the counters.
+func New(api frontend.API, h hash.FieldHasher, counters [][]frontend.Variable) (*Sketch, error) {
+	if len(counters) == 0 {
+		return nil, fmt.Errorf("empty sketch")
+	}
+	width := len(counters[0])
+	if width == 0 || width&(width-1) != 0 {
+		return nil, fmt.Errorf("width %d is not a power of two", width)
+	}
+	for r := range counters {
+		if len(counters[r]) != width {
+			return nil, fmt.Errorf("row %d has width %d, expected %d", r, len(counters[r]), width)
+		}
+	}
+	nbBits := 0
+	for 1<<nbBits < width {
+		nbBits++
+	}
+	if nbBits >= api.Compiler().FieldBitLen() {
+		return nil, fmt.Errorf("width 2^%d is too large for the scalar field", nbBits)
+	}
+	return &Sketch{api: api, h: h, nbBits: nbBits, counters: counters}, nil
+}
+
+// Index returns the index of the counter of item in row r.
+func (s *Sketch) Index(r int, item frontend.Variable) frontend.Variable {
+	s.h.Reset()
+	s.h.Write(r, item)
+	// the decomposition is canonical, so that the index is uniquely defined
+	digest := bits.ToBinary(s.api, s.h.Sum())
+	return bits.FromBinary(s.api, digest[:s.nbBits], bits.WithUnconstrainedInputs())
+}
+
+// Add adds amount to the counters of item.
+func (s *Sketch) Add(item, amount frontend.Variable) {
+	width := len(s.counters[0])
+	for r := range s.counters {
+		selected := selector.Decoder(s.api, width, s.Index(r, item))
+		for j := range s.counters[r] {
+			s.counters[r][j] = s.api.Add(s.counters[r][j], s.api.Mul(selected[j], amount))
+		}
+	}
+}
+
+// Counters returns the counters of the sketch, counters[r] being row r.
+func (s *Sketch) Counters() [][]frontend.Variable {
+	return s.counters
+}
+
+// Commit returns the commitment of the sketch, the hash of its counters in
+// row-major order.
+func (s *Sketch) Commit() frontend.Variable {
+	s.h.Reset()
+	for r := range s.counters {
+		s.h.Write(s.counters[r]...)
+	}
+	return s.h.Sum()
+}
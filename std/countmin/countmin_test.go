@@ -0,0 +1,168 @@
+package countmin
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const (
+	depth = 2
+	width = 8
+)
+
+type updateCircuit struct {
+	OldCommitment frontend.Variable `gnark:",public"`
+	NewCommitment frontend.Variable `gnark:",public"`
+	Counters      [depth][width]frontend.Variable
+	Items         [3]frontend.Variable
+	Amounts       [3]frontend.Variable
+}
+
+func (c *updateCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	counters := make([][]frontend.Variable, depth)
+	for r := range counters {
+		counters[r] = append([]frontend.Variable{}, c.Counters[r][:]...)
+	}
+	sketch, err := New(api, &h, counters)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(sketch.Commit(), c.OldCommitment)
+	for i := range c.Items {
+		sketch.Add(c.Items[i], c.Amounts[i])
+	}
+	api.AssertIsEqual(sketch.Commit(), c.NewCommitment)
+	return nil
+}
+
+// sketch is the reference implementation of the sketch.
+type sketch [depth][width]int64
+
+func element(v int64) []byte {
+	return big.NewInt(v).FillBytes(make([]byte, 32))
+}
+
+func (s *sketch) add(item, amount int64) {
+	for r := range s {
+		h := cryptohash.MIMC_BN254.New()
+		h.Write(element(int64(r)))
+		h.Write(element(item))
+		idx := new(big.Int).SetBytes(h.Sum(nil))
+		s[r][idx.Uint64()%width] += amount
+	}
+}
+
+func (s *sketch) commitment() *big.Int {
+	h := cryptohash.MIMC_BN254.New()
+	for r := range s {
+		for j := range s[r] {
+			h.Write(element(s[r][j]))
+		}
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+func TestUpdate(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var s sketch
+	s.add(7, 1)
+	items, amounts := [3]int64{7, 42, 7}, [3]int64{2, 5, 1}
+
+	var assignment updateCircuit
+	assignment.OldCommitment = s.commitment()
+	for r := range s {
+		for j := range s[r] {
+			assignment.Counters[r][j] = s[r][j]
+		}
+	}
+	for i := range items {
+		assignment.Items[i], assignment.Amounts[i] = items[i], amounts[i]
+		s.add(items[i], amounts[i])
+	}
+	assignment.NewCommitment = s.commitment()
+
+	invalid := assignment
+	invalid.Amounts[1] = 4
+
+	assert.CheckCircuit(&updateCircuit{},
+		test.WithValidAssignment(&assignment),
+		test.WithInvalidAssignment(&invalid),
+		test.WithCurves(ecc.BN254))
+}
+
+type newCircuit struct {
+	X        frontend.Variable
+	Expected [depth][width]frontend.Variable
+	Counters [][]frontend.Variable `gnark:"-"`
+}
+
+func (c *newCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	sketch, err := New(api, &h, c.Counters)
+	if err != nil {
+		return err
+	}
+	sketch.Add(c.X, 1)
+	for r, row := range sketch.Counters() {
+		for j := range row {
+			api.AssertIsEqual(row[j], c.Expected[r][j])
+		}
+	}
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, tc := range []struct {
+		counters [][]frontend.Variable
+		err      string
+	}{
+		{nil, "empty sketch"},
+		{[][]frontend.Variable{make([]frontend.Variable, 6)}, "width 6 is not a power of two"},
+		{[][]frontend.Variable{make([]frontend.Variable, 8), make([]frontend.Variable, 4)}, "row 1 has width 4, expected 8"},
+	} {
+		_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &newCircuit{Counters: tc.counters})
+		assert.ErrorContains(err, tc.err)
+	}
+
+	// a valid sketch counts the added item
+	zeros := func() [][]frontend.Variable {
+		counters := make([][]frontend.Variable, depth)
+		for r := range counters {
+			counters[r] = make([]frontend.Variable, width)
+			for j := range counters[r] {
+				counters[r][j] = 0
+			}
+		}
+		return counters
+	}
+	var s sketch
+	s.add(7, 1)
+	assignment := newCircuit{X: 7}
+	for r := range s {
+		for j := range s[r] {
+			assignment.Expected[r][j] = s[r][j]
+		}
+	}
+	invalid := assignment
+	invalid.X = 8
+	assert.NoError(test.IsSolved(&newCircuit{Counters: zeros()}, &assignment, ecc.BN254.ScalarField()))
+	assert.Error(test.IsSolved(&newCircuit{Counters: zeros()}, &invalid, ecc.BN254.ScalarField()))
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &newCircuit{Counters: zeros()})
+	assert.NoError(err)
+}
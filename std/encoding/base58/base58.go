@@ -0,0 +1,112 @@
+// Package base58 implements base58 and base58check decoding of byte strings,
+// using the Bitcoin alphabet.
+//
+// The encoded string and the decoded length are fixed at circuit compile time.
+// The decoding is done through a hint and the result is constrained by
+// comparing the integer values of both representations in the native field.
+// For this, the value of the encoded string must fit in the native field, which
+// limits the length of the inputs (at most 43 characters for BN254). Leading
+// zero bytes are not checked to correspond to leading '1' characters.
+package base58
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// Alphabet is the Bitcoin base58 alphabet.
+const Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// ChecksumLen is the length in bytes of the base58check checksum.
+const ChecksumLen = 4
+
+// Decode decodes the base58 encoded ASCII characters into decodedLen bytes.
+// It fails if a character is not in [Alphabet] or if the value does not fit
+// in decodedLen bytes.
+func Decode(api frontend.API, encoded []uints.U8, decodedLen int) ([]uints.U8, error) {
+	if len(encoded) == 0 || decodedLen <= 0 {
+		return nil, fmt.Errorf("empty input or output")
+	}
+	nbBits := api.Compiler().FieldBitLen()
+	bound := new(big.Int).Exp(big.NewInt(58), big.NewInt(int64(len(encoded))), nil)
+	if bound.BitLen() >= nbBits || 8*decodedLen >= nbBits {
+		return nil, fmt.Errorf("input of %d characters or output of %d bytes does not fit in the native field", len(encoded), decodedLen)
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	rchecker := rangecheck.New(api)
+
+	// map the characters to digits. Characters not in the alphabet map to 58,
+	// which is rejected by the range check below.
+	tbl := logderivlookup.New(api)
+	var digitOf [256]int
+	for i := range digitOf {
+		digitOf[i] = 58
+	}
+	for i := range Alphabet {
+		digitOf[Alphabet[i]] = i
+	}
+	for i := range digitOf {
+		tbl.Insert(digitOf[i])
+	}
+	chars := make([]frontend.Variable, len(encoded))
+	for i := range encoded {
+		chars[i] = encoded[i].Val
+	}
+	digits := tbl.Lookup(chars...)
+
+	encodedV := frontend.Variable(0)
+	for i := range digits {
+		rchecker.Check(api.Sub(57, digits[i]), 6)
+		encodedV = api.Add(api.Mul(encodedV, 58), digits[i])
+	}
+
+	decodedV, err := api.Compiler().NewHint(decodeHint, decodedLen, digits...)
+	if err != nil {
+		return nil, fmt.Errorf("new hint: %w", err)
+	}
+	decoded := make([]uints.U8, decodedLen)
+	value := frontend.Variable(0)
+	for i := range decodedV {
+		decoded[i] = uapi.ByteValueOf(decodedV[i])
+		value = api.Add(api.Mul(value, 256), decodedV[i])
+	}
+	api.AssertIsEqual(encodedV, value)
+	return decoded, nil
+}
+
+// DecodeCheck decodes the base58check encoded ASCII characters into
+// decodedLen bytes and asserts that the last [ChecksumLen] of them are the
+// first bytes of the double SHA256 of the others. It returns the payload
+// without the checksum, including the version byte(s) if any.
+func DecodeCheck(api frontend.API, encoded []uints.U8, decodedLen int) ([]uints.U8, error) {
+	if decodedLen <= ChecksumLen {
+		return nil, fmt.Errorf("decoded length %d too short for the checksum", decodedLen)
+	}
+	decoded, err := Decode(api, encoded, decodedLen)
+	if err != nil {
+		return nil, err
+	}
+	payload, checksum := decoded[:decodedLen-ChecksumLen], decoded[decodedLen-ChecksumLen:]
+	digest := payload
+	for i := 0; i < 2; i++ {
+		h, err := sha2.New(api)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(digest)
+		digest = h.Sum()
+	}
+	for i := range checksum {
+		api.AssertIsEqual(checksum[i].Val, digest[i].Val)
+	}
+	return payload, nil
+}
@@ -0,0 +1,76 @@
+package base58
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type decodeCheckCircuit struct {
+	Encoded []uints.U8
+	Payload []uints.U8
+}
+
+func (c *decodeCheckCircuit) Define(api frontend.API) error {
+	payload, err := DecodeCheck(api, c.Encoded, len(c.Payload)+ChecksumLen)
+	if err != nil {
+		return err
+	}
+	for i := range payload {
+		api.AssertIsEqual(payload[i].Val, c.Payload[i].Val)
+	}
+	return nil
+}
+
+func TestDecodeCheck(t *testing.T) {
+	assert := test.NewAssert(t)
+	// genesis block coinbase address
+	encoded := "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"
+	payload, err := hex.DecodeString("0062e907b15cbf27d5425399ebf6f0fb50ebb88f18")
+	assert.NoError(err)
+
+	circuit := decodeCheckCircuit{
+		Encoded: make([]uints.U8, len(encoded)),
+		Payload: make([]uints.U8, len(payload)),
+	}
+	valid := decodeCheckCircuit{
+		Encoded: uints.NewU8Array([]byte(encoded)),
+		Payload: uints.NewU8Array(payload),
+	}
+	// last character changed, the checksum does not match.
+	wrongChecksum := decodeCheckCircuit{
+		Encoded: uints.NewU8Array([]byte(encoded[:len(encoded)-1] + "b")),
+		Payload: uints.NewU8Array(payload),
+	}
+	// '0' is not in the alphabet.
+	invalidChar := decodeCheckCircuit{
+		Encoded: uints.NewU8Array([]byte("0" + encoded[1:])),
+		Payload: uints.NewU8Array(payload),
+	}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&valid),
+		test.WithInvalidAssignment(&wrongChecksum),
+		test.WithInvalidAssignment(&invalidChar),
+		test.WithCurves(ecc.BN254))
+}
+
+type decodeCircuit struct {
+	Encoded []uints.U8
+}
+
+func (c *decodeCircuit) Define(api frontend.API) error {
+	_, err := Decode(api, c.Encoded, 32)
+	return err
+}
+
+func TestDecodeTooLong(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := decodeCircuit{Encoded: make([]uints.U8, 44)}
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit)
+	assert.Error(err)
+}
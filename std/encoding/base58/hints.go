@@ -0,0 +1,42 @@
+package base58
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in this package
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		decodeHint,
+	}
+}
+
+// decodeHint interprets the inputs as big-endian base58 digits and returns the
+// big-endian bytes of the value, padded to the number of outputs.
+func decodeHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	v := new(big.Int)
+	base := big.NewInt(58)
+	for i := range inputs {
+		v.Mul(v, base)
+		v.Add(v, inputs[i])
+	}
+	bts := v.Bytes()
+	if len(bts) > len(outputs) {
+		return fmt.Errorf("decoded value does not fit in %d bytes", len(outputs))
+	}
+	for i := range outputs {
+		outputs[i].SetUint64(0)
+	}
+	off := len(outputs) - len(bts)
+	for i := range bts {
+		outputs[off+i].SetUint64(uint64(bts[i]))
+	}
+	return nil
+}
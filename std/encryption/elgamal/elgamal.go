@@ -0,0 +1,63 @@
+// Package elgamal implements the verification of ElGamal encryptions on a
+// twisted Edwards curve embedded in the native field.
+//
+// The message m is encoded in the exponent, so that ciphertexts are additively
+// homomorphic. A ciphertext of m under the public key A = [s]G with randomness
+// r is
+//
+//	C1 = [r]G
+//	C2 = [m]G + [r]A
+//
+// where G is the base point of the curve. Decryption requires solving a
+// discrete logarithm, so the scheme is meant for small messages such as
+// balances.
+package elgamal
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+)
+
+// PublicKey is an ElGamal public key.
+type PublicKey struct {
+	A twistededwards.Point
+}
+
+// Ciphertext is an ElGamal ciphertext.
+type Ciphertext struct {
+	C1, C2 twistededwards.Point
+}
+
+// AssertIsEncryption asserts that ct is the encryption of m with randomness r
+// under the public key pk.
+func AssertIsEncryption(curve twistededwards.Curve, ct Ciphertext, pk PublicKey, m, r frontend.Variable) {
+	expected := Encrypt(curve, pk, m, r)
+	api := curve.API()
+	api.AssertIsEqual(ct.C1.X, expected.C1.X)
+	api.AssertIsEqual(ct.C1.Y, expected.C1.Y)
+	api.AssertIsEqual(ct.C2.X, expected.C2.X)
+	api.AssertIsEqual(ct.C2.Y, expected.C2.Y)
+}
+
+// Encrypt returns the encryption of m with randomness r under the public key
+// pk. The public key is asserted to be on the curve.
+func Encrypt(curve twistededwards.Curve, pk PublicKey, m, r frontend.Variable) Ciphertext {
+	curve.AssertIsOnCurve(pk.A)
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+	return Ciphertext{
+		C1: curve.ScalarMul(base, r),
+		C2: curve.DoubleBaseScalarMul(base, pk.A, m, r),
+	}
+}
+
+// Add returns the encryption of the sum of the messages encrypted by a and b,
+// with the sum of their randomness.
+func Add(curve twistededwards.Curve, a, b Ciphertext) Ciphertext {
+	return Ciphertext{
+		C1: curve.Add(a.C1, b.C1),
+		C2: curve.Add(a.C2, b.C2),
+	}
+}
@@ -0,0 +1,107 @@
+package elgamal
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	gtwistededwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+type encryptionCircuit struct {
+	PublicKey  PublicKey  `gnark:",public"`
+	Ciphertext Ciphertext `gnark:",public"`
+	M, R       frontend.Variable
+}
+
+func (c *encryptionCircuit) Define(api frontend.API) error {
+	curve, err := gtwistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	AssertIsEncryption(curve, c.Ciphertext, c.PublicKey, c.M, c.R)
+	return nil
+}
+
+type addCircuit struct {
+	A, B, Sum Ciphertext
+}
+
+func (c *addCircuit) Define(api frontend.API) error {
+	curve, err := gtwistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	res := Add(curve, c.A, c.B)
+	api.AssertIsEqual(res.C1.X, c.Sum.C1.X)
+	api.AssertIsEqual(res.C1.Y, c.Sum.C1.Y)
+	api.AssertIsEqual(res.C2.X, c.Sum.C2.X)
+	api.AssertIsEqual(res.C2.Y, c.Sum.C2.Y)
+	return nil
+}
+
+func point(p *twistededwards.PointAffine) gtwistededwards.Point {
+	return gtwistededwards.Point{X: p.X, Y: p.Y}
+}
+
+// encrypt computes the encryption off-circuit.
+func encrypt(pk *twistededwards.PointAffine, m, r *big.Int) Ciphertext {
+	base := twistededwards.GetEdwardsCurve().Base
+	var c1, c2, rA twistededwards.PointAffine
+	c1.ScalarMultiplication(&base, r)
+	c2.ScalarMultiplication(&base, m)
+	rA.ScalarMultiplication(pk, r)
+	c2.Add(&c2, &rA)
+	return Ciphertext{C1: point(&c1), C2: point(&c2)}
+}
+
+func randomScalar(t *testing.T) *big.Int {
+	order := twistededwards.GetEdwardsCurve().Order
+	r, err := rand.Int(rand.Reader, &order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestEncryption(t *testing.T) {
+	assert := test.NewAssert(t)
+	base := twistededwards.GetEdwardsCurve().Base
+	var pk twistededwards.PointAffine
+	pk.ScalarMultiplication(&base, randomScalar(t))
+
+	m, r := big.NewInt(42), randomScalar(t)
+	ct := encrypt(&pk, m, r)
+
+	tampered := ct
+	tampered.C2 = encrypt(&pk, big.NewInt(43), r).C2
+
+	assert.CheckCircuit(&encryptionCircuit{},
+		test.WithValidAssignment(&encryptionCircuit{PublicKey: PublicKey{A: point(&pk)}, Ciphertext: ct, M: m, R: r}),
+		test.WithInvalidAssignment(&encryptionCircuit{PublicKey: PublicKey{A: point(&pk)}, Ciphertext: tampered, M: m, R: r}),
+		test.WithInvalidAssignment(&encryptionCircuit{PublicKey: PublicKey{A: point(&pk)}, Ciphertext: ct, M: 43, R: r}),
+		test.WithCurves(ecc.BN254))
+}
+
+func TestAdd(t *testing.T) {
+	assert := test.NewAssert(t)
+	base := twistededwards.GetEdwardsCurve().Base
+	var pk twistededwards.PointAffine
+	pk.ScalarMultiplication(&base, randomScalar(t))
+
+	r1, r2 := randomScalar(t), randomScalar(t)
+	a := encrypt(&pk, big.NewInt(10), r1)
+	b := encrypt(&pk, big.NewInt(32), r2)
+	sum := encrypt(&pk, big.NewInt(42), new(big.Int).Add(r1, r2))
+	wrong := encrypt(&pk, big.NewInt(41), new(big.Int).Add(r1, r2))
+
+	assert.CheckCircuit(&addCircuit{},
+		test.WithValidAssignment(&addCircuit{A: a, B: b, Sum: sum}),
+		test.WithInvalidAssignment(&addCircuit{A: a, B: b, Sum: wrong}),
+		test.WithCurves(ecc.BN254))
+}
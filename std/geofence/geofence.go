@@ -0,0 +1,88 @@
+// Package geofence implements point-in-polygon verification.
+//
+// The coordinates are signed fixed-point numbers represented as integers
+// scaled by a common factor 2^k, for example the latitude and longitude in
+// degrees multiplied by 2^16. As the ray casting algorithm only compares
+// coordinates and products of coordinate differences, the scaling factor does
+// not need to be known by the gadget. Negative coordinates are represented as
+// their negation in the native field.
+//
+// The absolute value of every coordinate must be smaller than 2^nbBits, which
+// is enforced with range checks. The gadget requires 2*nbBits+4 to be smaller
+// than the bit length of the native field.
+package geofence
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/cmp"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// Point is a point with fixed-point coordinates.
+type Point struct {
+	X, Y frontend.Variable
+}
+
+// IsInside returns 1 if the point p is strictly inside the polygon defined by
+// the vertices and 0 if it is outside, using the ray casting algorithm. The
+// polygon is closed implicitly between the last and the first vertex. The
+// result is unspecified for points on the boundary of the polygon.
+func IsInside(api frontend.API, vertices []Point, p Point, nbBits int) (frontend.Variable, error) {
+	if len(vertices) < 3 {
+		return nil, fmt.Errorf("polygon must have at least 3 vertices, got %d", len(vertices))
+	}
+	if nbBits < 1 || 2*nbBits+4 >= api.Compiler().FieldBitLen() {
+		return nil, fmt.Errorf("invalid coordinate bit length %d", nbBits)
+	}
+	rchecker := rangecheck.New(api)
+	assertBounded := func(v frontend.Variable) {
+		// |v| < 2^nbBits <==> 0 <= v + 2^nbBits < 2^(nbBits+1)
+		rchecker.Check(api.Add(v, new(big.Int).Lsh(big.NewInt(1), uint(nbBits))), nbBits+1)
+	}
+	assertBounded(p.X)
+	assertBounded(p.Y)
+	for i := range vertices {
+		assertBounded(vertices[i].X)
+		assertBounded(vertices[i].Y)
+	}
+	// the coordinate differences are smaller than 2^(nbBits+1) and the
+	// products of differences smaller than 2^(2*nbBits+2).
+	coordCmp := cmp.NewBoundedComparator(api, new(big.Int).Lsh(big.NewInt(1), uint(nbBits+1)), false)
+	prodCmp := cmp.NewBoundedComparator(api, new(big.Int).Lsh(big.NewInt(1), uint(2*nbBits+3)), false)
+
+	inside := frontend.Variable(0)
+	for i := range vertices {
+		a, b := vertices[i], vertices[(i+1)%len(vertices)]
+		// the horizontal ray from p crosses the edge if the edge straddles
+		// the ray, i.e. (a.Y > p.Y) != (b.Y > p.Y), and the crossing point is
+		// on the right of p, i.e.
+		//
+		//	p.X < a.X + (b.X - a.X) * (p.Y - a.Y) / (b.Y - a.Y).
+		//
+		// To avoid the division we compare
+		//
+		//	(p.X - a.X) * (b.Y - a.Y) < (b.X - a.X) * (p.Y - a.Y)
+		//
+		// and reverse the comparison if b.Y < a.Y.
+		straddles := api.Xor(coordCmp.IsLess(p.Y, a.Y), coordCmp.IsLess(p.Y, b.Y))
+		lhs := api.Mul(api.Sub(p.X, a.X), api.Sub(b.Y, a.Y))
+		rhs := api.Mul(api.Sub(b.X, a.X), api.Sub(p.Y, a.Y))
+		isRight := api.Select(coordCmp.IsLess(a.Y, b.Y), prodCmp.IsLess(lhs, rhs), prodCmp.IsLess(rhs, lhs))
+		inside = api.Xor(inside, api.And(straddles, isRight))
+	}
+	return inside, nil
+}
+
+// AssertIsInside asserts that the point p is strictly inside the polygon
+// defined by the vertices. See [IsInside].
+func AssertIsInside(api frontend.API, vertices []Point, p Point, nbBits int) error {
+	inside, err := IsInside(api, vertices, p, nbBits)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(inside, 1)
+	return nil
+}
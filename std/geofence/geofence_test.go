@@ -0,0 +1,48 @@
+package geofence
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type geofenceCircuit struct {
+	Vertices [5]Point `gnark:",public"`
+	P        Point
+}
+
+func (c *geofenceCircuit) Define(api frontend.API) error {
+	return AssertIsInside(api, c.Vertices[:], c.P, 24)
+}
+
+// fixedPoint returns v scaled by 2^16.
+func fixedPoint(v float64) int64 {
+	return int64(v * (1 << 16))
+}
+
+func point(x, y float64) Point {
+	return Point{X: fixedPoint(x), Y: fixedPoint(y)}
+}
+
+func TestAssertIsInside(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// concave polygon with a notch at the top
+	vertices := [5]Point{point(-5, -5), point(5, -5), point(5, 5), point(0, 0), point(-5, 5)}
+	inside := []Point{point(0, -2), point(4.5, 2.25), point(-4.75, 4.5)}
+	outside := []Point{point(0, 3), point(12, 1), point(-5.5, 0), point(1.5, -6)}
+
+	var circuit geofenceCircuit
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254)}
+	for _, p := range inside {
+		opts = append(opts, test.WithValidAssignment(&geofenceCircuit{Vertices: vertices, P: p}))
+	}
+	for _, p := range outside {
+		opts = append(opts, test.WithInvalidAssignment(&geofenceCircuit{Vertices: vertices, P: p}))
+	}
+	// coordinates out of bounds
+	opts = append(opts, test.WithInvalidAssignment(&geofenceCircuit{Vertices: vertices, P: point(0, 1<<9)}))
+	assert.CheckCircuit(&circuit, opts...)
+}
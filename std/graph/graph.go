@@ -0,0 +1,38 @@
+// Package graph provides ZKP-circuit functions to prove that an edge belongs
+// to a committed graph, without revealing the other edges.
+//
+// The graph is committed with the root of a Merkle tree over its adjacency
+// list: the leaf i holds the encoding H(from, to) of the i-th edge, see
+// [EncodeEdge], and the tree is the one of the package
+// [github.com/consensys/gnark/std/accumulator/merkle]. The edges are directed,
+// an undirected graph commits both orientations of its edges. The order of
+// the edges is free, so the prover can reveal only an edge and its position.
+package graph
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// Edge is a directed edge between the nodes From and To, identified by field
+// elements.
+type Edge struct {
+	From, To frontend.Variable
+}
+
+// EncodeEdge returns the leaf data of the edge, H(From, To).
+func EncodeEdge(h hash.FieldHasher, e Edge) frontend.Variable {
+	h.Reset()
+	h.Write(e.From, e.To)
+	return h.Sum()
+}
+
+// AssertHasEdge asserts that the edge is the edge at index in the adjacency
+// list committed by proof.RootHash. proof.Path[0] is the encoding of the edge,
+// and the rest of the path the Merkle proof of the leaf at index, see
+// [merkle.MerkleProof.VerifyProof].
+func AssertHasEdge(api frontend.API, h hash.FieldHasher, proof merkle.MerkleProof, index frontend.Variable, e Edge) {
+	api.AssertIsEqual(proof.Path[0], EncodeEdge(h, e))
+	proof.VerifyProof(api, h, index)
+}
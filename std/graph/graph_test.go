@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const depth = 3
+
+type edgeCircuit struct {
+	Proof merkle.MerkleProof
+	Index frontend.Variable
+	Edge  Edge `gnark:",public"`
+}
+
+func (c *edgeCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	AssertHasEdge(api, &h, c.Proof, c.Index, c.Edge)
+	return nil
+}
+
+// encodeEdge computes the encoding of the edge off-circuit, as EncodeEdge.
+func encodeEdge(from, to int64) []byte {
+	h := cryptohash.MIMC_BN254.New()
+	var buf [32]byte
+	big.NewInt(from).FillBytes(buf[:])
+	h.Write(buf[:])
+	big.NewInt(to).FillBytes(buf[:])
+	h.Write(buf[:])
+	return h.Sum(nil)
+}
+
+// edgeAssignment commits the adjacency list and returns the assignment proving
+// the membership of the edge at index, claimed to be (from, to).
+func edgeAssignment(t *testing.T, edges [][2]int64, index uint64, from, to int64) *edgeCircuit {
+	var buf bytes.Buffer
+	for _, e := range edges {
+		buf.Write(encodeEdge(e[0], e[1]))
+	}
+	h := cryptohash.MIMC_BN254.New()
+	root, path, nbLeaves, err := merkletree.BuildReaderProof(&buf, h, 32, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !merkletree.VerifyProof(h, root, path, index, nbLeaves) {
+		t.Fatal("invalid proof")
+	}
+	a := &edgeCircuit{
+		Proof: merkle.MerkleProof{RootHash: root, Path: make([]frontend.Variable, len(path))},
+		Index: index,
+		Edge:  Edge{From: from, To: to},
+	}
+	for i := range path {
+		a.Proof.Path[i] = path[i]
+	}
+	return a
+}
+
+func TestAssertHasEdge(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// a directed graph over the nodes 1 to 5
+	edges := [][2]int64{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {4, 5}, {5, 1}, {2, 5}, {3, 5}}
+	circuit := &edgeCircuit{Proof: merkle.MerkleProof{Path: make([]frontend.Variable, depth+1)}}
+
+	for i, e := range edges {
+		assert.CheckCircuit(circuit,
+			test.WithValidAssignment(edgeAssignment(t, edges, uint64(i), e[0], e[1])),
+			// the edges are directed
+			test.WithInvalidAssignment(edgeAssignment(t, edges, uint64(i), e[1], e[0])),
+			test.WithCurves(ecc.BN254))
+	}
+
+	// a non-edge can not be proven at any position
+	for i := range edges {
+		assert.CheckCircuit(circuit,
+			test.WithInvalidAssignment(edgeAssignment(t, edges, uint64(i), 1, 4)),
+			test.WithCurves(ecc.BN254), test.NoProverChecks())
+	}
+
+	// the proof of an edge is not valid at another position
+	a := edgeAssignment(t, edges, 2, 2, 4)
+	a.Index = 3
+	assert.CheckCircuit(circuit, test.WithInvalidAssignment(a), test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,89 @@
+// Package hmac implements HMAC-SHA256 message authentication code computation.
+//
+// The output matches the one of [crypto/hmac] with [crypto/sha256]. The key
+// length is fixed at circuit compile time.
+package hmac
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const blockSize = 64
+
+type digest struct {
+	api  frontend.API
+	uapi *uints.BinaryField[uints.U32]
+	ipad []uints.U8
+	opad []uints.U8
+	in   []uints.U8
+}
+
+// NewSHA256 returns a new HMAC-SHA256 hasher using the given key. Keys longer
+// than the SHA256 block size are hashed first, shorter keys are padded with
+// zeros, as in [crypto/hmac].
+func NewSHA256(api frontend.API, key []uints.U8) (hash.BinaryHasher, error) {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) > blockSize {
+		h, err := sha2.New(api)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(key)
+		key = h.Sum()
+	}
+	padded := make([]uints.U8, blockSize)
+	copy(padded, key)
+	for i := len(key); i < blockSize; i++ {
+		padded[i] = uints.NewU8(0)
+	}
+	d := &digest{
+		api:  api,
+		uapi: uapi,
+		ipad: xorPad(uapi, padded, 0x36363636),
+		opad: xorPad(uapi, padded, 0x5c5c5c5c),
+	}
+	return d, nil
+}
+
+// xorPad returns the key XORed with the repeated padding byte. The XOR is done
+// on 32-bit words to reuse the lookup tables of the SHA256 gadget.
+func xorPad(uapi *uints.BinaryField[uints.U32], key []uints.U8, pad uint32) []uints.U8 {
+	res := make([]uints.U8, 0, len(key))
+	for i := 0; i < len(key); i += 4 {
+		w := uapi.Xor(uapi.PackMSB(key[i:i+4]...), uints.NewU32(pad))
+		res = append(res, uapi.UnpackMSB(w)...)
+	}
+	return res
+}
+
+func (d *digest) Write(data []uints.U8) {
+	d.in = append(d.in, data...)
+}
+
+func (d *digest) Sum() []uints.U8 {
+	inner, err := sha2.New(d.api)
+	if err != nil {
+		panic(err)
+	}
+	inner.Write(d.ipad)
+	inner.Write(d.in)
+	outer, err := sha2.New(d.api)
+	if err != nil {
+		panic(err)
+	}
+	outer.Write(d.opad)
+	outer.Write(inner.Sum())
+	return outer.Sum()
+}
+
+func (d *digest) Reset() {
+	d.in = nil
+}
+
+func (d *digest) Size() int { return 32 }
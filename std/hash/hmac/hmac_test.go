@@ -0,0 +1,67 @@
+package hmac
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type hmacCircuit struct {
+	Key      []uints.U8
+	In       []uints.U8
+	Expected [32]uints.U8
+}
+
+func (c *hmacCircuit) Define(api frontend.API) error {
+	h, err := NewSHA256(api, c.Key)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.Sum()
+	if len(res) != 32 {
+		return fmt.Errorf("not 32 bytes")
+	}
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestHMAC(t *testing.T) {
+	msg := []byte("what do ya want for nothing?")
+	for _, keyLen := range []int{4, 64, 100} {
+		key := make([]byte, keyLen)
+		for i := range key {
+			key[i] = byte(i)
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write(msg)
+		dgst := mac.Sum(nil)
+		witness := hmacCircuit{
+			Key: uints.NewU8Array(key),
+			In:  uints.NewU8Array(msg),
+		}
+		copy(witness.Expected[:], uints.NewU8Array(dgst))
+		circuit := hmacCircuit{Key: make([]uints.U8, len(key)), In: make([]uints.U8, len(msg))}
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		if err != nil {
+			t.Fatalf("key length %d: %v", keyLen, err)
+		}
+		// tampered message
+		witness.In = uints.NewU8Array([]byte("what do ya want for nothing!"))
+		if err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()); err == nil {
+			t.Fatalf("key length %d: expected error for tampered message", keyLen)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Package histogram implements the verification of the histogram of a
+// dataset.
+//
+// The histogram has k buckets delimited by k+1 strictly increasing bounds: the
+// bucket i holds the values v with
+//
+//	bounds[i] <= v < bounds[i+1].
+//
+// The check does not commit to the dataset. For analytics over a committed
+// dataset, the circuit binds the data to its commitment, for example by
+// asserting that the hash of the data equals a public input.
+package histogram
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/cmp"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// AssertIsHistogram asserts that counts[i] is the number of values of data in
+// the bucket i delimited by bounds, with len(bounds) == len(counts)+1. It
+// asserts that the bounds are strictly increasing and that each value falls in
+// exactly one bucket, so that the counts sum to len(data).
+//
+// The data and the bounds are range checked to nbBits bits. Each value costs
+// len(bounds) comparisons of nbBits bits. It returns an error if the lengths
+// do not match or if nbBits is too large for the scalar field.
+func AssertIsHistogram(api frontend.API, data, bounds, counts []frontend.Variable, nbBits int) error {
+	if len(counts) == 0 || len(bounds) != len(counts)+1 {
+		return fmt.Errorf("expected %d bounds for %d buckets, got %d", len(counts)+1, len(counts), len(bounds))
+	}
+	if nbBits <= 0 || nbBits+2 >= api.Compiler().FieldBitLen() {
+		return fmt.Errorf("invalid number of bits %d", nbBits)
+	}
+	rchecker := rangecheck.New(api)
+	for i := range data {
+		rchecker.Check(data[i], nbBits)
+	}
+	for i := range bounds {
+		rchecker.Check(bounds[i], nbBits)
+	}
+	// all the differences are smaller than 2^nbBits
+	comparator := cmp.NewBoundedComparator(api, new(big.Int).Lsh(big.NewInt(1), uint(nbBits)), false)
+	for i := 1; i < len(bounds); i++ {
+		comparator.AssertIsLess(bounds[i-1], bounds[i])
+	}
+
+	sums := make([]frontend.Variable, len(counts))
+	for i := range sums {
+		sums[i] = 0
+	}
+	for _, v := range data {
+		// above[i] == 1 iff v >= bounds[i]. As the bounds are increasing, v is
+		// in the bucket i iff above[i] - above[i+1] == 1.
+		above := make([]frontend.Variable, len(bounds))
+		for i := range bounds {
+			above[i] = api.Sub(1, comparator.IsLess(v, bounds[i]))
+		}
+		api.AssertIsEqual(above[0], 1)
+		api.AssertIsEqual(above[len(bounds)-1], 0)
+		for i := range sums {
+			sums[i] = api.Add(sums[i], api.Sub(above[i], above[i+1]))
+		}
+	}
+
+	// conservation: the counts sum to the size of the dataset
+	var total frontend.Variable = 0
+	for i := range counts {
+		api.AssertIsEqual(counts[i], sums[i])
+		total = api.Add(total, counts[i])
+	}
+	api.AssertIsEqual(total, len(data))
+	return nil
+}
@@ -0,0 +1,51 @@
+package histogram
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type histogramCircuit struct {
+	Data   [6]frontend.Variable
+	Bounds [4]frontend.Variable `gnark:",public"`
+	Counts [3]frontend.Variable `gnark:",public"`
+}
+
+func (c *histogramCircuit) Define(api frontend.API) error {
+	return AssertIsHistogram(api, c.Data[:], c.Bounds[:], c.Counts[:], 16)
+}
+
+func assignment(data [6]int, bounds [4]int, counts [3]int) *histogramCircuit {
+	var c histogramCircuit
+	for i := range data {
+		c.Data[i] = data[i]
+	}
+	for i := range bounds {
+		c.Bounds[i] = bounds[i]
+	}
+	for i := range counts {
+		c.Counts[i] = counts[i]
+	}
+	return &c
+}
+
+func TestHistogram(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	data := [6]int{3, 10, 0, 19, 10, 9}
+	bounds := [4]int{0, 10, 15, 20}
+	assert.CheckCircuit(&histogramCircuit{},
+		test.WithValidAssignment(assignment(data, bounds, [3]int{3, 2, 1})),
+		// a value moved to the wrong bucket
+		test.WithInvalidAssignment(assignment(data, bounds, [3]int{2, 3, 1})),
+		// the counts do not sum to the size of the dataset
+		test.WithInvalidAssignment(assignment(data, bounds, [3]int{3, 2, 2})),
+		// a value out of the buckets
+		test.WithInvalidAssignment(assignment([6]int{3, 10, 0, 20, 10, 9}, bounds, [3]int{3, 2, 1})),
+		// bounds not increasing
+		test.WithInvalidAssignment(assignment(data, [4]int{0, 10, 10, 20}, [3]int{3, 0, 3})),
+		test.WithCurves(ecc.BN254))
+}
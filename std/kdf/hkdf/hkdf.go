@@ -0,0 +1,66 @@
+// Package hkdf implements the HMAC-based extract-and-expand key derivation
+// function HKDF-SHA256, as specified in RFC 5869.
+//
+// The output matches the one of [golang.org/x/crypto/hkdf] with
+// [crypto/sha256]. The lengths of the inputs and of the derived key are fixed
+// at circuit compile time.
+package hkdf
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/hmac"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// hashLen is the size of the SHA256 digest.
+const hashLen = 32
+
+// MaxLength is the largest length of a key derived by [Expand].
+const MaxLength = 255 * hashLen
+
+// Key derives a key of length bytes from the secret input keying material, the
+// salt and the context info. It is [Expand] of the pseudorandom key returned by
+// [Extract].
+func Key(api frontend.API, secret, salt, info []uints.U8, length int) ([]uints.U8, error) {
+	prk, err := Extract(api, secret, salt)
+	if err != nil {
+		return nil, err
+	}
+	return Expand(api, prk, info, length)
+}
+
+// Extract returns the pseudorandom key HMAC-SHA256(salt, secret) of 32 bytes.
+// An empty salt is equivalent to a salt of 32 zero bytes.
+func Extract(api frontend.API, secret, salt []uints.U8) ([]uints.U8, error) {
+	h, err := hmac.NewSHA256(api, salt)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(secret)
+	return h.Sum(), nil
+}
+
+// Expand derives a key of length bytes from the pseudorandom key prk and the
+// context info. The length must be in [1, [MaxLength]].
+func Expand(api frontend.API, prk, info []uints.U8, length int) ([]uints.U8, error) {
+	if length < 1 || length > MaxLength {
+		return nil, errors.New("hkdf: the length must be positive and at most 255*32")
+	}
+	res := make([]uints.U8, 0, length+hashLen-1)
+	var prev []uints.U8
+	for i := 1; len(res) < length; i++ {
+		// T(i) = HMAC(prk, T(i-1) || info || i)
+		h, err := hmac.NewSHA256(api, prk)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(prev)
+		h.Write(info)
+		h.Write([]uints.U8{uints.NewU8(uint8(i))})
+		prev = h.Sum()
+		res = append(res, prev...)
+	}
+	return res[:length], nil
+}
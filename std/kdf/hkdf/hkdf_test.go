@@ -0,0 +1,76 @@
+package hkdf
+
+import (
+	"crypto/sha256"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	xhkdf "golang.org/x/crypto/hkdf"
+)
+
+type hkdfCircuit struct {
+	Secret []uints.U8
+	Salt   []uints.U8 `gnark:",public"`
+	Info   []uints.U8 `gnark:",public"`
+	Key    []uints.U8 `gnark:",public"`
+}
+
+func (c *hkdfCircuit) Define(api frontend.API) error {
+	key, err := Key(api, c.Secret, c.Salt, c.Info, len(c.Key))
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	for i := range key {
+		uapi.ByteAssertEq(key[i], c.Key[i])
+	}
+	return nil
+}
+
+func TestKey(t *testing.T) {
+	assert := test.NewAssert(t)
+	// RFC 5869, test case 1
+	secret := make([]byte, 22)
+	for i := range secret {
+		secret[i] = 0x0b
+	}
+	salt := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c}
+	info := []byte{0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8, 0xf9}
+	key := make([]byte, 42)
+	_, err := io.ReadFull(xhkdf.New(sha256.New, secret, salt, info), key)
+	assert.NoError(err)
+
+	circuit := hkdfCircuit{
+		Secret: make([]uints.U8, len(secret)),
+		Salt:   make([]uints.U8, len(salt)),
+		Info:   make([]uints.U8, len(info)),
+		Key:    make([]uints.U8, len(key)),
+	}
+	valid := hkdfCircuit{
+		Secret: uints.NewU8Array(secret),
+		Salt:   uints.NewU8Array(salt),
+		Info:   uints.NewU8Array(info),
+		Key:    uints.NewU8Array(key),
+	}
+	assert.NoError(test.IsSolved(&circuit, &valid, ecc.BN254.ScalarField()))
+
+	secret[0] = 0x0c
+	invalid := valid
+	invalid.Secret = uints.NewU8Array(secret)
+	assert.Error(test.IsSolved(&circuit, &invalid, ecc.BN254.ScalarField()))
+}
+
+func TestExpandLength(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, length := range []int{0, MaxLength + 1} {
+		_, err := Expand(nil, nil, nil, length)
+		assert.Error(err)
+	}
+}
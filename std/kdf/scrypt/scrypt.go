@@ -0,0 +1,162 @@
+// Package scrypt implements the scrypt key derivation function, as specified
+// in RFC 7914.
+//
+// The output matches the one of [golang.org/x/crypto/scrypt.Key]. As every
+// Salsa20/8 core and SHA256 block costs thousands of constraints, only small
+// work factors are practical in-circuit and the cost parameter N is limited to
+// at most 256. The lengths of the password and the salt are fixed at circuit
+// compile time.
+package scrypt
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/hmac"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// MaxN is the largest supported value of the cost parameter N.
+const MaxN = 256
+
+// Key derives a key of keyLen bytes from the password and the salt with the
+// cost parameters N, r and p. N must be a power of two in [2, [MaxN]].
+func Key(api frontend.API, password, salt []uints.U8, N, r, p, keyLen int) ([]uints.U8, error) {
+	if N <= 1 || N&(N-1) != 0 || N > MaxN {
+		return nil, errors.New("scrypt: N must be > 1, a power of 2 and at most 256")
+	}
+	if r < 1 || p < 1 || keyLen < 1 {
+		return nil, errors.New("scrypt: r, p and the key length must be positive")
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	s := &scrypt{api: api, uapi: uapi, r: r, n: N}
+
+	b, err := pbkdf2(api, password, salt, p*128*r)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < p; i++ {
+		s.smix(b[i*128*r : (i+1)*128*r])
+	}
+	return pbkdf2(api, password, b, keyLen)
+}
+
+// pbkdf2 returns the PBKDF2-HMAC-SHA256 of the password and the salt with a
+// single iteration, as used in scrypt.
+func pbkdf2(api frontend.API, password, salt []uints.U8, keyLen int) ([]uints.U8, error) {
+	res := make([]uints.U8, 0, keyLen+31)
+	var counter [4]byte
+	for i := uint32(1); len(res) < keyLen; i++ {
+		h, err := hmac.NewSHA256(api, password)
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint32(counter[:], i)
+		h.Write(salt)
+		h.Write(uints.NewU8Array(counter[:]))
+		res = append(res, h.Sum()...)
+	}
+	return res[:keyLen], nil
+}
+
+type scrypt struct {
+	api  frontend.API
+	uapi *uints.BinaryField[uints.U32]
+	r, n int
+}
+
+// smix mixes the block b of 128*r bytes in place.
+func (s *scrypt) smix(b []uints.U8) {
+	R := 32 * s.r
+	x := make([]uints.U32, R)
+	for i := range x {
+		x[i] = s.uapi.PackLSB(b[4*i : 4*i+4]...)
+	}
+	v := make([][]uints.U32, s.n)
+	for i := 0; i < s.n; i++ {
+		v[i] = x
+		x = s.blockMix(x)
+	}
+	nbIdxBits := 0
+	for 1<<nbIdxBits < s.n {
+		nbIdxBits++
+	}
+	for i := 0; i < s.n; i++ {
+		// j = Integerify(x) mod N depends on the lowest bits of the first word
+		// of the last 64 bytes sub-block.
+		lowBits := bits.ToBinary(s.api, x[(2*s.r-1)*16][0].Val, bits.WithNbDigits(8))
+		j := bits.FromBinary(s.api, lowBits[:nbIdxBits], bits.WithUnconstrainedInputs())
+		y := make([]uints.U32, R)
+		for k := range y {
+			y[k] = s.uapi.Xor(x[k], s.mux(j, v, k))
+		}
+		x = s.blockMix(y)
+	}
+	for i := range x {
+		copy(b[4*i:4*i+4], s.uapi.UnpackLSB(x[i]))
+	}
+}
+
+// mux returns the word k of v[j].
+func (s *scrypt) mux(j frontend.Variable, v [][]uints.U32, k int) uints.U32 {
+	var res uints.U32
+	inputs := make([]frontend.Variable, len(v))
+	for l := range res {
+		for i := range v {
+			inputs[i] = v[i][k][l].Val
+		}
+		res[l] = uints.U8{Val: selector.Mux(s.api, j, inputs...)}
+	}
+	return res
+}
+
+// blockMix is the BlockMix function of scrypt with Salsa20/8 on 2*r sub-blocks
+// of 16 words.
+func (s *scrypt) blockMix(in []uints.U32) []uints.U32 {
+	out := make([]uints.U32, len(in))
+	var t [16]uints.U32
+	copy(t[:], in[(2*s.r-1)*16:])
+	for i := 0; i < 2*s.r; i++ {
+		for k := range t {
+			t[k] = s.uapi.Xor(t[k], in[i*16+k])
+		}
+		t = s.salsa208(t)
+		// even sub-blocks go to the first half, odd ones to the second half.
+		dst := (i/2)*16 + (i%2)*s.r*16
+		copy(out[dst:dst+16], t[:])
+	}
+	return out
+}
+
+// salsa208 is the Salsa20/8 core function.
+func (s *scrypt) salsa208(in [16]uints.U32) [16]uints.U32 {
+	x := in
+	qr := func(a, b, c, d int) {
+		x[b] = s.uapi.Xor(x[b], s.uapi.Lrot(s.uapi.Add(x[a], x[d]), 7))
+		x[c] = s.uapi.Xor(x[c], s.uapi.Lrot(s.uapi.Add(x[b], x[a]), 9))
+		x[d] = s.uapi.Xor(x[d], s.uapi.Lrot(s.uapi.Add(x[c], x[b]), 13))
+		x[a] = s.uapi.Xor(x[a], s.uapi.Lrot(s.uapi.Add(x[d], x[c]), 18))
+	}
+	for i := 0; i < 8; i += 2 {
+		// columns
+		qr(0, 4, 8, 12)
+		qr(5, 9, 13, 1)
+		qr(10, 14, 2, 6)
+		qr(15, 3, 7, 11)
+		// rows
+		qr(0, 1, 2, 3)
+		qr(5, 6, 7, 4)
+		qr(10, 11, 8, 9)
+		qr(15, 12, 13, 14)
+	}
+	for i := range x {
+		x[i] = s.uapi.Add(x[i], in[i])
+	}
+	return x
+}
@@ -0,0 +1,78 @@
+package scrypt
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	xscrypt "golang.org/x/crypto/scrypt"
+)
+
+type scryptCircuit struct {
+	n, r, p  int
+	Password []uints.U8
+	Salt     []uints.U8 `gnark:",public"`
+	Key      []uints.U8 `gnark:",public"`
+}
+
+func (c *scryptCircuit) Define(api frontend.API) error {
+	key, err := Key(api, c.Password, c.Salt, c.n, c.r, c.p, len(c.Key))
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	for i := range key {
+		uapi.ByteAssertEq(key[i], c.Key[i])
+	}
+	return nil
+}
+
+func TestKey(t *testing.T) {
+	assert := test.NewAssert(t)
+	password, salt := []byte("password"), []byte("NaCl")
+	for _, params := range [][3]int{{2, 1, 1}, {4, 1, 2}} {
+		n, r, p := params[0], params[1], params[2]
+		key, err := xscrypt.Key(password, salt, n, r, p, 32)
+		assert.NoError(err)
+
+		circuit := scryptCircuit{
+			n: n, r: r, p: p,
+			Password: make([]uints.U8, len(password)),
+			Salt:     make([]uints.U8, len(salt)),
+			Key:      make([]uints.U8, len(key)),
+		}
+		valid := scryptCircuit{
+			Password: uints.NewU8Array(password),
+			Salt:     uints.NewU8Array(salt),
+			Key:      uints.NewU8Array(key),
+		}
+		assert.NoError(test.IsSolved(&circuit, &valid, ecc.BN254.ScalarField()))
+
+		invalid := scryptCircuit{
+			Password: uints.NewU8Array([]byte("passwore")),
+			Salt:     uints.NewU8Array(salt),
+			Key:      uints.NewU8Array(key),
+		}
+		assert.Error(test.IsSolved(&circuit, &invalid, ecc.BN254.ScalarField()))
+	}
+}
+
+type invalidParamsCircuit struct {
+	Password []uints.U8
+}
+
+func (c *invalidParamsCircuit) Define(api frontend.API) error {
+	_, err := Key(api, c.Password, c.Password, 3, 1, 1, 32)
+	return err
+}
+
+func TestInvalidParams(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := invalidParamsCircuit{Password: make([]uints.U8, 4)}
+	assert.Error(test.IsSolved(&circuit, &invalidParamsCircuit{Password: uints.NewU8Array([]byte("pass"))}, ecc.BN254.ScalarField()))
+}
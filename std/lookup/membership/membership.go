@@ -0,0 +1,105 @@
+// Package membership implements set membership gadgets.
+//
+// The membership of an element is checked with a lookup in the set, see
+// [logderivlookup]: the prover gives the index of the element in the set,
+// and all the lookups in a set are checked at once by the log-derivative
+// argument. The non-membership is checked by asserting that the product of the
+// differences between the element and the elements of the set is not zero.
+//
+// The sets do not need to be sorted, nor their elements to be distinct.
+package membership
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in this package
+func GetHints() []solver.Hint {
+	return []solver.Hint{indexHint}
+}
+
+// Set is a set of variables. Its lookup table is only built when checking
+// the membership of an element, and is shared by all the membership checks.
+type Set struct {
+	api      frontend.API
+	elements []frontend.Variable
+	table    *logderivlookup.Table
+}
+
+// New returns the set of elements.
+func New(api frontend.API, elements []frontend.Variable) *Set {
+	return &Set{api: api, elements: elements}
+}
+
+// AssertIsMember asserts that element is one of the elements of the set. It
+// panics at compile time if the set is empty.
+func (s *Set) AssertIsMember(element frontend.Variable) {
+	if len(s.elements) == 0 {
+		panic("membership in an empty set")
+	}
+	if s.table == nil {
+		s.table = logderivlookup.New(s.api)
+		for i := range s.elements {
+			s.table.Insert(s.elements[i])
+		}
+	}
+	inputs := make([]frontend.Variable, 0, len(s.elements)+1)
+	inputs = append(inputs, element)
+	inputs = append(inputs, s.elements...)
+	index, err := s.api.Compiler().NewHint(indexHint, 1, inputs...)
+	if err != nil {
+		panic(err)
+	}
+	s.api.AssertIsEqual(s.table.Lookup(index[0])[0], element)
+}
+
+// AssertIsNotMember asserts that element is none of the elements of the set.
+func (s *Set) AssertIsNotMember(element frontend.Variable) {
+	var res frontend.Variable = 1
+	for i := range s.elements {
+		res = s.api.Mul(res, s.api.Sub(s.elements[i], element))
+	}
+	s.api.AssertIsDifferent(res, 0)
+}
+
+// AssertIsMember asserts that element is one of the elements of set. Use a
+// [Set] to check the membership of many elements in the same set.
+func AssertIsMember(api frontend.API, element frontend.Variable, set []frontend.Variable) {
+	New(api, set).AssertIsMember(element)
+}
+
+// AssertIsNotMember asserts that element is none of the elements of set.
+func AssertIsNotMember(api frontend.API, element frontend.Variable, set []frontend.Variable) {
+	New(api, set).AssertIsNotMember(element)
+}
+
+// AssertInDifference asserts that element is in setA and not in setB.
+func AssertInDifference(api frontend.API, element frontend.Variable, setA, setB []frontend.Variable) {
+	AssertIsMember(api, element, setA)
+	AssertIsNotMember(api, element, setB)
+}
+
+// indexHint returns the index of inputs[0] in inputs[1:], or 0 if it is not
+// an element, to fail the lookup check.
+func indexHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 2 || len(outputs) != 1 {
+		return fmt.Errorf("expected at least 2 inputs and 1 output, got %d and %d", len(inputs), len(outputs))
+	}
+	outputs[0].SetUint64(0)
+	for i, e := range inputs[1:] {
+		if e.Cmp(inputs[0]) == 0 {
+			outputs[0].SetUint64(uint64(i))
+			break
+		}
+	}
+	return nil
+}
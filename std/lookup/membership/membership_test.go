@@ -0,0 +1,61 @@
+package membership
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type memberCircuit struct {
+	Elements []frontend.Variable
+	Set      []frontend.Variable
+}
+
+func (c *memberCircuit) Define(api frontend.API) error {
+	set := New(api, c.Set)
+	for i := range c.Elements {
+		set.AssertIsMember(c.Elements[i])
+	}
+	return nil
+}
+
+func TestAssertIsMember(t *testing.T) {
+	assert := test.NewAssert(t)
+	set := []frontend.Variable{13, 2, 8, 5, 8}
+	circuit := memberCircuit{Elements: make([]frontend.Variable, 3), Set: make([]frontend.Variable, len(set))}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&memberCircuit{Elements: []frontend.Variable{8, 13, 8}, Set: set}),
+		test.WithValidAssignment(&memberCircuit{Elements: []frontend.Variable{5, 2, 13}, Set: set}),
+		// the last element is not in the set
+		test.WithInvalidAssignment(&memberCircuit{Elements: []frontend.Variable{8, 13, 7}, Set: set}),
+		test.WithCurves(ecc.BN254))
+}
+
+type differenceCircuit struct {
+	Element    frontend.Variable
+	SetA, SetB []frontend.Variable
+}
+
+func (c *differenceCircuit) Define(api frontend.API) error {
+	AssertInDifference(api, c.Element, c.SetA, c.SetB)
+	return nil
+}
+
+func TestAssertInDifference(t *testing.T) {
+	assert := test.NewAssert(t)
+	setA := []frontend.Variable{2, 5, 8, 13}
+	setB := []frontend.Variable{1, 5, 9}
+	circuit := differenceCircuit{SetA: make([]frontend.Variable, len(setA)), SetB: make([]frontend.Variable, len(setB))}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&differenceCircuit{Element: 8, SetA: setA, SetB: setB}),
+		test.WithValidAssignment(&differenceCircuit{Element: 2, SetA: setA, SetB: setB}),
+		// in A and B
+		test.WithInvalidAssignment(&differenceCircuit{Element: 5, SetA: setA, SetB: setB}),
+		// in neither
+		test.WithInvalidAssignment(&differenceCircuit{Element: 7, SetA: setA, SetB: setB}),
+		// in B only
+		test.WithInvalidAssignment(&differenceCircuit{Element: 9, SetA: setA, SetB: setB}),
+		test.WithCurves(ecc.BN254))
+}
@@ -18,25 +18,35 @@ const (
 )
 
 // ToBase decomposes scalar v into digits in given base using options opts. The
-// decomposition is in little-endian order.
+// decomposition is in little-endian order, unless the [WithBigEndian] option is
+// set.
 func ToBase(api frontend.API, base Base, v frontend.Variable, opts ...BaseConversionOption) []frontend.Variable {
+	var digits []frontend.Variable
 	switch base {
 	case Binary:
-		return toBinary(api, v, opts...)
+		digits = toBinary(api, v, opts...)
 	case Ternary:
-		return toTernary(api, v, opts...)
+		digits = toTernary(api, v, opts...)
 	default:
 		panic("not implemented")
 	}
+	if isBigEndian(opts) {
+		reverse(digits)
+	}
+	return digits
 }
 
 // FromBase compute from a set of digits its canonical representation in
-// little-endian order.
+// little-endian order, unless the [WithBigEndian] option is set.
 // For example for base 2, it returns Σbi = Σ (2**i * digits[i])
 func FromBase(api frontend.API, base Base, digits []frontend.Variable, opts ...BaseConversionOption) frontend.Variable {
 	if len(digits) == 0 {
 		panic("FromBase needs at least 1 digit")
 	}
+	if isBigEndian(opts) {
+		digits = append([]frontend.Variable(nil), digits...)
+		reverse(digits)
+	}
 	switch base {
 	case Binary:
 		return fromBinary(api, digits, opts...)
@@ -53,6 +63,7 @@ type baseConversionConfig struct {
 	UnconstrainedInputs  bool
 
 	omitModulusCheck bool
+	bigEndian        bool
 }
 
 // BaseConversionOption configures the behaviour of scalar decomposition.
@@ -118,3 +129,34 @@ func OmitModulusCheck() BaseConversionOption {
 		return nil
 	}
 }
+
+// WithBigEndian sets the order of the digits to big-endian: the most
+// significant digit comes first. It applies to both the decomposition and the
+// recomposition. If not set, the digits are in little-endian order, the least
+// significant digit first.
+//
+// With [WithNbDigits] larger than the bit-length of the modulus, the padding
+// zero digits come first.
+func WithBigEndian() BaseConversionOption {
+	return func(opt *baseConversionConfig) error {
+		opt.bigEndian = true
+		return nil
+	}
+}
+
+// isBigEndian returns true if the options set [WithBigEndian].
+func isBigEndian(opts []BaseConversionOption) bool {
+	var cfg baseConversionConfig
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			panic(err)
+		}
+	}
+	return cfg.bigEndian
+}
+
+func reverse(digits []frontend.Variable) {
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+}
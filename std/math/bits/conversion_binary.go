@@ -1,12 +1,16 @@
 package bits
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/consensys/gnark/frontend"
 )
 
-// ToBinary is an alias of ToBase(api, Binary, v, opts)
+// ToBinary is an alias of ToBase(api, Binary, v, opts). Unless
+// [WithUnconstrainedOutputs] is set, the bits are constrained and marked to be
+// boolean, so that asserting it again adds no constraint. A constant is
+// decomposed into constant bits, without constraint.
 func ToBinary(api frontend.API, v frontend.Variable, opts ...BaseConversionOption) []frontend.Variable {
 	return ToBase(api, Binary, v, opts...)
 }
@@ -61,6 +65,19 @@ func toBinary(api frontend.API, v frontend.Variable, opts ...BaseConversionOptio
 	// to decompose to is less than the modulus or it was strictly requested.
 	omitReducednessCheck := cfg.omitModulusCheck || cfg.NbDigits < api.Compiler().FieldBitLen()
 
+	// a constant is decomposed directly, which adds neither hint nor
+	// constraint. Its bits are constants, known to be boolean.
+	if c, ok := api.Compiler().ConstantValue(v); ok {
+		if c.BitLen() > cfg.NbDigits {
+			panic(fmt.Sprintf("constant %s does not fit in %d bits", c, cfg.NbDigits))
+		}
+		bits := make([]frontend.Variable, cfg.NbDigits)
+		for i := range bits {
+			bits[i] = c.Bit(i)
+		}
+		return bits
+	}
+
 	// when cfg.NbDigits == 1, v itself has to be a binary digit. This if clause
 	// saves one constraint.
 	if cfg.NbDigits == 1 {
@@ -1,9 +1,13 @@
 package bits_test
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/gnark/std/math/bits"
 	"github.com/consensys/gnark/test"
 )
@@ -64,3 +68,113 @@ func TestToTernary(t *testing.T) {
 	assert := test.NewAssert(t)
 	assert.CheckCircuit(&toTernaryCircuit{}, test.WithValidAssignment(&toTernaryCircuit{A: 5, T0: 2, T1: 1, T2: 0}))
 }
+
+type endiannessCircuit struct {
+	A          frontend.Variable
+	B0, B1, B2 frontend.Variable
+}
+
+func (c *endiannessCircuit) Define(api frontend.API) error {
+	// little-endian by default
+	le := bits.ToBinary(api, c.A, bits.WithNbDigits(3))
+	api.AssertIsEqual(le[0], c.B0)
+	api.AssertIsEqual(le[2], c.B2)
+	api.AssertIsEqual(bits.FromBinary(api, []frontend.Variable{c.B0, c.B1, c.B2}), c.A)
+
+	// big-endian
+	be := bits.ToBinary(api, c.A, bits.WithNbDigits(3), bits.WithBigEndian())
+	api.AssertIsEqual(be[0], c.B2)
+	api.AssertIsEqual(be[2], c.B0)
+	beDigits := []frontend.Variable{c.B2, c.B1, c.B0}
+	api.AssertIsEqual(bits.FromBinary(api, beDigits, bits.WithBigEndian()), c.A)
+	// the input slice is not reordered
+	api.AssertIsEqual(beDigits[0], c.B2)
+
+	// padding digits come first in big-endian
+	padded := bits.ToBinary(api, c.A, bits.WithNbDigits(400), bits.WithBigEndian())
+	api.AssertIsEqual(padded[0], 0)
+	api.AssertIsEqual(padded[399], c.B0)
+
+	// ternary
+	tbe := bits.ToTernary(api, c.A, bits.WithNbDigits(3), bits.WithBigEndian())
+	api.AssertIsEqual(bits.FromTernary(api, tbe, bits.WithBigEndian()), c.A)
+	return nil
+}
+
+func TestEndianness(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	assert.CheckCircuit(&endiannessCircuit{},
+		test.WithValidAssignment(&endiannessCircuit{A: 6, B0: 0, B1: 1, B2: 1}),
+		test.WithValidAssignment(&endiannessCircuit{A: 1, B0: 1, B1: 0, B2: 0}),
+		test.WithInvalidAssignment(&endiannessCircuit{A: 4, B0: 1, B1: 0, B2: 0}),
+	)
+}
+
+type binaryMarkCircuit struct {
+	constant bool
+	X        frontend.Variable
+}
+
+func (c *binaryMarkCircuit) Define(api frontend.API) error {
+	if c.constant {
+		b := bits.ToBinary(api, 13, bits.WithNbDigits(5))
+		if len(b) != 5 {
+			return fmt.Errorf("expected 5 bits, got %d", len(b))
+		}
+		for i, expected := range []uint64{1, 0, 1, 1, 0} {
+			if v, ok := api.Compiler().ConstantValue(b[i]); !ok || v.Uint64() != expected {
+				return fmt.Errorf("bit %d of the constant is not %d", i, expected)
+			}
+		}
+		if v, ok := api.Compiler().ConstantValue(bits.FromBinary(api, b)); !ok || v.Uint64() != 13 {
+			return fmt.Errorf("recomposed constant is not 13")
+		}
+		return nil
+	}
+	b := bits.ToBinary(api, c.X, bits.WithNbDigits(8))
+	if len(b) != 8 {
+		return fmt.Errorf("expected 8 bits, got %d", len(b))
+	}
+	for i := range b {
+		if !api.Compiler().IsBoolean(b[i]) {
+			return fmt.Errorf("bit %d is not marked boolean", i)
+		}
+	}
+	api.AssertIsEqual(bits.FromBinary(api, b), c.X)
+	return nil
+}
+
+func TestToBinaryMarked(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, builder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		// FromBinary does not constrain the marked bits again
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &binaryMarkCircuit{})
+		assert.NoError(err)
+		expected, err := frontend.Compile(ecc.BN254.ScalarField(), builder, &toBinaryOnlyCircuit{})
+		assert.NoError(err)
+		assert.Equal(expected.GetNbConstraints()+1, ccs.GetNbConstraints())
+
+		// the constants are decomposed without constraints
+		ccs, err = frontend.Compile(ecc.BN254.ScalarField(), builder, &binaryMarkCircuit{constant: true})
+		assert.NoError(err)
+		expected, err = frontend.Compile(ecc.BN254.ScalarField(), builder, &toBinaryOnlyCircuit{constant: true})
+		assert.NoError(err)
+		assert.Equal(expected.GetNbConstraints(), ccs.GetNbConstraints())
+	}
+	assert.CheckCircuit(&binaryMarkCircuit{},
+		test.WithValidAssignment(&binaryMarkCircuit{X: 200}),
+		test.WithInvalidAssignment(&binaryMarkCircuit{X: 256}))
+}
+
+type toBinaryOnlyCircuit struct {
+	constant bool
+	X        frontend.Variable
+}
+
+func (c *toBinaryOnlyCircuit) Define(api frontend.API) error {
+	if !c.constant {
+		bits.ToBinary(api, c.X, bits.WithNbDigits(8))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Package intsqrt implements the integer square root.
+package intsqrt
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// IntSqrt returns r = floor(sqrt(a)) for a < 2^nbBits. The root is computed
+// by a hint and the method enforces
+//
+//	r² <= a < (r+1)²
+//
+// by range checking r to (nbBits+1)/2 bits, and both differences a-r² and
+// (r+1)²-1-a to one bit more. The root of a value of odd nbBits needs the
+// rounded up half: for example the root of 2^15-1 is 181, of 8 bits. As this
+// bound on r allows a up to 2^(nbBits+1)-1, a is then range checked too. If a
+// is not less than 2^nbBits, then the constraints are not satisfiable.
+func IntSqrt(api frontend.API, a frontend.Variable, nbBits int) frontend.Variable {
+	if nbBits < 1 || nbBits+4 >= api.Compiler().FieldBitLen() {
+		panic(fmt.Sprintf("invalid number of bits %d", nbBits))
+	}
+	ret, err := api.Compiler().NewHint(sqrtHint, 1, a)
+	if err != nil {
+		panic(err)
+	}
+	r := ret[0]
+
+	// r < 2^h, so both differences are at most 2r < 2^(h+1) when r is the
+	// floor of the square root. As all values are small, there is no
+	// wrap-around modulo the field and a = r² + lower holds over the integers.
+	h := (nbBits + 1) / 2
+	rSquared := api.Mul(r, r)
+	lower := api.Sub(a, rSquared)
+	upper := api.Sub(api.Add(rSquared, r, r), a)
+
+	rh := rangecheck.New(api)
+	rh.Check(r, h)
+	rh.Check(lower, h+1)
+	rh.Check(upper, h+1)
+	if nbBits%2 == 1 {
+		// (r+1)² > a only bounds a by 2^(2h) = 2^(nbBits+1)
+		rh.Check(a, nbBits)
+	}
+	return r
+}
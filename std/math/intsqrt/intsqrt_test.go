@@ -0,0 +1,60 @@
+package intsqrt
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const nbBits = 16
+
+type sqrtCircuit struct {
+	nbBits int
+	A, R   frontend.Variable
+}
+
+func (c *sqrtCircuit) Define(api frontend.API) error {
+	n := c.nbBits
+	if n == 0 {
+		n = nbBits
+	}
+	r := IntSqrt(api, c.A, n)
+	api.AssertIsEqual(r, c.R)
+	return nil
+}
+
+func TestIntSqrt(t *testing.T) {
+	assert := test.NewAssert(t)
+	var opts []test.TestingOption
+	for _, v := range []int64{0, 1, 2, 3, 4, 15, 16, 17, 99, 100, 101, 65024, 65025, 65535} {
+		r := new(big.Int).Sqrt(big.NewInt(v))
+		opts = append(opts, test.WithValidAssignment(&sqrtCircuit{A: v, R: r}))
+		opts = append(opts, test.WithInvalidAssignment(&sqrtCircuit{A: v, R: new(big.Int).Add(r, big.NewInt(1))}))
+		if r.Sign() > 0 {
+			opts = append(opts, test.WithInvalidAssignment(&sqrtCircuit{A: v, R: new(big.Int).Sub(r, big.NewInt(1))}))
+		}
+	}
+	// input out of range
+	opts = append(opts, test.WithInvalidAssignment(&sqrtCircuit{A: 1 << nbBits, R: 1 << (nbBits / 2)}))
+	opts = append(opts, test.WithCurves(ecc.BN254))
+	assert.CheckCircuit(&sqrtCircuit{}, opts...)
+}
+
+func TestIntSqrtOddBits(t *testing.T) {
+	// the root of a 15 bits value has up to 8 bits
+	assert := test.NewAssert(t)
+	const n = 15
+	var opts []test.TestingOption
+	for _, v := range []int64{0, 1, 1<<14 - 1, 1 << 14, 32761, 1<<n - 1} {
+		r := new(big.Int).Sqrt(big.NewInt(v))
+		opts = append(opts, test.WithValidAssignment(&sqrtCircuit{nbBits: n, A: v, R: r}))
+		opts = append(opts, test.WithInvalidAssignment(&sqrtCircuit{nbBits: n, A: v, R: new(big.Int).Add(r, big.NewInt(1))}))
+	}
+	// input out of range
+	opts = append(opts, test.WithInvalidAssignment(&sqrtCircuit{nbBits: n, A: 1 << n, R: 181}))
+	opts = append(opts, test.WithCurves(ecc.BN254))
+	assert.CheckCircuit(&sqrtCircuit{nbBits: n}, opts...)
+}
@@ -0,0 +1,37 @@
+package modular
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in this package
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		mulModHint,
+	}
+}
+
+// mulModHint returns the quotient and the remainder of the division of
+// inputs[0]*inputs[1] by inputs[2].
+func mulModHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 3 {
+		return fmt.Errorf("expecting three inputs")
+	}
+	if len(outputs) != 2 {
+		return fmt.Errorf("expecting two outputs")
+	}
+	if inputs[2].Sign() == 0 {
+		return errors.New("modulus is zero")
+	}
+	ab := new(big.Int).Mul(inputs[0], inputs[1])
+	outputs[0].QuoRem(ab, inputs[2], outputs[1])
+	return nil
+}
@@ -0,0 +1,46 @@
+// Package modular implements modular arithmetic over native field elements
+// with a modulus which is not known at circuit compile time.
+package modular
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// MulModSecret returns a*b mod m, where m may be a secret input. The inputs a,
+// b and m are asserted to fit in nbBits bits and m to be non-zero.
+//
+// The result r is computed through a hint together with the quotient q, and
+// the method constrains
+//
+//	a*b == q*m + r, 0 <= r < m.
+//
+// For this relation to hold over the integers, nbBits must be small enough so
+// that q*m + r does not overflow the native field, i.e. 3*nbBits+1 must be
+// smaller than the bit length of the native field. The method panics
+// otherwise.
+func MulModSecret(api frontend.API, a, b, m frontend.Variable, nbBits int) frontend.Variable {
+	if nbBits < 1 || 3*nbBits+1 >= api.Compiler().FieldBitLen() {
+		panic(fmt.Sprintf("invalid number of bits %d", nbBits))
+	}
+	rchecker := rangecheck.New(api)
+	rchecker.Check(a, nbBits)
+	rchecker.Check(b, nbBits)
+	rchecker.Check(m, nbBits)
+	api.AssertIsDifferent(m, 0)
+
+	res, err := api.Compiler().NewHint(mulModHint, 2, a, b, m)
+	if err != nil {
+		panic(err)
+	}
+	q, r := res[0], res[1]
+	// a*b < 2^(2*nbBits), so q < 2^(2*nbBits).
+	rchecker.Check(q, 2*nbBits)
+	// r < m <==> m - r - 1 >= 0. As r >= 0, m - r - 1 < 2^nbBits.
+	rchecker.Check(r, nbBits)
+	rchecker.Check(api.Sub(m, r, 1), nbBits)
+	api.AssertIsEqual(api.Mul(a, b), api.Add(api.Mul(q, m), r))
+	return r
+}
@@ -0,0 +1,53 @@
+package modular
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const nbBits = 64
+
+type mulModCircuit struct {
+	A, B, M frontend.Variable
+	R       frontend.Variable `gnark:",public"`
+}
+
+func (c *mulModCircuit) Define(api frontend.API) error {
+	r := MulModSecret(api, c.A, c.B, c.M, nbBits)
+	api.AssertIsEqual(r, c.R)
+	return nil
+}
+
+func TestMulModSecret(t *testing.T) {
+	assert := test.NewAssert(t)
+	bound := new(big.Int).Lsh(big.NewInt(1), nbBits)
+	random := func() *big.Int {
+		v, err := rand.Int(rand.Reader, bound)
+		assert.NoError(err)
+		return v
+	}
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254)}
+	for i := 0; i < 5; i++ {
+		a, b, m := random(), random(), random()
+		if i == 0 {
+			m.SetUint64(1)
+		}
+		r := new(big.Int).Mul(a, b)
+		r.Mod(r, m)
+		opts = append(opts,
+			test.WithValidAssignment(&mulModCircuit{A: a, B: b, M: m, R: r}),
+			test.WithInvalidAssignment(&mulModCircuit{A: a, B: b, M: m, R: new(big.Int).Add(r, m)}),
+			test.WithInvalidAssignment(&mulModCircuit{A: a, B: b, M: m, R: new(big.Int).Add(r, big.NewInt(1))}),
+		)
+	}
+	opts = append(opts,
+		test.WithInvalidAssignment(&mulModCircuit{A: 3, B: 5, M: 0, R: 0}),
+		test.WithInvalidAssignment(&mulModCircuit{A: 3, B: 5, M: bound, R: 15}),
+	)
+	assert.CheckCircuit(&mulModCircuit{}, opts...)
+}
@@ -0,0 +1,55 @@
+// Package ulp implements approximate equality of IEEE-754 floating-point
+// numbers in units in the last place (ULPs).
+//
+// The numbers are given by their binary encoding as integers, for example as
+// obtained with [math.Float32bits]. The distance in ULPs between two numbers is
+// the number of representable floating-point numbers between them, which
+// allows to compare the output of a circuit to a reference computed in
+// floating-point arithmetic with a tolerance relative to the magnitude of the
+// values. Negative and positive zero are equal. NaNs are not handled and
+// compare as numbers larger than infinity in absolute value.
+package ulp
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/cmp"
+)
+
+// AssertFloat32WithinULPs asserts that the float32 numbers with binary
+// encodings a and b are at most maxULPs ULPs apart. It also asserts that a and
+// b are 32 bit values.
+func AssertFloat32WithinULPs(api frontend.API, a, b frontend.Variable, maxULPs uint32) {
+	assertWithinULPs(api, a, b, 32, uint64(maxULPs))
+}
+
+// AssertFloat64WithinULPs asserts that the float64 numbers with binary
+// encodings a and b are at most maxULPs ULPs apart. It also asserts that a and
+// b are 64 bit values.
+func AssertFloat64WithinULPs(api frontend.API, a, b frontend.Variable, maxULPs uint64) {
+	assertWithinULPs(api, a, b, 64, maxULPs)
+}
+
+func assertWithinULPs(api frontend.API, a, b frontend.Variable, nbBits int, maxULPs uint64) {
+	if api.Compiler().FieldBitLen() <= nbBits+2 {
+		panic(fmt.Sprintf("native field too small for %d bit floats", nbBits))
+	}
+	// the ordered values differ by less than 2^nbBits.
+	comparator := cmp.NewBoundedComparator(api, new(big.Int).Lsh(big.NewInt(1), uint(nbBits)), false)
+	d := api.Sub(ordered(api, a, nbBits), ordered(api, b, nbBits))
+	comparator.AssertIsLessEq(d, maxULPs)
+	comparator.AssertIsLessEq(api.Neg(d), maxULPs)
+}
+
+// ordered maps the sign-magnitude encoding of a floating-point number to a
+// signed integer, such that consecutive floating-point numbers map to
+// consecutive integers.
+func ordered(api frontend.API, v frontend.Variable, nbBits int) frontend.Variable {
+	vBits := bits.ToBinary(api, v, bits.WithNbDigits(nbBits))
+	sign := vBits[nbBits-1]
+	magnitude := bits.FromBinary(api, vBits[:nbBits-1], bits.WithUnconstrainedInputs())
+	return api.Select(sign, api.Neg(magnitude), magnitude)
+}
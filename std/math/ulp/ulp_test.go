@@ -0,0 +1,65 @@
+package ulp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type float32Circuit struct {
+	A, B frontend.Variable
+}
+
+func (c *float32Circuit) Define(api frontend.API) error {
+	AssertFloat32WithinULPs(api, c.A, c.B, 1)
+	return nil
+}
+
+func float32Assignment(a, b float32) *float32Circuit {
+	return &float32Circuit{A: math.Float32bits(a), B: math.Float32bits(b)}
+}
+
+func TestFloat32WithinULPs(t *testing.T) {
+	assert := test.NewAssert(t)
+	x := float32(0.1)
+	next := math.Nextafter32(x, 1)
+	prev := math.Nextafter32(x, 0)
+	smallest := math.Nextafter32(0, 1)
+	assert.CheckCircuit(&float32Circuit{},
+		test.WithValidAssignment(float32Assignment(x, x)),
+		test.WithValidAssignment(float32Assignment(x, next)),
+		test.WithValidAssignment(float32Assignment(prev, x)),
+		test.WithValidAssignment(float32Assignment(-x, -next)),
+		test.WithValidAssignment(float32Assignment(0, float32(math.Copysign(0, -1)))),
+		test.WithValidAssignment(float32Assignment(smallest, 0)),
+		test.WithInvalidAssignment(float32Assignment(prev, next)),
+		test.WithInvalidAssignment(float32Assignment(x, -x)),
+		test.WithInvalidAssignment(float32Assignment(smallest, -smallest)),
+		test.WithInvalidAssignment(float32Assignment(x, 1000*x)),
+		test.WithInvalidAssignment(&float32Circuit{A: 1 << 32, B: 1 << 32}),
+		test.WithCurves(ecc.BN254))
+}
+
+type float64Circuit struct {
+	A, B frontend.Variable
+}
+
+func (c *float64Circuit) Define(api frontend.API) error {
+	AssertFloat64WithinULPs(api, c.A, c.B, 2)
+	return nil
+}
+
+func TestFloat64WithinULPs(t *testing.T) {
+	assert := test.NewAssert(t)
+	x := 1.5
+	next := math.Nextafter(x, 2)
+	next2 := math.Nextafter(next, 2)
+	next3 := math.Nextafter(next2, 2)
+	assert.CheckCircuit(&float64Circuit{},
+		test.WithValidAssignment(&float64Circuit{A: math.Float64bits(x), B: math.Float64bits(next2)}),
+		test.WithInvalidAssignment(&float64Circuit{A: math.Float64bits(x), B: math.Float64bits(next3)}),
+		test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,102 @@
+package nn
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/cmp"
+)
+
+// ReLU returns max(0, x) for a signed value x. It asserts that the absolute
+// value of x is smaller than 2^nbBits. As ReLU commutes with scaling, the
+// number of fractional bits of x does not matter.
+func ReLU(api frontend.API, x frontend.Variable, nbBits int) frontend.Variable {
+	if nbBits < 1 || nbBits+2 >= api.Compiler().FieldBitLen() {
+		panic(fmt.Sprintf("invalid number of bits %d", nbBits))
+	}
+	// x + 2^nbBits is in [0, 2^(nbBits+1)) and its top bit is set iff x >= 0.
+	shifted := api.Add(x, new(big.Int).Lsh(big.NewInt(1), uint(nbBits)))
+	xBits := bits.ToBinary(api, shifted, bits.WithNbDigits(nbBits+1))
+	return api.Select(xBits[nbBits], x, 0)
+}
+
+// segmentBits is the logarithm of the number of segments per unit in the
+// piecewise-linear approximations of the activation functions.
+const segmentBits = 2
+
+// Sigmoid returns an approximation of 1/(1+e^-x) for the fixed-point value x
+// with nbFracBits fractional bits, with the same number of fractional bits. It
+// asserts that the absolute value of x is smaller than 2^(nbFracBits+nbBits).
+//
+// The function is approximated by linear interpolation between the points
+// spaced by 1/4 in [-8, 8) and by its values at -8 and 8 outside. The approximation error is
+// smaller than 0.001 plus the fixed-point rounding error of 2^-(nbFracBits-2).
+func Sigmoid(api frontend.API, x frontend.Variable, nbFracBits, nbBits int) frontend.Variable {
+	sigmoid := func(v float64) float64 { return 1 / (1 + math.Exp(-v)) }
+	return piecewiseLinear(api, x, nbFracBits, nbBits, sigmoid, -8, 8, segmentBits)
+}
+
+// Tanh returns an approximation of tanh(x) for the fixed-point value x with
+// nbFracBits fractional bits, with the same number of fractional bits. It
+// asserts that the absolute value of x is smaller than 2^(nbFracBits+nbBits).
+//
+// The function is approximated by linear interpolation between the points
+// spaced by 1/4 in [-4, 4) and by its values at -4 and 4 outside. The approximation error is
+// smaller than 0.007 plus the fixed-point rounding error of 2^-(nbFracBits-2).
+func Tanh(api frontend.API, x frontend.Variable, nbFracBits, nbBits int) frontend.Variable {
+	return piecewiseLinear(api, x, nbFracBits, nbBits, math.Tanh, -4, 4, segmentBits)
+}
+
+// piecewiseLinear approximates fn on [lo, hi) by linear interpolation between
+// the points spaced by 2^-nbSegmentBits, and by fn(lo) and fn(hi) outside. The
+// values of fn at the points are stored in lookup tables, so that the cost of
+// the approximation does not depend on the number of segments.
+func piecewiseLinear(api frontend.API, x frontend.Variable, nbFracBits, nbBits int, fn func(float64) float64, lo, hi, nbSegmentBits int) frontend.Variable {
+	if nbFracBits < nbSegmentBits || nbBits < 1 || nbFracBits+nbBits+2 >= api.Compiler().FieldBitLen() {
+		panic(fmt.Sprintf("invalid bit lengths %d and %d", nbFracBits, nbBits))
+	}
+	toFixed := func(v float64) *big.Int {
+		f := new(big.Float).SetFloat64(math.Round(v * math.Exp2(float64(nbFracBits))))
+		r, _ := f.Int(nil)
+		return r
+	}
+	// the segments are indexed by k in [lo*2^nbSegmentBits, hi*2^nbSegmentBits),
+	// the k-th segment is between k/2^nbSegmentBits and (k+1)/2^nbSegmentBits.
+	kLo, kHi := lo<<nbSegmentBits, hi<<nbSegmentBits
+	offsets, deltas := logderivlookup.New(api), logderivlookup.New(api)
+	// maxAbs bounds the absolute value of the interpolated values.
+	maxAbs := new(big.Int)
+	for k := kLo; k < kHi; k++ {
+		start := fn(float64(k) / float64(int(1)<<nbSegmentBits))
+		end := fn(float64(k+1) / float64(int(1)<<nbSegmentBits))
+		for _, v := range []*big.Int{toFixed(start), toFixed(end)} {
+			if v.CmpAbs(maxAbs) > 0 {
+				maxAbs.Abs(v)
+			}
+		}
+		// the table entries must be reduced.
+		offsets.Insert(new(big.Int).Mod(toFixed(start), api.Compiler().Field()))
+		deltas.Insert(new(big.Int).Mod(toFixed(end-start), api.Compiler().Field()))
+	}
+
+	// x = k * 2^(nbFracBits-nbSegmentBits) + r
+	shift := nbFracBits - nbSegmentBits
+	k := Rescale(api, x, shift, nbBits+nbSegmentBits)
+	r := api.Sub(x, api.Mul(k, new(big.Int).Lsh(big.NewInt(1), uint(shift))))
+
+	comparator := cmp.NewBoundedComparator(api, new(big.Int).Lsh(big.NewInt(1), uint(nbBits+nbSegmentBits+1)), false)
+	below := comparator.IsLess(k, kLo)
+	above := comparator.IsLess(kHi-1, k)
+	outside := api.Or(below, above)
+	idx := api.Select(outside, 0, api.Sub(k, kLo))
+	vals := append(offsets.Lookup(idx), deltas.Lookup(idx)...)
+
+	// offset + delta * r / 2^shift
+	scaled := api.Add(api.Mul(vals[0], new(big.Int).Lsh(big.NewInt(1), uint(shift))), api.Mul(vals[1], r))
+	interpolated := Rescale(api, api.Select(outside, 0, scaled), shift, maxAbs.BitLen()+1)
+	return api.Select(below, toFixed(fn(float64(lo))), api.Select(above, toFixed(fn(float64(hi))), interpolated))
+}
@@ -0,0 +1,87 @@
+package nn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type reluCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *reluCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(ReLU(api, c.X, nbBits), c.Y)
+	return nil
+}
+
+func TestReLU(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&reluCircuit{},
+		test.WithValidAssignment(&reluCircuit{X: 12345, Y: 12345}),
+		test.WithValidAssignment(&reluCircuit{X: 0, Y: 0}),
+		test.WithValidAssignment(&reluCircuit{X: -12345, Y: 0}),
+		test.WithValidAssignment(&reluCircuit{X: -(1<<nbBits - 1), Y: 0}),
+		test.WithInvalidAssignment(&reluCircuit{X: -12345, Y: -12345}),
+		test.WithInvalidAssignment(&reluCircuit{X: 12345, Y: 0}),
+		test.WithInvalidAssignment(&reluCircuit{X: 1 << nbBits, Y: 1 << nbBits}),
+		test.WithCurves(ecc.BN254))
+}
+
+type approxFunc func(api frontend.API, x frontend.Variable, nbFracBits, nbBits int) frontend.Variable
+
+// approxFuncs are the approximated functions, by name. The circuits must be
+// comparable with reflect.DeepEqual and cannot hold the functions.
+var approxFuncs = map[string]approxFunc{
+	"sigmoid":    Sigmoid,
+	"tanh":       Tanh,
+	"reciprocal": Reciprocal,
+	"exp":        Exp,
+	"log":        Log,
+}
+
+type approxCircuit struct {
+	fn        string
+	tolerance int
+	X         frontend.Variable
+	Expected  frontend.Variable `gnark:",public"`
+}
+
+func (c *approxCircuit) Define(api frontend.API) error {
+	assertClose(api, approxFuncs[c.fn](api, c.X, nbFracBits, nbBits), c.Expected, c.tolerance)
+	return nil
+}
+
+// testApproximation checks that the function named fn is within maxError of
+// reference at the points xs.
+func testApproximation(t *testing.T, fn string, reference func(float64) float64, maxError float64, xs []float64) {
+	assert := test.NewAssert(t)
+	// stated tolerance and the fixed-point rounding error
+	tolerance := int(maxError*(1<<nbFracBits)) + 1<<segmentBits + 1
+	circuit := approxCircuit{fn: fn, tolerance: tolerance}
+	opts := []test.TestingOption{test.WithCurves(ecc.BN254)}
+	for _, x := range xs {
+		opts = append(opts, test.WithValidAssignment(&approxCircuit{
+			X:        toFixed(x, nbFracBits),
+			Expected: toFixed(reference(x), nbFracBits),
+		}))
+	}
+	opts = append(opts, test.WithInvalidAssignment(&approxCircuit{
+		X:        toFixed(xs[len(xs)/2], nbFracBits),
+		Expected: toFixed(reference(xs[len(xs)/2])+0.05, nbFracBits),
+	}))
+	assert.CheckCircuit(&circuit, opts...)
+}
+
+var activationPoints = []float64{-100, -8.5, -4.1, -2.9, -1.3, -0.6, -0.01, 0, 0.2, 0.77, 1.5, 2.4, 3.99, 6.3, 8, 100}
+
+func TestSigmoid(t *testing.T) {
+	testApproximation(t, "sigmoid", func(v float64) float64 { return 1 / (1 + math.Exp(-v)) }, 0.001, activationPoints)
+}
+
+func TestTanh(t *testing.T) {
+	testApproximation(t, "tanh", math.Tanh, 0.007, activationPoints)
+}
@@ -0,0 +1,47 @@
+package nn
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in this package
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		rescaleHint,
+	}
+}
+
+// rescaleHint interprets inputs[1] as a signed integer in the field and
+// returns its floor division by 2^inputs[0] and the remainder.
+func rescaleHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 {
+		return fmt.Errorf("expecting two inputs")
+	}
+	if len(outputs) != 2 {
+		return fmt.Errorf("expecting two outputs")
+	}
+	x := signed(mod, inputs[1])
+	div := new(big.Int).Lsh(big.NewInt(1), uint(inputs[0].Uint64()))
+	// Div and Mod are the Euclidean division, the remainder is non-negative.
+	outputs[0].Div(x, div)
+	outputs[1].Mod(x, div)
+	outputs[0].Mod(outputs[0], mod)
+	return nil
+}
+
+// signed returns the signed representative of x modulo mod, in (-mod/2, mod/2].
+func signed(mod, x *big.Int) *big.Int {
+	half := new(big.Int).Rsh(mod, 1)
+	res := new(big.Int).Set(x)
+	if res.Cmp(half) > 0 {
+		res.Sub(res, mod)
+	}
+	return res
+}
@@ -0,0 +1,88 @@
+// Package nn implements gadgets for verifying neural network inference.
+//
+// The values are signed fixed-point numbers, represented as integers scaled by
+// 2^f where f is the number of fractional bits. Negative values are represented
+// as their negation in the native field. The product of two fixed-point numbers
+// with f fractional bits has 2f fractional bits, and can be brought back to f
+// fractional bits with [Rescale].
+package nn
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// ActivationFunc is the non-linear function applied to the outputs of a
+// layer.
+type ActivationFunc func(api frontend.API, x frontend.Variable) frontend.Variable
+
+// Identity is the identity activation function, for layers without
+// non-linearity.
+func Identity(_ frontend.API, x frontend.Variable) frontend.Variable {
+	return x
+}
+
+// MatVec returns the product of the matrix m, given as a slice of rows, and
+// the vector v. It panics if the dimensions do not match.
+func MatVec(api frontend.API, m [][]frontend.Variable, v []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(m))
+	for i := range m {
+		if len(m[i]) != len(v) {
+			panic(fmt.Sprintf("row %d has %d elements, expected %d", i, len(m[i]), len(v)))
+		}
+		acc := frontend.Variable(0)
+		for j := range v {
+			acc = api.Add(acc, api.Mul(m[i][j], v[j]))
+		}
+		res[i] = acc
+	}
+	return res
+}
+
+// DenseLayer returns activation(weights·input + bias) for a fully connected
+// layer, where weights is given as a slice of rows, one per output. The
+// weights and biases may be constants, in which case the multiplications are
+// free.
+//
+// If the inputs and the weights have f fractional bits, then the result of the
+// matrix-vector product has 2f fractional bits. The biases must be given with
+// 2f fractional bits and the activation function applies to values with 2f
+// fractional bits. Use [Rescale] on the outputs to get back to f fractional
+// bits, possibly inside the activation function.
+func DenseLayer(api frontend.API, input []frontend.Variable, weights [][]frontend.Variable, bias []frontend.Variable, activation ActivationFunc) []frontend.Variable {
+	if len(bias) != len(weights) {
+		panic(fmt.Sprintf("got %d biases for %d outputs", len(bias), len(weights)))
+	}
+	res := MatVec(api, weights, input)
+	for i := range res {
+		res[i] = activation(api, api.Add(res[i], bias[i]))
+	}
+	return res
+}
+
+// Rescale returns x/2^nbFracBits rounded towards negative infinity, i.e. it
+// drops the lowest nbFracBits fractional bits of the signed fixed-point value
+// x. It asserts that the absolute value of the result is smaller than
+// 2^nbBits.
+func Rescale(api frontend.API, x frontend.Variable, nbFracBits, nbBits int) frontend.Variable {
+	if nbFracBits < 0 || nbBits < 1 || nbFracBits+nbBits+2 >= api.Compiler().FieldBitLen() {
+		panic(fmt.Sprintf("invalid bit lengths %d and %d", nbFracBits, nbBits))
+	}
+	if nbFracBits == 0 {
+		return x
+	}
+	res, err := api.Compiler().NewHint(rescaleHint, 2, nbFracBits, x)
+	if err != nil {
+		panic(err)
+	}
+	q, r := res[0], res[1]
+	rchecker := rangecheck.New(api)
+	rchecker.Check(r, nbFracBits)
+	// |q| < 2^nbBits <==> 0 <= q + 2^nbBits < 2^(nbBits+1)
+	rchecker.Check(api.Add(q, new(big.Int).Lsh(big.NewInt(1), uint(nbBits))), nbBits+1)
+	api.AssertIsEqual(x, api.Add(api.Mul(q, new(big.Int).Lsh(big.NewInt(1), uint(nbFracBits))), r))
+	return q
+}
@@ -0,0 +1,123 @@
+package nn
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/cmp"
+	"github.com/consensys/gnark/test"
+)
+
+const (
+	nbFracBits = 16
+	nbBits     = 32
+)
+
+func toFixed(v float64, fracBits int) int64 {
+	return int64(math.Round(v * float64(int64(1)<<fracBits)))
+}
+
+// assertClose asserts that |a - b| <= tolerance.
+func assertClose(api frontend.API, a, b frontend.Variable, tolerance int) {
+	comparator := cmp.NewBoundedComparator(api, new(big.Int).Lsh(big.NewInt(1), nbBits+2), false)
+	d := api.Sub(a, b)
+	comparator.AssertIsLessEq(d, tolerance)
+	comparator.AssertIsLessEq(api.Neg(d), tolerance)
+}
+
+type denseCircuit struct {
+	weights  [][]float64
+	bias     []float64
+	Input    []frontend.Variable
+	Expected []frontend.Variable `gnark:",public"`
+}
+
+func (c *denseCircuit) Define(api frontend.API) error {
+	// the weights are constants of the circuit.
+	weights := make([][]frontend.Variable, len(c.weights))
+	for i := range c.weights {
+		weights[i] = make([]frontend.Variable, len(c.weights[i]))
+		for j := range c.weights[i] {
+			weights[i][j] = toFixed(c.weights[i][j], nbFracBits)
+		}
+	}
+	bias := make([]frontend.Variable, len(c.bias))
+	for i := range c.bias {
+		bias[i] = toFixed(c.bias[i], 2*nbFracBits)
+	}
+	rescale := func(api frontend.API, x frontend.Variable) frontend.Variable {
+		return Rescale(api, x, nbFracBits, nbBits)
+	}
+	out := DenseLayer(api, c.Input, weights, bias, rescale)
+	for i := range out {
+		assertClose(api, out[i], c.Expected[i], 2)
+	}
+	return nil
+}
+
+func TestDenseLayer(t *testing.T) {
+	assert := test.NewAssert(t)
+	weights := [][]float64{
+		{0.5, -1.25, 2},
+		{-0.75, 0.125, -3.5},
+	}
+	bias := []float64{0.1, -2.3}
+	input := []float64{1.7, -0.3, 0.45}
+
+	// plain reference
+	expected := make([]float64, len(weights))
+	for i := range weights {
+		expected[i] = bias[i]
+		for j := range input {
+			expected[i] += weights[i][j] * input[j]
+		}
+	}
+
+	circuit := denseCircuit{
+		weights:  weights,
+		bias:     bias,
+		Input:    make([]frontend.Variable, len(input)),
+		Expected: make([]frontend.Variable, len(expected)),
+	}
+	assignment := func(expected []float64) *denseCircuit {
+		w := &denseCircuit{
+			Input:    make([]frontend.Variable, len(input)),
+			Expected: make([]frontend.Variable, len(expected)),
+		}
+		for i := range input {
+			w.Input[i] = toFixed(input[i], nbFracBits)
+		}
+		for i := range expected {
+			w.Expected[i] = toFixed(expected[i], nbFracBits)
+		}
+		return w
+	}
+	wrong := []float64{expected[0], expected[1] + 0.01}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(assignment(expected)),
+		test.WithInvalidAssignment(assignment(wrong)),
+		test.WithCurves(ecc.BN254))
+}
+
+type rescaleCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *rescaleCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(Rescale(api, c.X, nbFracBits, nbBits), c.Y)
+	return nil
+}
+
+func TestRescale(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&rescaleCircuit{},
+		test.WithValidAssignment(&rescaleCircuit{X: 3<<nbFracBits + 5, Y: 3}),
+		test.WithValidAssignment(&rescaleCircuit{X: -(3<<nbFracBits + 5), Y: -4}),
+		test.WithValidAssignment(&rescaleCircuit{X: -(3 << nbFracBits), Y: -3}),
+		test.WithInvalidAssignment(&rescaleCircuit{X: 3<<nbFracBits + 5, Y: 4}),
+		test.WithInvalidAssignment(&rescaleCircuit{X: int64(1) << (nbFracBits + nbBits), Y: int64(1) << nbBits}),
+		test.WithCurves(ecc.BN254))
+}
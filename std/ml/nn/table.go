@@ -0,0 +1,53 @@
+package nn
+
+import (
+	"math"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// tableSegmentBits is the logarithm of the number of segments per unit in the
+// piecewise-linear approximations of the arithmetic functions. They are
+// steeper than the activation functions and use finer segments, trading a
+// larger lookup table for a smaller approximation error. The cost of the
+// lookups grows with the size of the table, but does not depend on the number
+// of evaluations once the table is built.
+const tableSegmentBits = 4
+
+// Reciprocal returns an approximation of 1/x for the fixed-point value x with
+// nbFracBits fractional bits, with the same number of fractional bits. It
+// asserts that the absolute value of x is smaller than 2^(nbFracBits+nbBits).
+//
+// The function is approximated by linear interpolation between the points
+// spaced by 1/16 in [1, 16) and by its values at 1 and 16 outside, so that the
+// input should be normalized to this domain. The approximation error is
+// smaller than 0.001 plus the fixed-point rounding error of 2^-(nbFracBits-2).
+func Reciprocal(api frontend.API, x frontend.Variable, nbFracBits, nbBits int) frontend.Variable {
+	reciprocal := func(v float64) float64 { return 1 / v }
+	return piecewiseLinear(api, x, nbFracBits, nbBits, reciprocal, 1, 16, tableSegmentBits)
+}
+
+// Exp returns an approximation of e^x for the fixed-point value x with
+// nbFracBits fractional bits, with the same number of fractional bits. It
+// asserts that the absolute value of x is smaller than 2^(nbFracBits+nbBits).
+//
+// The function is approximated by linear interpolation between the points
+// spaced by 1/16 in [-8, 2) and by its values at -8 and 2 outside. The
+// approximation error is smaller than 0.004 plus the fixed-point rounding
+// error of 2^-(nbFracBits-2).
+func Exp(api frontend.API, x frontend.Variable, nbFracBits, nbBits int) frontend.Variable {
+	return piecewiseLinear(api, x, nbFracBits, nbBits, math.Exp, -8, 2, tableSegmentBits)
+}
+
+// Log returns an approximation of the natural logarithm of the fixed-point
+// value x with nbFracBits fractional bits, with the same number of fractional
+// bits. It asserts that the absolute value of x is smaller than
+// 2^(nbFracBits+nbBits).
+//
+// The function is approximated by linear interpolation between the points
+// spaced by 1/16 in [1, 16) and by its values at 1 and 16 outside, so that the
+// input should be normalized to this domain. The approximation error is
+// smaller than 0.001 plus the fixed-point rounding error of 2^-(nbFracBits-2).
+func Log(api frontend.API, x frontend.Variable, nbFracBits, nbBits int) frontend.Variable {
+	return piecewiseLinear(api, x, nbFracBits, nbBits, math.Log, 1, 16, tableSegmentBits)
+}
@@ -0,0 +1,28 @@
+package nn
+
+import (
+	"math"
+	"testing"
+)
+
+// domainPoints returns points spread over [lo, hi), not aligned with the
+// segments of the tables.
+func domainPoints(lo, hi float64) []float64 {
+	var res []float64
+	for x := lo; x < hi; x += 0.37 {
+		res = append(res, x)
+	}
+	return res
+}
+
+func TestReciprocal(t *testing.T) {
+	testApproximation(t, "reciprocal", func(v float64) float64 { return 1 / v }, 0.001, domainPoints(1, 16))
+}
+
+func TestExp(t *testing.T) {
+	testApproximation(t, "exp", math.Exp, 0.004, domainPoints(-8, 2))
+}
+
+func TestLog(t *testing.T) {
+	testApproximation(t, "log", math.Log, 0.001, domainPoints(1, 16))
+}
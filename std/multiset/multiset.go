@@ -0,0 +1,75 @@
+// Package multiset implements multiset equality checks.
+//
+// To show that the vectors a and b are permutations of each other, we use the
+// product argument [Lipton89]: given a random challenge r, which is derived
+// from a commitment to the entries of a and b, we check
+//
+//	∏_{i} (r-a_i) == ∏_{i} (r-b_i).
+//
+// By the Schwartz-Zippel lemma, the check passes with negligible probability
+// if a and b are not permutations of each other.
+//
+// The package requires the builder to implement [frontend.Committer] in order
+// to derive the random challenge.
+//
+// [Lipton89]: https://doi.org/10.1016/0020-0190(90)90047-9
+package multiset
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/multicommit"
+)
+
+// AssertIsPermutation asserts that b is a permutation of a. It returns an error
+// if the lengths of a and b differ.
+func AssertIsPermutation(api frontend.API, a, b []frontend.Variable) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("length mismatch: %d != %d", len(a), len(b))
+	}
+	if len(a) == 0 {
+		return nil
+	}
+
+	// we only commit to the non-constant entries.
+	var toCommit []frontend.Variable
+	for _, v := range append(append([]frontend.Variable{}, a...), b...) {
+		if _, isConst := api.Compiler().ConstantValue(v); !isConst {
+			toCommit = append(toCommit, v)
+		}
+	}
+	if len(toCommit) == 0 {
+		// both vectors are constant, we can compare them directly.
+		assertIsPermutationConstant(api, a, b)
+		return nil
+	}
+
+	multicommit.WithCommitment(api, func(api frontend.API, commitment frontend.Variable) error {
+		var lp, rp frontend.Variable = 1, 1
+		for i := range a {
+			lp = api.Mul(lp, api.Sub(commitment, a[i]))
+			rp = api.Mul(rp, api.Sub(commitment, b[i]))
+		}
+		api.AssertIsEqual(lp, rp)
+		return nil
+	}, toCommit...)
+	return nil
+}
+
+func assertIsPermutationConstant(api frontend.API, a, b []frontend.Variable) {
+	toSorted := func(v []frontend.Variable) []*big.Int {
+		r := make([]*big.Int, len(v))
+		for i := range v {
+			r[i], _ = api.Compiler().ConstantValue(v[i])
+		}
+		sort.Slice(r, func(i, j int) bool { return r[i].Cmp(r[j]) < 0 })
+		return r
+	}
+	sa, sb := toSorted(a), toSorted(b)
+	for i := range sa {
+		api.AssertIsEqual(sa[i], sb[i])
+	}
+}
@@ -0,0 +1,50 @@
+package multiset
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type permutationCircuit struct {
+	A, B []frontend.Variable
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	return AssertIsPermutation(api, c.A, c.B)
+}
+
+func TestAssertIsPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := permutationCircuit{A: make([]frontend.Variable, 5), B: make([]frontend.Variable, 5)}
+
+	valid := permutationCircuit{
+		A: []frontend.Variable{1, 2, 3, 4, 4},
+		B: []frontend.Variable{4, 2, 4, 1, 3},
+	}
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&valid), test.WithCurves(ecc.BN254))
+
+	invalid := permutationCircuit{
+		A: []frontend.Variable{1, 2, 3, 4, 4},
+		B: []frontend.Variable{4, 2, 3, 1, 3},
+	}
+	assert.CheckCircuit(&circuit, test.WithInvalidAssignment(&invalid), test.WithCurves(ecc.BN254))
+}
+
+type constPermutationCircuit struct {
+	A frontend.Variable
+}
+
+func (c *constPermutationCircuit) Define(api frontend.API) error {
+	if err := AssertIsPermutation(api, []frontend.Variable{1, 2, 3}, []frontend.Variable{3, 1, 2}); err != nil {
+		return err
+	}
+	return AssertIsPermutation(api, []frontend.Variable{c.A, 2}, []frontend.Variable{2, c.A})
+}
+
+func TestAssertIsPermutationConstant(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&constPermutationCircuit{}, test.WithValidAssignment(&constPermutationCircuit{A: 5}), test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,70 @@
+// Package nullifier implements the derivation of nullifiers.
+//
+// A nullifier binds a spend (or a vote) to a secret without revealing it. It
+// is computed as
+//
+//	nullifier = MiMC(secret || index)
+//
+// and exposed as a public input of the circuit. Spending twice the same note
+// reveals the same nullifier, which the verifier can reject. The MiMC hash is
+// defined over the scalar field of the curve, see [mimc.NewMiMC].
+package nullifier
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptomimc "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// Derive returns the nullifier of the secret at the given index.
+func Derive(api frontend.API, secret, index frontend.Variable) (frontend.Variable, error) {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return nil, fmt.Errorf("new mimc: %w", err)
+	}
+	h.Write(secret, index)
+	return h.Sum(), nil
+}
+
+// AssertNullifier asserts that nullifier is the nullifier of the secret at the
+// given index.
+func AssertNullifier(api frontend.API, secret, index, nullifier frontend.Variable) error {
+	n, err := Derive(api, secret, index)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(n, nullifier)
+	return nil
+}
+
+var hashes = map[ecc.ID]cryptomimc.Hash{
+	ecc.BN254:     cryptomimc.MIMC_BN254,
+	ecc.BLS12_381: cryptomimc.MIMC_BLS12_381,
+	ecc.BLS12_377: cryptomimc.MIMC_BLS12_377,
+	ecc.BW6_761:   cryptomimc.MIMC_BW6_761,
+	ecc.BLS24_315: cryptomimc.MIMC_BLS24_315,
+	ecc.BLS24_317: cryptomimc.MIMC_BLS24_317,
+	ecc.BW6_633:   cryptomimc.MIMC_BW6_633,
+}
+
+// Compute returns the nullifier of the secret at the given index, computed
+// off-circuit over the scalar field of curve. The secret and the index are
+// reduced modulo the scalar field.
+func Compute(curve ecc.ID, secret, index *big.Int) (*big.Int, error) {
+	hf, ok := hashes[curve]
+	if !ok {
+		return nil, fmt.Errorf("no MiMC hash for curve %s", curve)
+	}
+	field := curve.ScalarField()
+	h := hf.New()
+	buf := make([]byte, h.BlockSize())
+	for _, v := range []*big.Int{secret, index} {
+		new(big.Int).Mod(v, field).FillBytes(buf)
+		h.Write(buf)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil)), nil
+}
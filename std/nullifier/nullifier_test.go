@@ -0,0 +1,45 @@
+package nullifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type nullifierCircuit struct {
+	Secret    frontend.Variable
+	Index     frontend.Variable `gnark:",public"`
+	Nullifier frontend.Variable `gnark:",public"`
+}
+
+func (c *nullifierCircuit) Define(api frontend.API) error {
+	return AssertNullifier(api, c.Secret, c.Index, c.Nullifier)
+}
+
+func TestNullifier(t *testing.T) {
+	assert := test.NewAssert(t)
+	secret, _ := new(big.Int).SetString("2a8f6b9d4e1c7035f2b1e9d8c7a6b5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8", 16)
+	index := big.NewInt(42)
+
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BW6_761} {
+		assert.Run(func(assert *test.Assert) {
+			nullifier, err := Compute(curve, secret, index)
+			assert.NoError(err)
+			otherIndex, err := Compute(curve, secret, big.NewInt(43))
+			assert.NoError(err)
+			assert.NotEqual(nullifier, otherIndex)
+
+			assert.CheckCircuit(&nullifierCircuit{},
+				test.WithValidAssignment(&nullifierCircuit{Secret: secret, Index: index, Nullifier: nullifier}),
+				test.WithInvalidAssignment(&nullifierCircuit{Secret: secret, Index: 43, Nullifier: nullifier}),
+				test.WithInvalidAssignment(&nullifierCircuit{Secret: 1, Index: index, Nullifier: nullifier}),
+				test.WithCurves(curve))
+		}, curve.String())
+	}
+
+	_, err := Compute(ecc.SECP256K1, secret, index)
+	assert.Error(err)
+}
@@ -0,0 +1,306 @@
+// Package poseidon2 implements the Poseidon2 permutation [[GKS23]].
+//
+// Poseidon2 keeps the round structure of Poseidon, full rounds applied to the
+// whole state around partial rounds applied to its first element, but uses
+// cheaper linear layers: an external matrix for the full rounds and an
+// internal matrix 1 + diag for the partial rounds. The S-box is x^d with d
+// the smallest integer such that gcd(d, p-1) = 1.
+//
+// The round keys are generated with the Grain LFSR of the reference
+// implementation, see [NewParameters], such that the default instance over
+// BN254 is the one of the reference implementation. [Parameters.Permute]
+// computes the permutation off-circuit.
+//
+// [GKS23]: https://eprint.iacr.org/2023/323
+package poseidon2
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Parameters are the parameters of a Poseidon2 instance.
+type Parameters struct {
+	// Field is the prime field of the permutation.
+	Field *big.Int
+	// Width is the size of the state, 2 or 3.
+	Width int
+	// DegreeSBox is the degree d of the S-box x^d.
+	DegreeSBox int
+	// NbFullRounds is the number of full rounds, half of them before the
+	// partial rounds.
+	NbFullRounds int
+	// NbPartialRounds is the number of partial rounds.
+	NbPartialRounds int
+	// RoundKeys are the round keys: Width keys for the full rounds, one key
+	// for the partial rounds.
+	RoundKeys [][]*big.Int
+}
+
+// DefaultParameters returns the parameters of the Poseidon2 instance with
+// width 3, 8 full rounds and 56 partial rounds over the scalar field of the
+// curve. These are the numbers of rounds of the 128-bit secure instance of the
+// paper for d = 5, they are conservative for larger degrees.
+func DefaultParameters(curve ecc.ID) (*Parameters, error) {
+	return NewParameters(curve.ScalarField(), 3, 8, 56)
+}
+
+// NewParameters returns the parameters of the Poseidon2 instance over field
+// with the given width and numbers of rounds. The round keys are the ones of
+// the reference implementation [HorizenLabs]: they are sampled by rejection
+// from the output of the Grain LFSR in self-shrinking mode, initialized with
+// the field size, the width and the numbers of rounds.
+//
+// It returns an error if the width is not 2 or 3 or if the number of full
+// rounds is not even.
+//
+// [HorizenLabs]: https://github.com/HorizenLabs/poseidon2
+func NewParameters(field *big.Int, width, nbFullRounds, nbPartialRounds int) (*Parameters, error) {
+	if width != 2 && width != 3 {
+		return nil, fmt.Errorf("unsupported width %d, must be 2 or 3", width)
+	}
+	if nbFullRounds <= 0 || nbFullRounds%2 != 0 {
+		return nil, fmt.Errorf("number of full rounds %d must be even and positive", nbFullRounds)
+	}
+	if nbPartialRounds < 0 {
+		return nil, errors.New("negative number of partial rounds")
+	}
+	d, err := degreeSBox(field)
+	if err != nil {
+		return nil, err
+	}
+	p := &Parameters{
+		Field:           new(big.Int).Set(field),
+		Width:           width,
+		DegreeSBox:      d,
+		NbFullRounds:    nbFullRounds,
+		NbPartialRounds: nbPartialRounds,
+		RoundKeys:       make([][]*big.Int, nbFullRounds+nbPartialRounds),
+	}
+
+	g := newGrain(field.BitLen(), width, nbFullRounds, nbPartialRounds)
+	for i := range p.RoundKeys {
+		n := width
+		if p.isPartial(i) {
+			n = 1
+		}
+		p.RoundKeys[i] = make([]*big.Int, n)
+		for j := range p.RoundKeys[i] {
+			p.RoundKeys[i][j] = g.element(field)
+		}
+	}
+	return p, nil
+}
+
+// grain is the Grain LFSR generating the round keys.
+type grain struct {
+	bits [80]byte
+}
+
+// newGrain returns the LFSR initialized for a prime field of n bits and an S-box
+// x^d, with the given width and numbers of rounds. The first 160 bits are
+// discarded.
+func newGrain(n, width, nbFullRounds, nbPartialRounds int) *grain {
+	var g grain
+	i := 0
+	push := func(v, size int) {
+		for b := size - 1; b >= 0; b-- {
+			g.bits[i] = byte(v>>b) & 1
+			i++
+		}
+	}
+	push(1, 2) // prime field
+	push(0, 4) // S-box x^d
+	push(n, 12)
+	push(width, 12)
+	push(nbFullRounds, 10)
+	push(nbPartialRounds, 10)
+	for ; i < len(g.bits); i++ {
+		g.bits[i] = 1
+	}
+	for j := 0; j < 160; j++ {
+		g.next()
+	}
+	return &g
+}
+
+// next shifts the register and returns the new bit.
+func (g *grain) next() byte {
+	b := g.bits[62] ^ g.bits[51] ^ g.bits[38] ^ g.bits[23] ^ g.bits[13] ^ g.bits[0]
+	copy(g.bits[:], g.bits[1:])
+	g.bits[len(g.bits)-1] = b
+	return b
+}
+
+// bit returns the next output bit in self-shrinking mode: the bits are read by
+// pairs, the second one is output if the first one is set.
+func (g *grain) bit() byte {
+	for {
+		if g.next() == 1 {
+			return g.next()
+		}
+		g.next()
+	}
+}
+
+// element returns the first integer smaller than field read from the output,
+// the most significant bit first, on field.BitLen() bits.
+func (g *grain) element(field *big.Int) *big.Int {
+	res := new(big.Int)
+	for {
+		res.SetInt64(0)
+		for i := 0; i < field.BitLen(); i++ {
+			res.Lsh(res, 1)
+			res.SetBit(res, 0, uint(g.bit()))
+		}
+		if res.Cmp(field) < 0 {
+			return res
+		}
+	}
+}
+
+// degreeSBox returns the smallest d >= 3 such that x^d is a permutation of the
+// field.
+func degreeSBox(field *big.Int) (int, error) {
+	pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+	var gcd, bd big.Int
+	for d := 3; d < 256; d += 2 {
+		bd.SetInt64(int64(d))
+		if gcd.GCD(nil, nil, &bd, pMinusOne).IsInt64() && gcd.Int64() == 1 {
+			return d, nil
+		}
+	}
+	return 0, errors.New("no S-box of small degree for the field")
+}
+
+// Permutation computes the Poseidon2 permutation in a circuit.
+type Permutation struct {
+	api    frontend.API
+	params *Parameters
+}
+
+// NewPermutation returns a Permutation with the given parameters. It returns
+// an error if the parameters are not defined over the native field.
+func NewPermutation(api frontend.API, params *Parameters) (*Permutation, error) {
+	if params.Field.Cmp(api.Compiler().Field()) != 0 {
+		return nil, errors.New("parameters are not defined over the native field")
+	}
+	if len(params.RoundKeys) != params.NbFullRounds+params.NbPartialRounds {
+		return nil, fmt.Errorf("%d round keys, expected %d", len(params.RoundKeys), params.NbFullRounds+params.NbPartialRounds)
+	}
+	return &Permutation{api: api, params: params}, nil
+}
+
+// Permute applies the permutation to the state in place. It returns an error
+// if the state does not have Width elements.
+func (h *Permutation) Permute(state []frontend.Variable) error {
+	if len(state) != h.params.Width {
+		return fmt.Errorf("state has %d elements, expected %d", len(state), h.params.Width)
+	}
+	h.externalLayer(state)
+	for r, keys := range h.params.RoundKeys {
+		if h.params.isPartial(r) {
+			state[0] = h.sbox(h.api.Add(state[0], keys[0]))
+			h.internalLayer(state)
+			continue
+		}
+		for i := range state {
+			state[i] = h.sbox(h.api.Add(state[i], keys[i]))
+		}
+		h.externalLayer(state)
+	}
+	return nil
+}
+
+// sbox returns x^d.
+func (h *Permutation) sbox(x frontend.Variable) frontend.Variable {
+	d := h.params.DegreeSBox
+	var res frontend.Variable
+	for acc := x; d > 0; d >>= 1 {
+		if d&1 == 1 {
+			if res == nil {
+				res = acc
+			} else {
+				res = h.api.Mul(res, acc)
+			}
+		}
+		if d > 1 {
+			acc = h.api.Mul(acc, acc)
+		}
+	}
+	return res
+}
+
+// externalLayer multiplies the state by circ(2, 1) or circ(2, 1, 1), which is
+// adding the sum of the state to every element.
+func (h *Permutation) externalLayer(state []frontend.Variable) {
+	sum := h.api.Add(state[0], state[1], state[2:]...)
+	for i := range state {
+		state[i] = h.api.Add(state[i], sum)
+	}
+}
+
+// internalLayer multiplies the state by [[2, 1], [1, 3]] or [[2, 1, 1], [1, 2,
+// 1], [1, 1, 3]], which is adding the sum of the state to every element, and
+// the last element once more.
+func (h *Permutation) internalLayer(state []frontend.Variable) {
+	sum := h.api.Add(state[0], state[1], state[2:]...)
+	last := len(state) - 1
+	for i := range state[:last] {
+		state[i] = h.api.Add(state[i], sum)
+	}
+	state[last] = h.api.Add(h.api.Mul(state[last], 2), sum)
+}
+
+// isPartial returns true if the round i is a partial round.
+func (p *Parameters) isPartial(i int) bool {
+	return i >= p.NbFullRounds/2 && i < p.NbFullRounds/2+p.NbPartialRounds
+}
+
+// Permute applies the permutation to the state in place, off-circuit. The
+// elements of the state are reduced modulo the field.
+func (p *Parameters) Permute(state []*big.Int) error {
+	if len(state) != p.Width {
+		return fmt.Errorf("state has %d elements, expected %d", len(state), p.Width)
+	}
+	for i := range state {
+		state[i].Mod(state[i], p.Field)
+	}
+	d := big.NewInt(int64(p.DegreeSBox))
+	sbox := func(x *big.Int) {
+		x.Exp(x, d, p.Field)
+	}
+	linear := func(internal bool) {
+		sum := new(big.Int)
+		for i := range state {
+			sum.Add(sum, state[i])
+		}
+		for i := range state {
+			if internal && i == len(state)-1 {
+				state[i].Lsh(state[i], 1)
+			}
+			state[i].Add(state[i], sum).Mod(state[i], p.Field)
+		}
+	}
+
+	// the linear layers are described in Permutation
+	linear(false)
+	for r := range p.RoundKeys {
+		if p.isPartial(r) {
+			state[0].Add(state[0], p.RoundKeys[r][0])
+			sbox(state[0])
+			linear(true)
+			continue
+		}
+		for i := range state {
+			state[i].Add(state[i], p.RoundKeys[r][i])
+			sbox(state[i])
+		}
+		linear(false)
+	}
+	return nil
+}
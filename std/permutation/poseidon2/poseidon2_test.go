@@ -0,0 +1,156 @@
+package poseidon2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// reference computes the permutation with the matrices of the paper, without
+// the optimizations of the linear layers.
+func reference(p *Parameters, in []*big.Int) []*big.Int {
+	var external, internal [][]int64
+	if p.Width == 2 {
+		external = [][]int64{{2, 1}, {1, 2}}
+		internal = [][]int64{{2, 1}, {1, 3}}
+	} else {
+		external = [][]int64{{2, 1, 1}, {1, 2, 1}, {1, 1, 2}}
+		internal = [][]int64{{2, 1, 1}, {1, 2, 1}, {1, 1, 3}}
+	}
+	state := make([]*big.Int, len(in))
+	for i := range in {
+		state[i] = new(big.Int).Mod(in[i], p.Field)
+	}
+	mul := func(m [][]int64) {
+		res := make([]*big.Int, len(state))
+		for i := range m {
+			res[i] = new(big.Int)
+			for j := range m[i] {
+				res[i].Add(res[i], new(big.Int).Mul(big.NewInt(m[i][j]), state[j]))
+			}
+			res[i].Mod(res[i], p.Field)
+		}
+		state = res
+	}
+	sbox := func(x *big.Int) {
+		x.Exp(x, big.NewInt(int64(p.DegreeSBox)), p.Field)
+	}
+
+	mul(external)
+	for r := 0; r < p.NbFullRounds+p.NbPartialRounds; r++ {
+		if r >= p.NbFullRounds/2 && r < p.NbFullRounds/2+p.NbPartialRounds {
+			state[0].Add(state[0], p.RoundKeys[r][0])
+			sbox(state[0])
+			mul(internal)
+			continue
+		}
+		for i := range state {
+			state[i].Add(state[i], p.RoundKeys[r][i])
+			sbox(state[i])
+		}
+		mul(external)
+	}
+	return state
+}
+
+type permutationCircuit struct {
+	params *Parameters
+	Input  []frontend.Variable
+	Output []frontend.Variable `gnark:",public"`
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	h, err := NewPermutation(api, c.params)
+	if err != nil {
+		return err
+	}
+	state := append([]frontend.Variable{}, c.Input...)
+	if err := h.Permute(state); err != nil {
+		return err
+	}
+	for i := range state {
+		api.AssertIsEqual(state[i], c.Output[i])
+	}
+	return nil
+}
+
+func TestPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	bn254, err := DefaultParameters(ecc.BN254)
+	assert.NoError(err)
+	assert.Equal(5, bn254.DegreeSBox)
+	// x^11 is the S-box over the scalar field of BLS12-377
+	bls12377, err := NewParameters(ecc.BLS12_377.ScalarField(), 2, 8, 24)
+	assert.NoError(err)
+	assert.Equal(11, bls12377.DegreeSBox)
+
+	for _, tc := range []struct {
+		curve  ecc.ID
+		params *Parameters
+	}{
+		{ecc.BN254, bn254},
+		{ecc.BLS12_377, bls12377},
+	} {
+		input := make([]*big.Int, tc.params.Width)
+		for i := range input {
+			input[i] = big.NewInt(int64(i))
+		}
+		expected := reference(tc.params, input)
+
+		output := make([]*big.Int, len(input))
+		for i := range input {
+			output[i] = new(big.Int).Set(input[i])
+		}
+		assert.NoError(tc.params.Permute(output))
+		assert.Equal(expected, output)
+
+		valid := permutationCircuit{Input: make([]frontend.Variable, len(input)), Output: make([]frontend.Variable, len(input))}
+		invalid := permutationCircuit{Input: make([]frontend.Variable, len(input)), Output: make([]frontend.Variable, len(input))}
+		for i := range input {
+			valid.Input[i], valid.Output[i] = input[i], output[i]
+			invalid.Input[i], invalid.Output[i] = input[i], output[i]
+		}
+		invalid.Output[0] = new(big.Int).Add(output[0], big.NewInt(1))
+
+		circuit := permutationCircuit{params: tc.params, Input: make([]frontend.Variable, len(input)), Output: make([]frontend.Variable, len(input))}
+		assert.CheckCircuit(&circuit, test.WithValidAssignment(&valid), test.WithInvalidAssignment(&invalid), test.WithCurves(tc.curve))
+	}
+}
+
+func TestVector(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// test vector of the reference implementation for BN254 and width 3
+	p, err := DefaultParameters(ecc.BN254)
+	assert.NoError(err)
+	assert.Equal("1d066a255517b7fd8bddd3a93f7804ef7f8fcde48bb4c37a59a09a1a97052816", p.RoundKeys[0][0].Text(16))
+	state := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2)}
+	assert.NoError(p.Permute(state))
+	for i, expected := range []string{
+		"bb61d24daca55eebcb1929a82650f328134334da98ea4f847f760054f4a3033",
+		"303b6f7c86d043bfcbcc80214f26a30277a15d3f74ca654992defe7ff8d03570",
+		"1ed25194542b12eef8617361c3ba7c52e660b145994427cc86296242cf766ec8",
+	} {
+		assert.Equal(expected, state[i].Text(16))
+	}
+}
+
+func TestParameters(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, err := NewParameters(ecc.BN254.ScalarField(), 4, 8, 56)
+	assert.Error(err)
+	_, err = NewParameters(ecc.BN254.ScalarField(), 3, 7, 56)
+	assert.Error(err)
+
+	p, err := DefaultParameters(ecc.BN254)
+	assert.NoError(err)
+	assert.Len(p.RoundKeys, 64)
+	assert.Len(p.RoundKeys[3], 3)
+	assert.Len(p.RoundKeys[4], 1)
+	assert.Len(p.RoundKeys[60], 3)
+	assert.Error(p.Permute([]*big.Int{big.NewInt(1)}))
+}
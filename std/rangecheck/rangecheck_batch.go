@@ -0,0 +1,32 @@
+package rangecheck
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// AssertAllInRange asserts that 0 <= v < 2^nbBits for every value v of vs.
+//
+// The checks are deferred to the range checker returned by [New], which
+// aggregates all the values checked in the circuit into a single
+// log-derivative argument when the builder supports commitments. Every value
+// is then decomposed into limbs of a common width w, each limb is looked up in
+// a table of the 2^w values smaller than 2^w, and the table is paid once for
+// all the values. The cost per value is about nbBits/w limbs instead of nbBits
+// booleanity constraints for a binary decomposition. For a thousand 64-bit
+// values, this amounts to about a seventh of the constraints of independent
+// binary decompositions with R1CS. Lacking commitments, the values are decomposed
+// into bits independently.
+//
+// Packing the values and decomposing their combination would be cheaper, but
+// is not sound: an overflowing value can be compensated by its neighbour.
+func AssertAllInRange(api frontend.API, vs []frontend.Variable, nbBits int) {
+	if nbBits <= 0 {
+		panic(fmt.Sprintf("invalid number of bits %d", nbBits))
+	}
+	r := New(api)
+	for i := range vs {
+		r.Check(vs[i], nbBits)
+	}
+}
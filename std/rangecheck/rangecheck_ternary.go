@@ -0,0 +1,32 @@
+package rangecheck
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// CheckTernary asserts that 0 <= v < 3^nbTrits and returns the little-endian
+// ternary decomposition of v. Every trit is constrained to be 0, 1 or 2 and
+// their recomposition to be v. It panics if 3^nbTrits exceeds the modulus of
+// the scalar field, as the recomposition could then wrap around.
+//
+// A trit costs more constraints than the 1.58 bits it carries (see
+// [bits.AssertIsTrit]), so [New] is cheaper to bound a value by a power of two.
+// Ternary checking pays off when the bound is a power of three, which a binary
+// decomposition can only enforce with an additional comparison, or when the
+// circuit consumes the trits anyway, for example in hash functions or
+// arithmetic defined over base-3 digits: the decomposition then serves both as
+// the range check and as the input of the computation.
+func CheckTernary(api frontend.API, v frontend.Variable, nbTrits int) []frontend.Variable {
+	if nbTrits <= 0 {
+		panic("nbTrits must be positive")
+	}
+	bound := new(big.Int).Exp(big.NewInt(3), big.NewInt(int64(nbTrits)), nil)
+	if bound.Cmp(api.Compiler().Field()) > 0 {
+		panic(fmt.Sprintf("3^%d exceeds the scalar field modulus", nbTrits))
+	}
+	return bits.ToTernary(api, v, bits.WithNbDigits(nbTrits))
+}
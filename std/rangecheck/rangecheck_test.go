@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/math/bits"
 	"github.com/consensys/gnark/test"
 )
 
@@ -44,3 +46,86 @@ func TestCheck(t *testing.T) {
 	_, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit, frontend.WithCompressThreshold(100))
 	assert.NoError(err)
 }
+
+type ternaryCircuit struct {
+	V       frontend.Variable
+	nbTrits int
+}
+
+func (c *ternaryCircuit) Define(api frontend.API) error {
+	CheckTernary(api, c.V, c.nbTrits)
+	return nil
+}
+
+func TestCheckTernary(t *testing.T) {
+	assert := test.NewAssert(t)
+	// 3^4 = 81
+	assert.CheckCircuit(&ternaryCircuit{nbTrits: 4},
+		test.WithValidAssignment(&ternaryCircuit{V: 0}),
+		test.WithValidAssignment(&ternaryCircuit{V: 42}),
+		test.WithValidAssignment(&ternaryCircuit{V: 80}),
+		test.WithInvalidAssignment(&ternaryCircuit{V: 81}),
+		test.WithInvalidAssignment(&ternaryCircuit{V: 1 << 20}),
+		test.WithInvalidAssignment(&ternaryCircuit{V: -1}),
+		test.WithCurves(ecc.BN254))
+
+	// 3^161 is larger than the BN254 scalar field modulus
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &ternaryCircuit{nbTrits: 161})
+	assert.Error(err)
+}
+
+type allInRangeCircuit struct {
+	Vals []frontend.Variable
+	bits int
+}
+
+func (c *allInRangeCircuit) Define(api frontend.API) error {
+	AssertAllInRange(api, c.Vals, c.bits)
+	return nil
+}
+
+func TestAssertAllInRange(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := allInRangeCircuit{Vals: make([]frontend.Variable, 4), bits: 16}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&allInRangeCircuit{Vals: []frontend.Variable{0, 1, 12345, 1<<16 - 1}}),
+		test.WithInvalidAssignment(&allInRangeCircuit{Vals: []frontend.Variable{0, 1, 1 << 16, 1<<16 - 1}}),
+		test.WithInvalidAssignment(&allInRangeCircuit{Vals: []frontend.Variable{-1, 1, 12345, 1<<16 - 1}}),
+		test.WithCurves(ecc.BN254))
+}
+
+// perValueCircuit checks the values with independent binary decompositions.
+type perValueCircuit struct {
+	Vals []frontend.Variable
+	bits int
+}
+
+func (c *perValueCircuit) Define(api frontend.API) error {
+	for i := range c.Vals {
+		bits.ToBinary(api, c.Vals[i], bits.WithNbDigits(c.bits))
+	}
+	return nil
+}
+
+func BenchmarkAssertAllInRange(b *testing.B) {
+	const nbVals, nbBits = 1000, 64
+	for _, bc := range []struct {
+		name    string
+		circuit frontend.Circuit
+	}{
+		{"batched", &allInRangeCircuit{Vals: make([]frontend.Variable, nbVals), bits: nbBits}},
+		{"per-value", &perValueCircuit{Vals: make([]frontend.Variable, nbVals), bits: nbBits}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			var ccs constraint.ConstraintSystem
+			var err error
+			for i := 0; i < b.N; i++ {
+				ccs, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, bc.circuit)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(ccs.GetNbConstraints()), "constraints")
+		})
+	}
+}
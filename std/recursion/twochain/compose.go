@@ -0,0 +1,165 @@
+package twochain
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/math/emulated"
+	"golang.org/x/sync/errgroup"
+)
+
+// Composition splits a statement into independent subcircuits over BLS12-377,
+// which are proven in parallel, and an aggregation circuit over BW6-761 which
+// verifies the proofs of all the subcircuits and asserts that their shared
+// wires match.
+//
+// The wires shared by two subcircuits are public inputs of both, declared
+// with [Composition.Share]. The aggregation circuit is [Composition.Circuit],
+// and its assignment is returned by [Composition.Prove].
+type Composition struct {
+	subs   []*Inner
+	inputs []map[string]int // index of the public inputs of the subcircuits, by name
+	shared []SharedWire
+}
+
+// SharedWire is a wire shared by two subcircuits: the public input of index
+// IndexA of the subcircuit A equals the one of index IndexB of the
+// subcircuit B.
+type SharedWire struct {
+	A, IndexA int
+	B, IndexB int
+}
+
+// Add compiles the subcircuit and adds it to the composition. It returns the
+// index of the subcircuit.
+func (c *Composition) Add(circuit frontend.Circuit, opts ...frontend.CompileOption) (int, error) {
+	schema, err := frontend.Schema(circuit)
+	if err != nil {
+		return 0, err
+	}
+	inner, err := CompileInner(circuit, opts...)
+	if err != nil {
+		return 0, err
+	}
+	inputs := make(map[string]int, len(schema.Public))
+	for _, in := range schema.Public {
+		inputs[in.Name] = in.Index
+	}
+	c.subs = append(c.subs, inner)
+	c.inputs = append(c.inputs, inputs)
+	return len(c.subs) - 1, nil
+}
+
+// Share declares that the public input inputA of the subcircuit a and the
+// public input inputB of the subcircuit b are the same wire. The inputs are
+// named by their full names, see [frontend.Schema].
+func (c *Composition) Share(a int, inputA string, b int, inputB string) error {
+	indexA, err := c.input(a, inputA)
+	if err != nil {
+		return err
+	}
+	indexB, err := c.input(b, inputB)
+	if err != nil {
+		return err
+	}
+	c.shared = append(c.shared, SharedWire{A: a, IndexA: indexA, B: b, IndexB: indexB})
+	return nil
+}
+
+func (c *Composition) input(sub int, name string) (int, error) {
+	if sub < 0 || sub >= len(c.subs) {
+		return 0, fmt.Errorf("unknown subcircuit %d", sub)
+	}
+	index, ok := c.inputs[sub][name]
+	if !ok {
+		return 0, fmt.Errorf("subcircuit %d has no public input %s", sub, name)
+	}
+	return index, nil
+}
+
+// Subcircuit returns the compiled subcircuit of index i.
+func (c *Composition) Subcircuit(i int) *Inner {
+	return c.subs[i]
+}
+
+// Circuit returns the definition of the aggregation circuit, to compile over
+// BW6-761. The verifying keys of the subcircuits are constants of the
+// circuit.
+func (c *Composition) Circuit() (*Aggregator, error) {
+	res := &Aggregator{
+		Proofs:        make([]Proof, len(c.subs)),
+		Witnesses:     make([]Witness, len(c.subs)),
+		VerifyingKeys: make([]VerifyingKey, len(c.subs)),
+		Shared:        c.shared,
+	}
+	for i, sub := range c.subs {
+		vk, err := sub.VerifyingKey()
+		if err != nil {
+			return nil, err
+		}
+		res.VerifyingKeys[i] = vk
+		res.Witnesses[i] = sub.PlaceholderWitness()
+	}
+	return res, nil
+}
+
+// Prove proves the assignments of the subcircuits in parallel, in the order
+// of [Composition.Add], and returns the assignment of the aggregation circuit.
+// The proofs are verified, but not the shared wires, which are checked by the
+// aggregation circuit.
+func (c *Composition) Prove(assignments ...frontend.Circuit) (*Aggregator, error) {
+	if len(assignments) != len(c.subs) {
+		return nil, fmt.Errorf("expected %d assignments, got %d", len(c.subs), len(assignments))
+	}
+	res := &Aggregator{
+		Proofs:    make([]Proof, len(c.subs)),
+		Witnesses: make([]Witness, len(c.subs)),
+	}
+	var g errgroup.Group
+	for i := range c.subs {
+		i := i
+		g.Go(func() error {
+			var err error
+			if res.Proofs[i], res.Witnesses[i], err = c.subs[i].Prove(assignments[i]); err != nil {
+				return fmt.Errorf("subcircuit %d: %w", i, err)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Aggregator is the aggregation circuit of a [Composition]. The public
+// witnesses of the subcircuits are the public inputs of the aggregation
+// circuit.
+type Aggregator struct {
+	Proofs        []Proof
+	Witnesses     []Witness      `gnark:",public"`
+	VerifyingKeys []VerifyingKey `gnark:"-"`
+	Shared        []SharedWire   `gnark:"-"`
+}
+
+// Define verifies the proofs of the subcircuits and asserts that their shared
+// wires match.
+func (c *Aggregator) Define(api frontend.API) error {
+	if len(c.Proofs) != len(c.VerifyingKeys) || len(c.Witnesses) != len(c.VerifyingKeys) {
+		return fmt.Errorf("expected %d proofs and witnesses", len(c.VerifyingKeys))
+	}
+	for i := range c.Proofs {
+		if err := AssertProof(api, c.VerifyingKeys[i], c.Proofs[i], c.Witnesses[i]); err != nil {
+			return fmt.Errorf("subcircuit %d: %w", i, err)
+		}
+	}
+	f, err := emulated.NewField[sw_bls12377.ScalarField](api)
+	if err != nil {
+		return err
+	}
+	for _, w := range c.Shared {
+		f.AssertIsEqual(&c.Witnesses[w.A].Public[w.IndexA], &c.Witnesses[w.B].Public[w.IndexB])
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package twochain
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// cubeCircuit proves the knowledge of the cube root X of Y.
+type cubeCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubeCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+// incCircuit proves that Z = Y + 1.
+type incCircuit struct {
+	Y frontend.Variable `gnark:",public"`
+	Z frontend.Variable `gnark:",public"`
+}
+
+func (c *incCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(c.Y, 1), c.Z)
+	return nil
+}
+
+func TestComposition(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var c Composition
+	cube, err := c.Add(&cubeCircuit{})
+	assert.NoError(err)
+	inc, err := c.Add(&incCircuit{})
+	assert.NoError(err)
+	assert.NoError(c.Share(cube, "Y", inc, "Y"))
+	assert.Error(c.Share(cube, "X", inc, "Y"), "secret inputs can not be shared")
+	assert.Error(c.Share(cube, "Y", 2, "Y"))
+
+	circuit, err := c.Circuit()
+	assert.NoError(err)
+
+	// 2^3 = 8, 8 + 1 = 9
+	assignment, err := c.Prove(&cubeCircuit{X: 2, Y: 8}, &incCircuit{Y: 8, Z: 9})
+	assert.NoError(err)
+	assert.NoError(test.IsSolved(circuit, assignment, OuterField()))
+
+	// the proofs are verified with the verifying keys of their subcircuits
+	assignment.Proofs[0], assignment.Proofs[1] = assignment.Proofs[1], assignment.Proofs[0]
+	assert.Error(test.IsSolved(circuit, assignment, OuterField()))
+
+	// both proofs are valid, but the shared wire does not match
+	assignment, err = c.Prove(&cubeCircuit{X: 2, Y: 8}, &incCircuit{Y: 9, Z: 10})
+	assert.NoError(err)
+	assert.Error(test.IsSolved(circuit, assignment, OuterField()))
+
+	// the subproofs are verified
+	_, err = c.Prove(&cubeCircuit{X: 2, Y: 9}, &incCircuit{Y: 9, Z: 10})
+	assert.Error(err)
+	_, err = c.Prove(&cubeCircuit{X: 2, Y: 8})
+	assert.Error(err)
+}
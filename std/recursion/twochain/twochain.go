@@ -0,0 +1,151 @@
+// Package twochain provides helpers to verify Groth16 proofs over the
+// BLS12-377 / BW6-761 two-chain.
+//
+// The scalar field of BW6-761 is the base field of BLS12-377, so that a
+// circuit over BW6-761 verifies a proof over BLS12-377 with native field
+// arithmetic, without field emulation. The inner circuit is compiled with
+// [CompileInner] and proven with [Inner.Prove]. The outer circuit holds a
+// [VerifyingKey], a [Proof] and a [Witness], and calls [AssertProof] in its
+// Define method. It is compiled over BW6-761, see [OuterField].
+//
+// The verification of a proof with n public inputs costs about 19k + 1k*n
+// R1CS constraints, or 80k + 2k*n PLONK constraints, in the outer circuit when
+// the verifying key is a constant of the outer circuit: a field tagged with
+// gnark:"-" and set to [Inner.VerifyingKey] in the circuit definition. When
+// the verifying key is a witness, set to [Inner.PlaceholderVerifyingKey] in
+// the circuit definition, each public input costs about twice as much. For
+// comparison, the verification of a BN254 proof in a BN254 circuit, which
+// needs field emulation, costs more than a million constraints.
+//
+// A statement decomposable into independent subcircuits can be proven with a
+// [Composition]: the subcircuits are proven in parallel and an aggregation
+// circuit verifies their proofs and the wires they share.
+//
+// For other curves and for PLONK proofs, see the generic verifiers of the
+// packages [github.com/consensys/gnark/std/recursion/groth16] and
+// [github.com/consensys/gnark/std/recursion/plonk].
+package twochain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
+)
+
+type (
+	// Proof is a Groth16 proof over BLS12-377 in a BW6-761 circuit.
+	Proof = stdgroth16.Proof[sw_bls12377.G1Affine, sw_bls12377.G2Affine]
+	// VerifyingKey is a Groth16 verifying key over BLS12-377 in a BW6-761
+	// circuit.
+	VerifyingKey = stdgroth16.VerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]
+	// Witness is the public witness of a BLS12-377 proof in a BW6-761
+	// circuit.
+	Witness = stdgroth16.Witness[sw_bls12377.ScalarField]
+)
+
+// InnerField returns the scalar field of the inner circuits, the scalar field
+// of BLS12-377.
+func InnerField() *big.Int {
+	return ecc.BLS12_377.ScalarField()
+}
+
+// OuterField returns the scalar field of the outer circuits, the scalar field
+// of BW6-761.
+func OuterField() *big.Int {
+	return ecc.BW6_761.ScalarField()
+}
+
+// Inner is an inner circuit compiled over BLS12-377, with its Groth16 keys.
+type Inner struct {
+	CCS constraint.ConstraintSystem
+	PK  groth16.ProvingKey
+	VK  groth16.VerifyingKey
+}
+
+// CompileInner compiles the circuit over BLS12-377 and runs the Groth16 setup.
+// The setup is not suitable for production, see [groth16.Setup].
+func CompileInner(circuit frontend.Circuit, opts ...frontend.CompileOption) (*Inner, error) {
+	ccs, err := frontend.Compile(InnerField(), r1cs.NewBuilder, circuit, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		return nil, fmt.Errorf("setup: %w", err)
+	}
+	return &Inner{CCS: ccs, PK: pk, VK: vk}, nil
+}
+
+// PlaceholderVerifyingKey returns the verifying key to set in the outer circuit
+// definition.
+func (in *Inner) PlaceholderVerifyingKey() VerifyingKey {
+	return stdgroth16.PlaceholderVerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](in.CCS)
+}
+
+// PlaceholderWitness returns the public witness to set in the outer circuit
+// definition.
+func (in *Inner) PlaceholderWitness() Witness {
+	return stdgroth16.PlaceholderWitness[sw_bls12377.ScalarField](in.CCS)
+}
+
+// VerifyingKey returns the verifying key to set in the outer circuit
+// assignment.
+func (in *Inner) VerifyingKey() (VerifyingKey, error) {
+	return stdgroth16.ValueOfVerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](in.VK)
+}
+
+// Prove proves the assignment of the inner circuit and returns the proof and
+// the public witness to set in the outer circuit assignment. The proof is
+// verified before it is returned.
+func (in *Inner) Prove(assignment frontend.Circuit, opts ...frontend.WitnessOption) (Proof, Witness, error) {
+	var proof Proof
+	var witness Witness
+	w, err := frontend.NewWitness(assignment, InnerField(), opts...)
+	if err != nil {
+		return proof, witness, fmt.Errorf("witness: %w", err)
+	}
+	p, err := groth16.Prove(in.CCS, in.PK, w)
+	if err != nil {
+		return proof, witness, fmt.Errorf("prove: %w", err)
+	}
+	pw, err := w.Public()
+	if err != nil {
+		return proof, witness, fmt.Errorf("public witness: %w", err)
+	}
+	if err := groth16.Verify(p, in.VK, pw); err != nil {
+		return proof, witness, fmt.Errorf("verify: %w", err)
+	}
+	if proof, err = stdgroth16.ValueOfProof[sw_bls12377.G1Affine, sw_bls12377.G2Affine](p); err != nil {
+		return proof, witness, err
+	}
+	if witness, err = stdgroth16.ValueOfWitness[sw_bls12377.ScalarField](pw); err != nil {
+		return proof, witness, err
+	}
+	return proof, witness, nil
+}
+
+// AssertProof asserts in the outer circuit that proof is a valid proof for the
+// verifying key vk and the public witness. The circuit must be compiled over
+// BW6-761.
+func AssertProof(api frontend.API, vk VerifyingKey, proof Proof, witness Witness) error {
+	if api.Compiler().Field().Cmp(OuterField()) != 0 {
+		return fmt.Errorf("the outer circuit must be compiled over the scalar field of BW6-761")
+	}
+	curve, err := algebra.GetCurve[sw_bls12377.ScalarField, sw_bls12377.G1Affine](api)
+	if err != nil {
+		return fmt.Errorf("new curve: %w", err)
+	}
+	pairing, err := algebra.GetPairing[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](api)
+	if err != nil {
+		return fmt.Errorf("new pairing: %w", err)
+	}
+	return stdgroth16.NewVerifier(curve, pairing).AssertProof(vk, proof, witness)
+}
@@ -0,0 +1,70 @@
+package twochain
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+)
+
+type innerCircuit struct {
+	P, Q frontend.Variable
+	N    frontend.Variable `gnark:",public"`
+}
+
+func (c *innerCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.P, c.Q), c.N)
+	return nil
+}
+
+type outerCircuit struct {
+	Proof        Proof
+	VerifyingKey VerifyingKey `gnark:"-"`
+	InnerWitness Witness      `gnark:",public"`
+}
+
+func (c *outerCircuit) Define(api frontend.API) error {
+	return AssertProof(api, c.VerifyingKey, c.Proof, c.InnerWitness)
+}
+
+func TestTwoChain(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	inner, err := CompileInner(&innerCircuit{})
+	assert.NoError(err)
+	proof, witness, err := inner.Prove(&innerCircuit{P: 3, Q: 5, N: 15})
+	assert.NoError(err)
+	_, _, err = inner.Prove(&innerCircuit{P: 3, Q: 5, N: 16})
+	assert.Error(err)
+
+	// the verifying key is a constant of the outer circuit
+	vk, err := inner.VerifyingKey()
+	assert.NoError(err)
+	outer := &outerCircuit{VerifyingKey: vk, InnerWitness: inner.PlaceholderWitness()}
+	assignment := &outerCircuit{Proof: proof, InnerWitness: witness}
+
+	ccs, err := frontend.Compile(OuterField(), r1cs.NewBuilder, outer)
+	assert.NoError(err)
+	pk, outerVK, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(assignment, OuterField())
+	assert.NoError(err)
+	outerProof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(outerProof, outerVK, pw))
+
+	// a proof for another public input is rejected
+	_, wrongWitness, err := inner.Prove(&innerCircuit{P: 3, Q: 7, N: 21})
+	assert.NoError(err)
+	assignment.InnerWitness = wrongWitness
+	assert.Error(test.IsSolved(outer, assignment, OuterField()))
+
+	// the outer circuit must be over BW6-761
+	_, err = frontend.Compile(InnerField(), scs.NewBuilder, outer)
+	assert.Error(err)
+}
@@ -0,0 +1,124 @@
+// Package rollup provides a gadget verifying a zk-rollup state transition.
+//
+// The state is a Merkle tree of accounts. Each leaf is the hash of the
+// account fields (index, nonce, balance, public key) and each node is the
+// hash of its two children, ordered from left to right. A transition moves
+// an amount from a sender account to a receiver account, authorized by an
+// EdDSA signature of the sender.
+package rollup
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// Account is a leaf of the state tree.
+type Account struct {
+	Index   frontend.Variable
+	Nonce   frontend.Variable
+	Balance frontend.Variable
+	PubKey  eddsa.PublicKey
+}
+
+// Transition is a single-transfer state transition from RootBefore to
+// RootAfter.
+//
+// SenderPath is the list of siblings of the sender leaf in the tree of root
+// RootBefore, from the leaf level up to the level below the root.
+// ReceiverPath is the list of siblings of the receiver leaf in the tree
+// obtained after updating the sender account. Both paths must have the same
+// length, which is the depth of the tree.
+type Transition struct {
+	RootBefore frontend.Variable
+	RootAfter  frontend.Variable
+
+	Sender   Account
+	Receiver Account
+
+	Amount    frontend.Variable
+	Signature eddsa.Signature
+
+	SenderPath   []frontend.Variable
+	ReceiverPath []frontend.Variable
+}
+
+// Verify asserts that the transition is valid:
+//   - the sender account is in the tree of root RootBefore;
+//   - the signature of the transfer by the sender is valid;
+//   - the amount and the updated balances fit in nbBalanceBits bits, so
+//     that the transfer neither creates nor destroys funds;
+//   - the receiver account is in the tree after updating the sender;
+//   - the tree after updating the receiver has root RootAfter.
+//
+// The signed message is H(sender nonce, amount, sender key, receiver key)
+// and the sender nonce is incremented, preventing replays.
+func (t *Transition) Verify(api frontend.API, curve twistededwards.Curve, h hash.FieldHasher, nbBalanceBits int) error {
+	api.AssertIsDifferent(t.Sender.Index, t.Receiver.Index)
+
+	// sender account is in the initial state
+	root := computeRoot(api, h, leafHash(h, t.Sender), t.Sender.Index, t.SenderPath)
+	api.AssertIsEqual(root, t.RootBefore)
+
+	// signature of the transfer
+	h.Reset()
+	h.Write(t.Sender.Nonce, t.Amount,
+		t.Sender.PubKey.A.X, t.Sender.PubKey.A.Y,
+		t.Receiver.PubKey.A.X, t.Receiver.PubKey.A.Y)
+	msg := h.Sum()
+	h.Reset()
+	if err := eddsa.Verify(curve, t.Signature, msg, t.Sender.PubKey, h); err != nil {
+		return err
+	}
+
+	// balance updates. As the initial balances are in range by induction,
+	// range checking the amount and the updated balances ensures there is no
+	// wrap-around modulo the field.
+	senderAfter := t.Sender
+	senderAfter.Nonce = api.Add(t.Sender.Nonce, 1)
+	senderAfter.Balance = api.Sub(t.Sender.Balance, t.Amount)
+	receiverAfter := t.Receiver
+	receiverAfter.Balance = api.Add(t.Receiver.Balance, t.Amount)
+
+	rc := rangecheck.New(api)
+	rc.Check(t.Amount, nbBalanceBits)
+	rc.Check(senderAfter.Balance, nbBalanceBits)
+	rc.Check(receiverAfter.Balance, nbBalanceBits)
+
+	// intermediate state, with the sender updated
+	root = computeRoot(api, h, leafHash(h, senderAfter), t.Sender.Index, t.SenderPath)
+	receiverRoot := computeRoot(api, h, leafHash(h, t.Receiver), t.Receiver.Index, t.ReceiverPath)
+	api.AssertIsEqual(receiverRoot, root)
+
+	// final state, with the receiver updated
+	root = computeRoot(api, h, leafHash(h, receiverAfter), t.Receiver.Index, t.ReceiverPath)
+	api.AssertIsEqual(root, t.RootAfter)
+
+	return nil
+}
+
+// leafHash returns the hash of the account fields.
+func leafHash(h hash.FieldHasher, a Account) frontend.Variable {
+	h.Reset()
+	h.Write(a.Index, a.Nonce, a.Balance, a.PubKey.A.X, a.PubKey.A.Y)
+	return h.Sum()
+}
+
+// computeRoot returns the root of the tree containing leaf at position index,
+// where path holds the siblings from the leaf level up. The index is
+// constrained to be less than 2^len(path).
+func computeRoot(api frontend.API, h hash.FieldHasher, leaf, index frontend.Variable, path []frontend.Variable) frontend.Variable {
+	bits := api.ToBinary(index, len(path))
+	node := leaf
+	for i := range path {
+		// bits[i] == 1 when the current node is a right child
+		left := api.Select(bits[i], path[i], node)
+		right := api.Select(bits[i], node, path[i])
+		h.Reset()
+		h.Write(left, right)
+		node = h.Sum()
+	}
+	return node
+}
@@ -0,0 +1,160 @@
+package rollup
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const (
+	depth         = 2
+	nbBalanceBits = 64
+)
+
+type transitionCircuit struct {
+	T Transition
+}
+
+func (c *transitionCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return c.T.Verify(api, curve, &h, nbBalanceBits)
+}
+
+type account struct {
+	index, nonce, balance uint64
+	key                   *eddsa.PrivateKey
+}
+
+func hashElements(elems ...fr.Element) fr.Element {
+	h := mimc.NewMiMC()
+	for i := range elems {
+		b := elems[i].Bytes()
+		h.Write(b[:])
+	}
+	var res fr.Element
+	res.SetBytes(h.Sum(nil))
+	return res
+}
+
+func (a *account) leaf() fr.Element {
+	var index, nonce, balance fr.Element
+	index.SetUint64(a.index)
+	nonce.SetUint64(a.nonce)
+	balance.SetUint64(a.balance)
+	return hashElements(index, nonce, balance, a.key.PublicKey.A.X, a.key.PublicKey.A.Y)
+}
+
+func (a *account) assignment() Account {
+	var res Account
+	res.Index = a.index
+	res.Nonce = a.nonce
+	res.Balance = a.balance
+	res.PubKey.Assign(tedwards.BN254, a.key.PublicKey.Bytes())
+	return res
+}
+
+// tree returns the root of the state and the sibling path of each account.
+func tree(accounts []account) (fr.Element, [][]frontend.Variable) {
+	level := make([]fr.Element, len(accounts))
+	for i := range accounts {
+		level[i] = accounts[i].leaf()
+	}
+	paths := make([][]frontend.Variable, len(accounts))
+	for d := 0; d < depth; d++ {
+		for i := range accounts {
+			pos := (i >> d) ^ 1
+			paths[i] = append(paths[i], level[pos])
+		}
+		next := make([]fr.Element, len(level)/2)
+		for i := range next {
+			next[i] = hashElements(level[2*i], level[2*i+1])
+		}
+		level = next
+	}
+	return level[0], paths
+}
+
+func transfer(t *testing.T, accounts []account, sender, receiver int, amount uint64) Transition {
+	var res Transition
+	rootBefore, paths := tree(accounts)
+	res.RootBefore = rootBefore
+	res.Sender = accounts[sender].assignment()
+	res.Receiver = accounts[receiver].assignment()
+	res.SenderPath = paths[sender]
+	res.Amount = amount
+
+	var nonce, amnt fr.Element
+	nonce.SetUint64(accounts[sender].nonce)
+	amnt.SetUint64(amount)
+	sk, rk := accounts[sender].key.PublicKey.A, accounts[receiver].key.PublicKey.A
+	msg := hashElements(nonce, amnt, sk.X, sk.Y, rk.X, rk.Y)
+	msgBytes := msg.Bytes()
+	sig, err := accounts[sender].key.Sign(msgBytes[:], mimc.NewMiMC())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Signature.Assign(tedwards.BN254, sig)
+
+	accounts[sender].nonce++
+	accounts[sender].balance -= amount
+	_, paths = tree(accounts)
+	res.ReceiverPath = paths[receiver]
+	accounts[receiver].balance += amount
+	rootAfter, _ := tree(accounts)
+	res.RootAfter = rootAfter
+	return res
+}
+
+func newAccounts(t *testing.T) []account {
+	accounts := make([]account, 1<<depth)
+	for i := range accounts {
+		key, err := eddsa.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		accounts[i] = account{index: uint64(i), nonce: uint64(i), balance: 100 * uint64(i+1), key: key}
+	}
+	return accounts
+}
+
+func TestTransition(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	valid := transfer(t, newAccounts(t), 1, 2, 42)
+
+	wrongRoot := valid
+	wrongRoot.RootAfter = valid.RootBefore
+
+	wrongAmount := valid
+	wrongAmount.Amount = 43
+
+	// sending more than the balance underflows the sender balance
+	overdraft := transfer(t, newAccounts(t), 0, 3, 101)
+
+	circuit := transitionCircuit{T: Transition{
+		SenderPath:   make([]frontend.Variable, depth),
+		ReceiverPath: make([]frontend.Variable, depth),
+	}}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&transitionCircuit{T: valid}),
+		test.WithInvalidAssignment(&transitionCircuit{T: wrongRoot}),
+		test.WithInvalidAssignment(&transitionCircuit{T: wrongAmount}),
+		test.WithInvalidAssignment(&transitionCircuit{T: overdraft}),
+		test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,119 @@
+// Package shuffle implements the verification of a Fisher-Yates shuffle
+// derived from committed randomness, for example for verifiable lotteries or
+// card games.
+//
+// The shuffle of n values swaps, for i from n-1 down to 1, the value at index i
+// with the value at index
+//
+//	j_i = (H(seed || i) mod 2^64) mod (i+1).
+//
+// The seed is the committed randomness: it is usually a public input, or the
+// opening of a public commitment. As the output is computed from the input by
+// swaps, it is a permutation of the input by construction and there is no
+// need for an additional permutation argument. Reducing 64 bits modulo i+1
+// has a negligible bias for the sizes which fit in a circuit.
+//
+// Each swap selects a value among i+1 values, so the shuffle costs O(n²)
+// constraints.
+package shuffle
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/selector"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in this package
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		divModHint,
+	}
+}
+
+// nbRandomBits is the number of bits of the digests used to derive the swap
+// indices.
+const nbRandomBits = 64
+
+// SwapIndex returns the index j_i with which the value at index i is swapped.
+// The hasher h is reset.
+func SwapIndex(api frontend.API, h hash.FieldHasher, seed frontend.Variable, i int) frontend.Variable {
+	h.Reset()
+	h.Write(seed, i)
+	// the decomposition is canonical, so that the index is uniquely defined
+	digest := bits.ToBinary(api, h.Sum())
+	r := bits.FromBinary(api, digest[:nbRandomBits], bits.WithUnconstrainedInputs())
+
+	// r == q*(i+1) + j, with q < 2^64 and j <= i, does not overflow the field.
+	res, err := api.Compiler().NewHint(divModHint, 2, r, i+1)
+	if err != nil {
+		panic(err)
+	}
+	q, j := res[0], res[1]
+	rangecheck.New(api).Check(q, nbRandomBits)
+	api.AssertIsLessOrEqual(j, i)
+	api.AssertIsEqual(r, api.Add(api.Mul(q, i+1), j))
+	return j
+}
+
+// divModHint returns the quotient and the remainder of the division of
+// inputs[0] by inputs[1].
+func divModHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 {
+		return fmt.Errorf("expecting two inputs")
+	}
+	if len(outputs) != 2 {
+		return fmt.Errorf("expecting two outputs")
+	}
+	if inputs[1].Sign() == 0 {
+		return fmt.Errorf("division by zero")
+	}
+	outputs[0].QuoRem(inputs[0], inputs[1], outputs[1])
+	return nil
+}
+
+// Shuffle returns the Fisher-Yates shuffle of input with the randomness seed.
+// The input is not modified.
+func Shuffle(api frontend.API, h hash.FieldHasher, seed frontend.Variable, input []frontend.Variable) []frontend.Variable {
+	res := append([]frontend.Variable{}, input...)
+	for i := len(res) - 1; i > 0; i-- {
+		swap(api, res, i, SwapIndex(api, h, seed, i))
+	}
+	return res
+}
+
+// AssertIsShuffle asserts that output is the Fisher-Yates shuffle of input with
+// the randomness seed. It returns an error if the lengths of input and output
+// differ.
+func AssertIsShuffle(api frontend.API, h hash.FieldHasher, seed frontend.Variable, input, output []frontend.Variable) error {
+	if len(input) != len(output) {
+		return fmt.Errorf("length mismatch: %d != %d", len(input), len(output))
+	}
+	shuffled := Shuffle(api, h, seed, input)
+	for i := range shuffled {
+		api.AssertIsEqual(shuffled[i], output[i])
+	}
+	return nil
+}
+
+// swap swaps in place values[i] and values[j], for j <= i.
+func swap(api frontend.API, values []frontend.Variable, i int, j frontend.Variable) {
+	selected := selector.Decoder(api, i+1, j)
+	vi, vj := values[i], frontend.Variable(0)
+	for k := 0; k <= i; k++ {
+		vj = api.Add(vj, api.Mul(selected[k], values[k]))
+	}
+	for k := 0; k < i; k++ {
+		values[k] = api.Add(values[k], api.Mul(selected[k], api.Sub(vi, values[k])))
+	}
+	values[i] = vj
+}
@@ -0,0 +1,72 @@
+package shuffle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const nbCards = 6
+
+type shuffleCircuit struct {
+	Seed   frontend.Variable `gnark:",public"`
+	Input  [nbCards]frontend.Variable
+	Output [nbCards]frontend.Variable `gnark:",public"`
+}
+
+func (c *shuffleCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return AssertIsShuffle(api, &h, c.Seed, c.Input[:], c.Output[:])
+}
+
+// shuffle is the reference implementation of the shuffle.
+func shuffle(seed int64, values []int64) []int64 {
+	element := func(v int64) []byte {
+		return big.NewInt(v).FillBytes(make([]byte, 32))
+	}
+	res := append([]int64{}, values...)
+	for i := len(res) - 1; i > 0; i-- {
+		h := cryptohash.MIMC_BN254.New()
+		h.Write(element(seed))
+		h.Write(element(int64(i)))
+		digest := new(big.Int).SetBytes(h.Sum(nil))
+		j := digest.Uint64() % uint64(i+1)
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
+
+func assignment(seed int64, input, output []int64) *shuffleCircuit {
+	c := shuffleCircuit{Seed: seed}
+	for i := range input {
+		c.Input[i] = input[i]
+		c.Output[i] = output[i]
+	}
+	return &c
+}
+
+func TestShuffle(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	const seed = 42
+	input := []int64{10, 11, 12, 13, 14, 15}
+	output := shuffle(seed, input)
+
+	// tamper with the last swap
+	tampered := append([]int64{}, output...)
+	tampered[0], tampered[1] = tampered[1], tampered[0]
+
+	assert.CheckCircuit(&shuffleCircuit{},
+		test.WithValidAssignment(assignment(seed, input, output)),
+		test.WithInvalidAssignment(assignment(seed, input, tampered)),
+		test.WithInvalidAssignment(assignment(seed+1, input, output)),
+		test.WithCurves(ecc.BN254))
+}
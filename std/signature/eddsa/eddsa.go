@@ -56,6 +56,32 @@ type Signature struct {
 // Verify verifies an eddsa signature using MiMC hash function
 // cf https://en.wikipedia.org/wiki/EdDSA
 func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pubKey PublicKey, hash hash.FieldHasher) error {
+	Q, err := verify(curve, sig, msg, pubKey, hash)
+	if err != nil {
+		return err
+	}
+	curve.API().AssertIsEqual(Q.X, 0)
+	curve.API().AssertIsEqual(Q.Y, 1)
+
+	return nil
+}
+
+// IsValid returns 1 if sig is a valid eddsa signature of msg for pubKey and 0
+// otherwise. Contrary to [Verify], an invalid signature does not make the
+// proof fail, which allows to verify signatures conditionally. The public key
+// must be a point on the curve.
+func IsValid(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pubKey PublicKey, hash hash.FieldHasher) (frontend.Variable, error) {
+	Q, err := verify(curve, sig, msg, pubKey, hash)
+	if err != nil {
+		return nil, err
+	}
+	api := curve.API()
+	return api.And(api.IsZero(Q.X), api.IsZero(api.Sub(Q.Y, 1))), nil
+}
+
+// verify returns [cofactor]([S]G-[H(R,A,M)]*A-R), which is the identity
+// point iff the signature is valid.
+func verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pubKey PublicKey, hash hash.FieldHasher) (twistededwards.Point, error) {
 
 	// compute H(R, A, M)
 	hash.Write(sig.R.X)
@@ -83,7 +109,7 @@ func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pu
 	if !curve.Params().Cofactor.IsUint64() {
 		err := errors.New("invalid cofactor")
 		log.Err(err).Str("cofactor", curve.Params().Cofactor.String()).Send()
-		return err
+		return twistededwards.Point{}, err
 	}
 	cofactor := curve.Params().Cofactor.Uint64()
 	switch cofactor {
@@ -95,10 +121,7 @@ func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pu
 		log.Warn().Str("cofactor", curve.Params().Cofactor.String()).Msg("curve cofactor is not implemented")
 	}
 
-	curve.API().AssertIsEqual(Q.X, 0)
-	curve.API().AssertIsEqual(Q.Y, 1)
-
-	return nil
+	return Q, nil
 }
 
 // Assign is a helper to assigned a compressed binary public key representation into its uncompressed form
@@ -0,0 +1,59 @@
+// Package schnorr provides a ZKP-circuit function to verify a Schnorr
+// signature over the twisted Edwards curve embedded in the native field.
+//
+// For a private key x and public key A = [x]G, where G is the base point of the
+// prime order subgroup of order l, a signature of the message m is (R, s) with
+//
+//	R = [k]G, e = H(R.X, R.Y, A.X, A.Y, m), s = k + e*x mod l
+//
+// for a random nonce k. The hash H is a [hash.FieldHasher] and the challenge e
+// is used as a native field element. The signature is valid if
+//
+//	[s]G = R + [e]A.
+package schnorr
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// PublicKey stores a Schnorr public key (to be used in gnark circuit)
+type PublicKey struct {
+	A twistededwards.Point
+}
+
+// Signature stores a Schnorr signature (to be used in gnark circuit)
+type Signature struct {
+	R twistededwards.Point
+	S frontend.Variable
+}
+
+// Verify verifies a Schnorr signature of the message msg with the given public
+// key. The hasher must be the one used by the signer to compute the challenge.
+// It is reset before computing the challenge, so it may have been used before.
+//
+// The method asserts that the public key and R are on the curve, but does not
+// check that they are in the prime order subgroup. S is not reduced, so the
+// signer can produce the equivalent signatures (R, S+l).
+func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pubKey PublicKey, hash hash.FieldHasher) error {
+	curve.AssertIsOnCurve(pubKey.A)
+	curve.AssertIsOnCurve(sig.R)
+
+	// compute e = H(R, A, M)
+	hash.Reset()
+	hash.Write(sig.R.X, sig.R.Y, pubKey.A.X, pubKey.A.Y, msg)
+	e := hash.Sum()
+
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+
+	// [s]G - [e]A == R
+	Q := curve.DoubleBaseScalarMul(base, curve.Neg(pubKey.A), sig.S, e)
+	api := curve.API()
+	api.AssertIsEqual(Q.X, sig.R.X)
+	api.AssertIsEqual(Q.Y, sig.R.Y)
+	return nil
+}
@@ -0,0 +1,108 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	gtwistededwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type schnorrCircuit struct {
+	PublicKey PublicKey         `gnark:",public"`
+	Signature Signature         `gnark:",public"`
+	Message   frontend.Variable `gnark:",public"`
+}
+
+func (c *schnorrCircuit) Define(api frontend.API) error {
+	curve, err := gtwistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	// the hasher is reset by Verify
+	h.Write(c.Message)
+	return Verify(curve, c.Signature, c.Message, c.PublicKey, &h)
+}
+
+// signer is a reference out-of-circuit Schnorr signer over the twisted Edwards
+// curve embedded in BN254.
+type signer struct {
+	x *big.Int
+	a twistededwards.PointAffine
+}
+
+func newSigner(t *testing.T) *signer {
+	params := twistededwards.GetEdwardsCurve()
+	x, err := rand.Int(rand.Reader, &params.Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &signer{x: x}
+	s.a.ScalarMultiplication(&params.Base, x)
+	return s
+}
+
+func challenge(r, a *twistededwards.PointAffine, msg *fr.Element) *big.Int {
+	h := mimc.NewMiMC()
+	for _, e := range []*fr.Element{&r.X, &r.Y, &a.X, &a.Y, msg} {
+		b := e.Bytes()
+		h.Write(b[:])
+	}
+	var res fr.Element
+	res.SetBytes(h.Sum(nil))
+	return res.BigInt(new(big.Int))
+}
+
+func (s *signer) sign(t *testing.T, msg *fr.Element) (twistededwards.PointAffine, *big.Int) {
+	params := twistededwards.GetEdwardsCurve()
+	k, err := rand.Int(rand.Reader, &params.Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r twistededwards.PointAffine
+	r.ScalarMultiplication(&params.Base, k)
+	e := challenge(&r, &s.a, msg)
+	sig := new(big.Int).Mul(e, s.x)
+	sig.Add(sig, k).Mod(sig, &params.Order)
+	return r, sig
+}
+
+func assignment(pub *twistededwards.PointAffine, r *twistededwards.PointAffine, s *big.Int, msg *fr.Element) *schnorrCircuit {
+	return &schnorrCircuit{
+		PublicKey: PublicKey{A: gtwistededwards.Point{X: pub.X, Y: pub.Y}},
+		Signature: Signature{R: gtwistededwards.Point{X: r.X, Y: r.Y}, S: s},
+		Message:   *msg,
+	}
+}
+
+func TestSchnorr(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	s := newSigner(t)
+	var msg, otherMsg fr.Element
+	msg.SetRandom()
+	otherMsg.SetRandom()
+	r, sig := s.sign(t, &msg)
+
+	other := newSigner(t)
+	wrongS := new(big.Int).Add(sig, big.NewInt(1))
+
+	assert.CheckCircuit(&schnorrCircuit{},
+		test.WithValidAssignment(assignment(&s.a, &r, sig, &msg)),
+		test.WithInvalidAssignment(assignment(&s.a, &r, sig, &otherMsg)),
+		test.WithInvalidAssignment(assignment(&other.a, &r, sig, &msg)),
+		test.WithInvalidAssignment(assignment(&s.a, &r, wrongS, &msg)),
+		test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,75 @@
+// Package weighted implements the verification of stake-weighted threshold
+// signatures, as used in proof-of-stake consensus.
+//
+// Every validator has an EdDSA public key and a stake. A message is accepted
+// if the summed stake of the validators which signed it exceeds a threshold.
+// The set of signers is given by a bit per validator, only the signatures of
+// the signers are checked.
+package weighted
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// Validator is a validator with its public key and stake. The stake may be a
+// public input or committed to elsewhere in the circuit.
+type Validator struct {
+	PublicKey eddsa.PublicKey
+	Stake     frontend.Variable
+}
+
+// AssertThreshold asserts that, for every i such that signed[i] == 1,
+// signatures[i] is a valid signature of msg by validators[i], and that the
+// summed stake of these validators is strictly greater than threshold. The
+// signatures of the validators with signed[i] == 0 are ignored.
+//
+// Every stake is range checked to nbStakeBits bits and the threshold to the
+// width of the sum. The public keys of the validators must be points on the
+// curve. It returns an error if the inputs have different lengths or if the
+// summed stake may not fit in the field.
+func AssertThreshold(curve twistededwards.Curve, h hash.FieldHasher, msg frontend.Variable, validators []Validator, signed []frontend.Variable, signatures []eddsa.Signature, threshold frontend.Variable, nbStakeBits int) error {
+	api := curve.API()
+	if len(validators) == 0 {
+		return fmt.Errorf("no validators")
+	}
+	if len(signed) != len(validators) || len(signatures) != len(validators) {
+		return fmt.Errorf("got %d signed bits and %d signatures for %d validators", len(signed), len(signatures), len(validators))
+	}
+	if nbStakeBits <= 0 {
+		return fmt.Errorf("number of bits must be positive, got %d", nbStakeBits)
+	}
+	// the sum of n stakes of nbStakeBits bits has at most
+	// nbStakeBits+bitlen(n) bits, it must not overflow the field.
+	nbSumBits := nbStakeBits + bits.Len(uint(len(validators)))
+	if bound := new(big.Int).Lsh(big.NewInt(1), uint(nbSumBits+1)); bound.Cmp(api.Compiler().Field()) >= 0 {
+		return fmt.Errorf("%d bits stakes are too wide for the field", nbStakeBits)
+	}
+
+	rc := rangecheck.New(api)
+	var sum frontend.Variable = 0
+	for i := range validators {
+		api.AssertIsBoolean(signed[i])
+		h.Reset()
+		valid, err := eddsa.IsValid(curve, signatures[i], msg, validators[i].PublicKey, h)
+		if err != nil {
+			return err
+		}
+		// signed[i] == 1 ==> valid == 1
+		api.AssertIsEqual(api.Mul(signed[i], api.Sub(1, valid)), 0)
+
+		rc.Check(validators[i].Stake, nbStakeBits)
+		sum = api.Add(sum, api.Mul(signed[i], validators[i].Stake))
+	}
+	// sum > threshold iff sum - threshold - 1 does not wrap around
+	rc.Check(threshold, nbSumBits)
+	rc.Check(api.Sub(sum, threshold, 1), nbSumBits)
+	return nil
+}
@@ -0,0 +1,94 @@
+package weighted
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+	cryptoeddsa "github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+const nbValidators = 4
+
+type thresholdCircuit struct {
+	Message    frontend.Variable       `gnark:",public"`
+	Validators [nbValidators]Validator `gnark:",public"`
+	Threshold  frontend.Variable       `gnark:",public"`
+	Signed     [nbValidators]frontend.Variable
+	Signatures [nbValidators]eddsa.Signature
+}
+
+func (c *thresholdCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return AssertThreshold(curve, &h, c.Message, c.Validators[:], c.Signed[:], c.Signatures[:], c.Threshold, 64)
+}
+
+func TestAssertThreshold(t *testing.T) {
+	assert := test.NewAssert(t)
+	randomness := rand.New(rand.NewSource(42)) //#nosec G404 -- test only
+
+	msg := make([]byte, 32)
+	msg[31] = 0x2a
+	stakes := [nbValidators]int64{400, 300, 200, 100}
+
+	var valid thresholdCircuit
+	valid.Message = msg
+	valid.Threshold = 666
+	for i := range stakes {
+		privKey, err := cryptoeddsa.New(tedwards.BN254, randomness)
+		assert.NoError(err)
+		sig, err := privKey.Sign(msg, hash.MIMC_BN254.New())
+		assert.NoError(err)
+		valid.Validators[i].PublicKey.Assign(tedwards.BN254, privKey.Public().Bytes())
+		valid.Validators[i].Stake = stakes[i]
+		valid.Signatures[i].Assign(tedwards.BN254, sig)
+	}
+
+	all := valid
+	all.Signed = [nbValidators]frontend.Variable{1, 1, 1, 1}
+	// the last signature is not a signature of the last validator
+	badSignature := all
+	badSignature.Signatures[3] = all.Signatures[2]
+
+	// 400 + 300 > 666, the signatures of the validators which did not sign are
+	// ignored
+	valid.Signed = [nbValidators]frontend.Variable{1, 1, 0, 0}
+	valid.Signatures[3] = valid.Signatures[0]
+
+	belowThreshold := valid
+	belowThreshold.Signed = [nbValidators]frontend.Variable{1, 0, 1, 0}
+	atThreshold := valid
+	atThreshold.Threshold = 700
+	invalidBit := valid
+	invalidBit.Signed = [nbValidators]frontend.Variable{2, 0, 0, 0}
+	// stakes are range checked so that a negative stake can not be used
+	negativeStake := valid
+	negativeStake.Validators[2].Stake = new(big.Int).Sub(ecc.BN254.ScalarField(), big.NewInt(1))
+	negativeStake.Signed = [nbValidators]frontend.Variable{1, 1, 1, 0}
+	negativeStake.Threshold = 698
+
+	assert.CheckCircuit(&thresholdCircuit{},
+		test.WithValidAssignment(&valid),
+		test.WithValidAssignment(&all),
+		test.WithInvalidAssignment(&badSignature),
+		test.WithInvalidAssignment(&belowThreshold),
+		test.WithInvalidAssignment(&atThreshold),
+		test.WithInvalidAssignment(&invalidBit),
+		test.WithInvalidAssignment(&negativeStake),
+		test.WithCurves(ecc.BN254))
+}
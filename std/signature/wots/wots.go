@@ -0,0 +1,134 @@
+// Package wots provides a ZKP-circuit function to verify a Winternitz one-time
+// signature (W-OTS), a hash-based signature believed to resist quantum
+// attacks.
+//
+// For the Winternitz parameter w = 2^LogW, the message of MsgBits bits is
+// split in l1 digits d_0...d_{l1-1} of LogW bits, the least significant first,
+// and the checksum
+//
+//	C = Σ (w-1-d_i)
+//
+// is split in l2 digits of LogW bits, which are appended to the message
+// digits, see [Params.NbChains]. For the l = l1 + l2 random private keys sk_i,
+// the public key is made of the ends of the hash chains
+//
+//	pk_i = H^(w-1)(sk_i)
+//
+// where H(x) is the hash of the single field element x with a
+// [hash.FieldHasher], and the signature is sig_i = H^(d_i)(sk_i). The
+// signature is valid if H^(w-1-d_i)(sig_i) = pk_i for every i. A private key
+// must sign a single message.
+//
+// The verification evaluates l*(w-1) hashes: a larger Winternitz parameter
+// gives shorter signatures but costs more constraints.
+package wots
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Params are the parameters of the signature scheme.
+type Params struct {
+	// LogW is the base 2 logarithm of the Winternitz parameter w, the number
+	// of bits signed by each hash chain.
+	LogW int
+	// MsgBits is the number of bits of the messages.
+	MsgBits int
+}
+
+func (p Params) check() error {
+	if p.LogW < 1 || p.LogW > 16 {
+		return fmt.Errorf("LogW must be between 1 and 16, got %d", p.LogW)
+	}
+	if p.MsgBits < 1 {
+		return fmt.Errorf("MsgBits must be positive, got %d", p.MsgBits)
+	}
+	return nil
+}
+
+// NbMsgDigits returns l1, the number of digits of the message.
+func (p Params) NbMsgDigits() int {
+	return (p.MsgBits + p.LogW - 1) / p.LogW
+}
+
+// NbChecksumDigits returns l2, the number of digits of the checksum.
+func (p Params) NbChecksumDigits() int {
+	maxChecksum := uint(p.NbMsgDigits()) * (1<<p.LogW - 1)
+	return (bits.Len(maxChecksum) + p.LogW - 1) / p.LogW
+}
+
+// NbChains returns l, the number of hash chains, which is the length of the
+// public keys and of the signatures.
+func (p Params) NbChains() int {
+	return p.NbMsgDigits() + p.NbChecksumDigits()
+}
+
+// PublicKey stores a W-OTS public key (to be used in gnark circuit)
+type PublicKey struct {
+	Chains []frontend.Variable
+}
+
+// Signature stores a W-OTS signature (to be used in gnark circuit)
+type Signature struct {
+	Chains []frontend.Variable
+}
+
+// Verify verifies the W-OTS signature of the message msg with the given public
+// key. The hasher must be the one used to build the public key and the
+// signature. The message is decomposed in params.MsgBits bits, so the method
+// asserts that msg < 2^MsgBits.
+func Verify(api frontend.API, h hash.FieldHasher, params Params, pubKey PublicKey, sig Signature, msg frontend.Variable) error {
+	if err := params.check(); err != nil {
+		return err
+	}
+	l := params.NbChains()
+	if len(pubKey.Chains) != l || len(sig.Chains) != l {
+		return fmt.Errorf("expected %d chains, got %d in the public key and %d in the signature", l, len(pubKey.Chains), len(sig.Chains))
+	}
+
+	// the digits of the message and of the checksum, as bits
+	digits := split(api.ToBinary(msg, params.MsgBits), params.LogW)
+	w := 1 << params.LogW
+	checksum := frontend.Variable(0)
+	for _, d := range digits {
+		checksum = api.Add(checksum, api.Sub(w-1, api.FromBinary(d...)))
+	}
+	digits = append(digits, split(api.ToBinary(checksum, params.NbChecksumDigits()*params.LogW), params.LogW)...)
+
+	chain := make([]frontend.Variable, w)
+	for i, d := range digits {
+		chain[0] = sig.Chains[i]
+		for j := 1; j < w; j++ {
+			h.Reset()
+			h.Write(chain[j-1])
+			chain[j] = h.Sum()
+		}
+		// H^(w-1-d)(sig_i), the bits of w-1-d are the complements of the bits
+		// of d. The last message digit may be shorter, its missing bits are
+		// zeros.
+		sel := make([]frontend.Variable, params.LogW)
+		for k := range sel {
+			sel[k] = 1
+			if k < len(d) {
+				sel[k] = api.Sub(1, d[k])
+			}
+		}
+		api.AssertIsEqual(selector.BinaryMux(api, sel, chain), pubKey.Chains[i])
+	}
+	return nil
+}
+
+// split splits the bits in digits of n bits, the last one may be shorter.
+func split(b []frontend.Variable, n int) [][]frontend.Variable {
+	res := make([][]frontend.Variable, 0, (len(b)+n-1)/n)
+	for len(b) > n {
+		res = append(res, b[:n])
+		b = b[n:]
+	}
+	return append(res, b)
+}
@@ -0,0 +1,210 @@
+package wots
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type wotsCircuit struct {
+	PublicKey PublicKey         `gnark:",public"`
+	Signature Signature         `gnark:",public"`
+	Message   frontend.Variable `gnark:",public"`
+	params    Params
+}
+
+func (c *wotsCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return Verify(api, &h, c.params, c.PublicKey, c.Signature, c.Message)
+}
+
+func newCircuit(params Params) *wotsCircuit {
+	return &wotsCircuit{
+		PublicKey: PublicKey{Chains: make([]frontend.Variable, params.NbChains())},
+		Signature: Signature{Chains: make([]frontend.Variable, params.NbChains())},
+		params:    params,
+	}
+}
+
+// refHash hashes the single field element x with MiMC, as the gadget does.
+func refHash(x *big.Int) *big.Int {
+	h := cryptohash.MIMC_BN254.New()
+	var buf [32]byte
+	x.FillBytes(buf[:])
+	h.Write(buf[:])
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+func refChain(x *big.Int, n int) *big.Int {
+	for i := 0; i < n; i++ {
+		x = refHash(x)
+	}
+	return x
+}
+
+// refDigits returns the digits of the message followed by the ones of the
+// checksum.
+func refDigits(params Params, msg uint64) []int {
+	w := 1 << params.LogW
+	var digits []int
+	checksum := 0
+	for i := 0; i < params.NbMsgDigits(); i++ {
+		d := int(msg>>(i*params.LogW)) & (w - 1)
+		digits = append(digits, d)
+		checksum += w - 1 - d
+	}
+	for i := 0; i < params.NbChecksumDigits(); i++ {
+		digits = append(digits, (checksum>>(i*params.LogW))&(w-1))
+	}
+	return digits
+}
+
+// refKey is a reference out-of-circuit W-OTS signer, with deterministic
+// private keys for the known-answer tests.
+type refKey struct {
+	params Params
+	sk, pk []*big.Int
+}
+
+func newRefKey(params Params, seed int64) *refKey {
+	k := &refKey{params: params}
+	w := 1 << params.LogW
+	for i := 0; i < params.NbChains(); i++ {
+		sk := refHash(big.NewInt(seed*1000 + int64(i)))
+		k.sk = append(k.sk, sk)
+		k.pk = append(k.pk, refChain(sk, w-1))
+	}
+	return k
+}
+
+func (k *refKey) sign(msg uint64) []*big.Int {
+	var sig []*big.Int
+	for i, d := range refDigits(k.params, msg) {
+		sig = append(sig, refChain(k.sk[i], d))
+	}
+	return sig
+}
+
+func (k *refKey) assignment(sig []*big.Int, msg uint64) *wotsCircuit {
+	a := newCircuit(k.params)
+	for i := range sig {
+		a.PublicKey.Chains[i] = k.pk[i]
+		a.Signature.Chains[i] = sig[i]
+	}
+	a.Message = msg
+	return a
+}
+
+func TestParams(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, tc := range []struct {
+		params Params
+		l1, l2 int
+	}{
+		{Params{LogW: 4, MsgBits: 256}, 64, 3}, // the usual W-OTS parameters
+		{Params{LogW: 2, MsgBits: 16}, 8, 3},
+		{Params{LogW: 3, MsgBits: 16}, 6, 2},
+		{Params{LogW: 8, MsgBits: 254}, 32, 2},
+	} {
+		assert.Equal(tc.l1, tc.params.NbMsgDigits(), tc.params)
+		assert.Equal(tc.l2, tc.params.NbChecksumDigits(), tc.params)
+		assert.Equal(tc.l1+tc.l2, tc.params.NbChains(), tc.params)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// with LogW = 3, the last message digit has a single bit
+	for _, params := range []Params{{LogW: 2, MsgBits: 16}, {LogW: 3, MsgBits: 16}} {
+		key := newRefKey(params, int64(params.LogW))
+		for _, msg := range []uint64{0, 0xffff, 0xbeef} {
+			sig := key.sign(msg)
+			assert.CheckCircuit(newCircuit(params),
+				test.WithValidAssignment(key.assignment(sig, msg)),
+				// the signature of msg is not valid for another message
+				test.WithInvalidAssignment(key.assignment(sig, msg^1)),
+				test.WithCurves(ecc.BN254))
+		}
+
+		// hashing a chain further increases a message digit, but decreases
+		// the checksum, whose chains can not be reversed
+		sig := key.sign(0x1234)
+		forged := key.sign(0x1234)
+		forged[0] = refHash(sig[0])
+		assert.CheckCircuit(newCircuit(params),
+			test.WithInvalidAssignment(key.assignment(forged, 0x1235)),
+			test.WithCurves(ecc.BN254))
+
+		// another key
+		other := newRefKey(params, 42)
+		assert.CheckCircuit(newCircuit(params),
+			test.WithInvalidAssignment(other.assignment(sig, 0x1234)),
+			test.WithCurves(ecc.BN254))
+
+		// the message must fit in MsgBits bits
+		assert.CheckCircuit(newCircuit(params),
+			test.WithInvalidAssignment(key.assignment(key.sign(0xbeef), 0x1beef)),
+			test.WithCurves(ecc.BN254))
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+
+	key := newRefKey(Params{LogW: 2, MsgBits: 16}, 2)
+	c := key.assignment(key.sign(0xbeef), 0xbeef)
+	c.Signature.Chains = c.Signature.Chains[1:]
+	assert.Error(test.IsSolved(c, c, field))
+
+	params := Params{LogW: 17, MsgBits: 16}
+	c = newCircuit(params)
+	for i := 0; i < params.NbChains(); i++ {
+		c.PublicKey.Chains[i], c.Signature.Chains[i] = 0, 0
+	}
+	c.Message = 0
+	assert.Error(test.IsSolved(c, c, field))
+}
+
+func TestVector(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// W-OTS with MiMC over BN254, w = 4 and 4-bit messages, the private keys
+	// are sk_i = i+1. The message 9 has the digits 1, 2 and the checksum 3 the
+	// digits 3, 0.
+	params := Params{LogW: 2, MsgBits: 4}
+	assert.Equal([]int{1, 2, 3, 0}, refDigits(params, 9))
+	hex := func(s string) *big.Int {
+		v, ok := new(big.Int).SetString(s, 16)
+		if !ok {
+			t.Fatalf("invalid hex %s", s)
+		}
+		return v
+	}
+	pk := []*big.Int{
+		hex("0df4c13845daf87fd11f5f5cd386628afe74387f2490c92760f48ee4a7dca689"),
+		hex("024af2c030b7aceb229c6b2127fc01718cb85965eb3bba89a26d9d2f0426f60b"),
+		hex("22f4c17b42d1140dab10d9ec17b958a20916e37e0a0a73ac0c958960ce6af5e7"),
+		hex("1ae46a9ee8d9c7146d07e63a3b2a4e204777ee46fbd270c07fbd30c2a6d7ef90"),
+	}
+	sig := []*big.Int{
+		hex("27e5458b666ef581475a9acddbc3524ca252185cae3936506e65cda9c358222b"),
+		hex("01eeda843902ac9482aa469b55e9a125d7a697ea855ce9831ab6f462cf10e844"),
+		hex("22f4c17b42d1140dab10d9ec17b958a20916e37e0a0a73ac0c958960ce6af5e7"),
+		big.NewInt(4),
+	}
+	key := &refKey{params: params, pk: pk}
+	assert.CheckCircuit(newCircuit(params),
+		test.WithValidAssignment(key.assignment(sig, 9)),
+		test.WithInvalidAssignment(key.assignment(sig, 8)),
+		test.WithCurves(ecc.BN254))
+}
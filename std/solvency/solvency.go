@@ -0,0 +1,57 @@
+// Package solvency provides a gadget for proofs of solvency.
+//
+// The balances are committed to as the leaves of a Merkle tree, built as in
+// [merkle.ComputeRoot]. The gadget proves that the sum of the committed
+// balances is at least a public liabilities value, without revealing the
+// individual balances.
+package solvency
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// AssertSolvent asserts that root is the Merkle root of the tree with leaves
+// balances and that the sum of the balances is greater than or equal to
+// liabilities.
+//
+// Every balance is range checked to nbBalanceBits bits, so that a negative
+// balance (a large field element) cannot be used to wrap the sum around the
+// field modulus. The liabilities are range checked to the width of the sum.
+// It returns an error if there are no balances or if the sum of the balances
+// may not fit in the field.
+func AssertSolvent(api frontend.API, h hash.FieldHasher, root frontend.Variable, balances []frontend.Variable, liabilities frontend.Variable, nbBalanceBits int) error {
+	if len(balances) == 0 {
+		return fmt.Errorf("no balances")
+	}
+	if nbBalanceBits <= 0 {
+		return fmt.Errorf("number of bits must be positive, got %d", nbBalanceBits)
+	}
+	// the sum of n balances of nbBalanceBits bits has at most
+	// nbBalanceBits+bitlen(n) bits. The difference with the liabilities is in
+	// ]-2^nbSumBits, 2^nbSumBits[, a negative one must not wrap around the
+	// field into the checked range.
+	nbSumBits := nbBalanceBits + bits.Len(uint(len(balances)))
+	if bound := new(big.Int).Lsh(big.NewInt(1), uint(nbSumBits+1)); bound.Cmp(api.Compiler().Field()) > 0 {
+		return fmt.Errorf("%d bits balances are too wide for the field", nbBalanceBits)
+	}
+
+	api.AssertIsEqual(merkle.ComputeRoot(api, h, balances), root)
+
+	rc := rangecheck.New(api)
+	var sum frontend.Variable = 0
+	for i := range balances {
+		rc.Check(balances[i], nbBalanceBits)
+		sum = api.Add(sum, balances[i])
+	}
+	// sum >= liabilities iff sum - liabilities does not wrap around
+	rc.Check(liabilities, nbSumBits)
+	rc.Check(api.Sub(sum, liabilities), nbSumBits)
+	return nil
+}
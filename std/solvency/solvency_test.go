@@ -0,0 +1,89 @@
+package solvency
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type solvencyCircuit struct {
+	Root        frontend.Variable `gnark:",public"`
+	Liabilities frontend.Variable `gnark:",public"`
+	Balances    []frontend.Variable
+	nbBits      int
+}
+
+func (c *solvencyCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	nbBits := c.nbBits
+	if nbBits == 0 {
+		nbBits = 32
+	}
+	return AssertSolvent(api, &h, c.Root, c.Balances, c.Liabilities, nbBits)
+}
+
+func merkleRoot(balances []*big.Int) []byte {
+	tree := merkletree.New(hash.MIMC_BN254.New())
+	nbBytes := len(ecc.BN254.ScalarField().Bytes())
+	for _, b := range balances {
+		tree.Push(b.FillBytes(make([]byte, nbBytes)))
+	}
+	return tree.Root()
+}
+
+func assignment(liabilities int64, balances ...*big.Int) *solvencyCircuit {
+	c := &solvencyCircuit{Root: merkleRoot(balances), Liabilities: liabilities}
+	for _, b := range balances {
+		c.Balances = append(c.Balances, b)
+	}
+	return c
+}
+
+func TestAssertSolvent(t *testing.T) {
+	assert := test.NewAssert(t)
+	balances := []*big.Int{big.NewInt(100), big.NewInt(0), big.NewInt(4000), big.NewInt(25)}
+	circuit := solvencyCircuit{Balances: make([]frontend.Variable, len(balances))}
+
+	// a negative balance lowers the sum, the liabilities match the wrapped sum
+	negative := new(big.Int).Sub(ecc.BN254.ScalarField(), big.NewInt(1000))
+	wrongRoot := assignment(4125, balances...)
+	wrongRoot.Root = merkleRoot([]*big.Int{big.NewInt(100), big.NewInt(0), big.NewInt(4001), big.NewInt(25)})
+
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(assignment(4125, balances...)),
+		test.WithValidAssignment(assignment(0, balances...)),
+		// insolvent
+		test.WithInvalidAssignment(assignment(4126, balances...)),
+		test.WithInvalidAssignment(assignment(3100, big.NewInt(100), big.NewInt(0), big.NewInt(4000), negative)),
+		test.WithInvalidAssignment(wrongRoot),
+		test.WithCurves(ecc.BN254))
+}
+
+func TestAssertSolventWideBalances(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	wide := func(nbBits int, liabilities *big.Int) error {
+		circuit := &solvencyCircuit{Balances: make([]frontend.Variable, 1), nbBits: nbBits}
+		w := &solvencyCircuit{Root: merkleRoot([]*big.Int{big.NewInt(0)}), Liabilities: liabilities, Balances: []frontend.Variable{0}}
+		return test.IsSolved(circuit, w, field)
+	}
+
+	// with a single balance of 252 bits, the sum has 253 bits and
+	// 0 - (2^253-1) would wrap around the BN254 modulus into the range of 253
+	// bits: the balances are too wide
+	liabilities := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 253), big.NewInt(1))
+	assert.ErrorContains(wide(252, liabilities), "too wide for the field")
+
+	// the widest balances accepted can not wrap
+	assert.NoError(wide(251, big.NewInt(0)))
+	assert.Error(wide(251, new(big.Int).Rsh(liabilities, 1)))
+}
@@ -0,0 +1,132 @@
+// Package sortedset implements gadgets operating on sorted lists of variables.
+//
+// The elements of the lists are interpreted as integers in [0, 2^nbBits) where
+// nbBits is provided by the caller. The gadgets do not work for lists with
+// elements spanning the full field, as the ordering relies on range checking
+// the differences between consecutive elements.
+package sortedset
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/multiset"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in this package
+func GetHints() []solver.Hint {
+	return []solver.Hint{sortHint}
+}
+
+// AssertIsSorted asserts that the elements of list are in [0, 2^nbBits) and
+// sorted in non-decreasing order.
+func AssertIsSorted(api frontend.API, list []frontend.Variable, nbBits int) error {
+	if err := checkNbBits(api, len(list), nbBits); err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	rc := rangecheck.New(api)
+	// if the first and the last elements are in range and all consecutive
+	// differences are non-negative, then all elements are in range. As the
+	// length of the list is small compared to the field, the sum of the
+	// differences does not overflow.
+	rc.Check(list[0], nbBits)
+	for i := 1; i < len(list); i++ {
+		rc.Check(api.Sub(list[i], list[i-1]), nbBits)
+	}
+	rc.Check(list[len(list)-1], nbBits)
+	return nil
+}
+
+// Sort returns the elements of values sorted in non-decreasing order. The
+// sorted list is computed by a hint and constrained to be a sorted
+// permutation of values, with all elements in [0, 2^nbBits).
+func Sort(api frontend.API, values []frontend.Variable, nbBits int) ([]frontend.Variable, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	sorted, err := api.Compiler().NewHint(sortHint, len(values), values...)
+	if err != nil {
+		return nil, fmt.Errorf("new hint: %w", err)
+	}
+	if err := AssertAggregatedRange(api, values, sorted, nbBits); err != nil {
+		return nil, err
+	}
+	return sorted, nil
+}
+
+// AssertAggregatedRange asserts that all the inputs are in [0, 2^nbBits) by
+// checking that sorted is a permutation of inputs, that it is sorted and that
+// its elements are in range.
+//
+// This allows to aggregate many range proofs over the inputs into a single
+// commitment to the sorted list (for example when sorted is a public input).
+// Use [Sort] when the sorted list should be computed in-circuit.
+func AssertAggregatedRange(api frontend.API, inputs, sorted []frontend.Variable, nbBits int) error {
+	if len(inputs) != len(sorted) {
+		return fmt.Errorf("length mismatch: %d inputs and %d sorted elements", len(inputs), len(sorted))
+	}
+	// the range checker defers a call to multicommit.WithCommitment. It must be
+	// initialized before the permutation argument, otherwise the deferred call
+	// happens after the multicommitter has been closed.
+	if err := AssertIsSorted(api, sorted, nbBits); err != nil {
+		return err
+	}
+	if err := multiset.AssertIsPermutation(api, inputs, sorted); err != nil {
+		return fmt.Errorf("permutation: %w", err)
+	}
+	return nil
+}
+
+// AssertIsSortedMerge asserts that merged is the sorted interleaving of the
+// lists a and b, with all elements in [0, 2^nbBits). It checks that merged is
+// sorted and that it is a permutation of the concatenation of a and b.
+//
+// The sortedness of a and b is not checked, use [AssertIsSorted] if they are
+// not already constrained to be sorted. As merged is sorted, the check passes
+// for any ordering of a and b.
+func AssertIsSortedMerge(api frontend.API, a, b, merged []frontend.Variable, nbBits int) error {
+	if len(a)+len(b) != len(merged) {
+		return fmt.Errorf("length mismatch: %d+%d inputs and %d merged elements", len(a), len(b), len(merged))
+	}
+	inputs := make([]frontend.Variable, 0, len(merged))
+	inputs = append(inputs, a...)
+	inputs = append(inputs, b...)
+	return AssertAggregatedRange(api, inputs, merged, nbBits)
+}
+
+func checkNbBits(api frontend.API, n, nbBits int) error {
+	if nbBits <= 0 {
+		return fmt.Errorf("number of bits must be positive, got %d", nbBits)
+	}
+	// we need n * 2^nbBits < p to avoid overflows when accumulating the
+	// differences between consecutive elements.
+	bound := new(big.Int).Lsh(big.NewInt(int64(n+1)), uint(nbBits))
+	if bound.Cmp(api.Compiler().Field()) >= 0 {
+		return fmt.Errorf("%d bits elements are too wide for the field", nbBits)
+	}
+	return nil
+}
+
+func sortHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != len(outputs) {
+		return fmt.Errorf("expected %d outputs, got %d", len(inputs), len(outputs))
+	}
+	sorted := make([]*big.Int, len(inputs))
+	copy(sorted, inputs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	for i := range sorted {
+		outputs[i].Set(sorted[i])
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package sortedset
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type aggregatedRangeCircuit struct {
+	Inputs []frontend.Variable
+	Sorted []frontend.Variable `gnark:",public"`
+	nbBits int
+}
+
+func (c *aggregatedRangeCircuit) Define(api frontend.API) error {
+	return AssertAggregatedRange(api, c.Inputs, c.Sorted, c.nbBits)
+}
+
+func TestAssertAggregatedRange(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := aggregatedRangeCircuit{Inputs: make([]frontend.Variable, 5), Sorted: make([]frontend.Variable, 5), nbBits: 8}
+
+	valid := aggregatedRangeCircuit{
+		Inputs: []frontend.Variable{200, 3, 17, 255, 3},
+		Sorted: []frontend.Variable{3, 3, 17, 200, 255},
+	}
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&valid), test.WithCurves(ecc.BN254))
+
+	// one element of the sorted list is tampered with
+	tampered := aggregatedRangeCircuit{
+		Inputs: []frontend.Variable{200, 3, 17, 255, 3},
+		Sorted: []frontend.Variable{3, 3, 18, 200, 255},
+	}
+	// the sorted list contains an out of range element
+	outOfRange := aggregatedRangeCircuit{
+		Inputs: []frontend.Variable{200, 3, 17, 256, 3},
+		Sorted: []frontend.Variable{3, 3, 17, 200, 256},
+	}
+	// the sorted list is a permutation, but is not sorted
+	unsorted := aggregatedRangeCircuit{
+		Inputs: []frontend.Variable{200, 3, 17, 255, 3},
+		Sorted: []frontend.Variable{3, 17, 3, 200, 255},
+	}
+	assert.CheckCircuit(&circuit,
+		test.WithInvalidAssignment(&tampered),
+		test.WithInvalidAssignment(&outOfRange),
+		test.WithInvalidAssignment(&unsorted),
+		test.WithCurves(ecc.BN254))
+}
+
+type sortCircuit struct {
+	Inputs   []frontend.Variable
+	Expected []frontend.Variable
+}
+
+func (c *sortCircuit) Define(api frontend.API) error {
+	sorted, err := Sort(api, c.Inputs, 16)
+	if err != nil {
+		return err
+	}
+	for i := range sorted {
+		api.AssertIsEqual(sorted[i], c.Expected[i])
+	}
+	return nil
+}
+
+func TestSort(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := sortCircuit{Inputs: make([]frontend.Variable, 4), Expected: make([]frontend.Variable, 4)}
+	valid := sortCircuit{
+		Inputs:   []frontend.Variable{1000, 5, 65535, 42},
+		Expected: []frontend.Variable{5, 42, 1000, 65535},
+	}
+	invalid := sortCircuit{
+		Inputs:   []frontend.Variable{1000, 5, 65536, 42},
+		Expected: []frontend.Variable{5, 42, 1000, 65536},
+	}
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&valid), test.WithInvalidAssignment(&invalid), test.WithCurves(ecc.BN254))
+}
+
+type mergeCircuit struct {
+	A, B   []frontend.Variable
+	Merged []frontend.Variable
+}
+
+func (c *mergeCircuit) Define(api frontend.API) error {
+	if err := AssertIsSorted(api, c.A, 16); err != nil {
+		return err
+	}
+	if err := AssertIsSorted(api, c.B, 16); err != nil {
+		return err
+	}
+	return AssertIsSortedMerge(api, c.A, c.B, c.Merged, 16)
+}
+
+func TestAssertIsSortedMerge(t *testing.T) {
+	assert := test.NewAssert(t)
+	a := []frontend.Variable{1, 4, 4, 9}
+	b := []frontend.Variable{2, 4, 10}
+	circuit := mergeCircuit{A: make([]frontend.Variable, len(a)), B: make([]frontend.Variable, len(b)), Merged: make([]frontend.Variable, len(a)+len(b))}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&mergeCircuit{A: a, B: b, Merged: []frontend.Variable{1, 2, 4, 4, 4, 9, 10}}),
+		// one element is replaced
+		test.WithInvalidAssignment(&mergeCircuit{A: a, B: b, Merged: []frontend.Variable{1, 2, 4, 4, 5, 9, 10}}),
+		// a permutation of the inputs, but not sorted
+		test.WithInvalidAssignment(&mergeCircuit{A: a, B: b, Merged: []frontend.Variable{1, 4, 2, 4, 4, 9, 10}}),
+		// sorted, but duplicates an element instead of including another
+		test.WithInvalidAssignment(&mergeCircuit{A: a, B: b, Merged: []frontend.Variable{1, 1, 4, 4, 4, 9, 10}}),
+		test.WithCurves(ecc.BN254))
+}
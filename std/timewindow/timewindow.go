@@ -0,0 +1,38 @@
+// Package timewindow implements the verification of validity windows, for
+// example the validity period of a credential.
+//
+// Timestamps are unsigned integers of a fixed bit length, for example Unix
+// times in seconds on 32 or 64 bits.
+package timewindow
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/cmp"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// AssertInTimeWindow asserts that notBefore <= t <= notAfter. The timestamps
+// t, notBefore and notAfter are range checked to be smaller than 2^nbBits. It
+// returns an error if nbBits is not positive or too large for the native
+// field.
+func AssertInTimeWindow(api frontend.API, t, notBefore, notAfter frontend.Variable, nbBits int) error {
+	if nbBits < 1 || nbBits+2 >= api.Compiler().FieldBitLen() {
+		return fmt.Errorf("invalid timestamp bit length %d", nbBits)
+	}
+	rchecker := rangecheck.New(api)
+	rchecker.Check(t, nbBits)
+	rchecker.Check(notBefore, nbBits)
+	rchecker.Check(notAfter, nbBits)
+
+	// the timestamps are in [0, 2^nbBits), so their differences are at most
+	// 2^nbBits - 1 in absolute value.
+	bound := new(big.Int).Lsh(big.NewInt(1), uint(nbBits))
+	bound.Sub(bound, big.NewInt(1))
+	comparator := cmp.NewBoundedComparator(api, bound, false)
+	comparator.AssertIsLessEq(notBefore, t)
+	comparator.AssertIsLessEq(t, notAfter)
+	return nil
+}
@@ -0,0 +1,43 @@
+package timewindow
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type timeWindowCircuit struct {
+	T         frontend.Variable
+	NotBefore frontend.Variable `gnark:",public"`
+	NotAfter  frontend.Variable `gnark:",public"`
+}
+
+func (c *timeWindowCircuit) Define(api frontend.API) error {
+	return AssertInTimeWindow(api, c.T, c.NotBefore, c.NotAfter, 32)
+}
+
+func TestAssertInTimeWindow(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	const notBefore, notAfter = 1700000000, 1731536000
+	window := func(t uint64) *timeWindowCircuit {
+		return &timeWindowCircuit{T: t, NotBefore: notBefore, NotAfter: notAfter}
+	}
+	opts := []test.TestingOption{
+		test.WithCurves(ecc.BN254),
+		// boundaries are inclusive
+		test.WithValidAssignment(window(notBefore)),
+		test.WithValidAssignment(window(notAfter)),
+		test.WithValidAssignment(window(1715000000)),
+		test.WithInvalidAssignment(window(notBefore - 1)),
+		test.WithInvalidAssignment(window(notAfter + 1)),
+		test.WithInvalidAssignment(window(0)),
+		// timestamp out of range
+		test.WithInvalidAssignment(window(1 << 32)),
+		// empty window
+		test.WithInvalidAssignment(&timeWindowCircuit{T: notBefore, NotBefore: notAfter, NotAfter: notBefore}),
+	}
+	assert.CheckCircuit(&timeWindowCircuit{}, opts...)
+}
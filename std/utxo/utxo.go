@@ -0,0 +1,151 @@
+// Package utxo implements the verification of the spend of a note in a
+// shielded pool of unspent transaction outputs.
+//
+// A note carries a value, the EdDSA public key of its owner and a secret. The
+// pool publishes the Merkle root of the commitments of its notes
+//
+//	commitment = H(value || owner.X || owner.Y || secret)
+//
+// A spend consumes a note of the tree and creates an output note of the same
+// value. It reveals the nullifier of the spent note, see package
+// [nullifier], so that it can not be spent twice, and the commitment of the
+// output note, which is appended to the tree. The owner of the spent note
+// authorizes the spend by signing H(nullifier || output commitment).
+//
+// [AssertUpdate] also computes the tree after the spend in the circuit, for
+// stateful rollups: the leaf of the spent note is replaced by its nullifier and
+// the output note is inserted in a free leaf.
+package utxo
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/nullifier"
+	"github.com/consensys/gnark/std/signature/eddsa"
+)
+
+// Note is a note of the pool.
+type Note struct {
+	Value  frontend.Variable
+	Owner  eddsa.PublicKey
+	Secret frontend.Variable
+}
+
+// Spend is the private witness of a spend.
+type Spend struct {
+	// Note is the spent note.
+	Note Note
+	// Index is the position of the spent note in the tree.
+	Index frontend.Variable
+	// Path is the Merkle path of the spent note, Path[0] is its commitment.
+	Path []frontend.Variable
+	// Signature is the signature of the spend by the owner of the spent note.
+	Signature eddsa.Signature
+	// Output is the created note.
+	Output Note
+}
+
+// Commit returns the commitment of the note.
+func Commit(h hash.FieldHasher, note Note) frontend.Variable {
+	h.Reset()
+	h.Write(note.Value, note.Owner.A.X, note.Owner.A.Y, note.Secret)
+	return h.Sum()
+}
+
+// AssertSpend asserts that s spends a note of the tree of the given root, that
+// nf is the nullifier of the spent note and outputCommitment the commitment of
+// the output note, and that the spend is signed by the owner of the spent note.
+// The output note must have the same value as the spent note.
+//
+// The hasher h is used for the note commitments, the Merkle tree and the
+// signature; the nullifier is derived with MiMC. It returns an error if the
+// Merkle path is empty.
+func AssertSpend(curve twistededwards.Curve, h hash.FieldHasher, root, nf, outputCommitment frontend.Variable, s Spend) error {
+	api := curve.API()
+	if len(s.Path) == 0 {
+		return fmt.Errorf("empty Merkle path")
+	}
+
+	// the spent note is in the tree
+	api.AssertIsEqual(s.Path[0], Commit(h, s.Note))
+	proof := merkle.MerkleProof{RootHash: root, Path: s.Path}
+	proof.VerifyProof(api, h, s.Index)
+
+	return assertAuthorized(curve, h, nf, outputCommitment, s)
+}
+
+// Update is the private witness of a spend which also updates the tree: the
+// spent note is marked spent and the output note is inserted in a free leaf.
+type Update struct {
+	Spend
+	// InsertIndex is the position of the free leaf receiving the output note.
+	InsertIndex frontend.Variable
+	// InsertPath is the Merkle path of the free leaf in the tree where the
+	// spent note is marked spent. InsertPath[0] is the free leaf, 0.
+	InsertPath []frontend.Variable
+}
+
+// AssertUpdate asserts that u is a valid spend of a note of the tree of root
+// oldRoot, as [AssertSpend] does, and returns the root of the tree after the
+// update. In the updated tree, the leaf of the spent note is replaced by its
+// nullifier nf, which marks it spent, and the free leaf at u.InsertIndex by
+// outputCommitment.
+//
+// The membership of the spent note and its replacement share the same Merkle
+// path. It returns an error if a Merkle path is empty or if the two paths have
+// different lengths.
+func AssertUpdate(curve twistededwards.Curve, h hash.FieldHasher, oldRoot, nf, outputCommitment frontend.Variable, u Update) (frontend.Variable, error) {
+	api := curve.API()
+	if len(u.Path) == 0 {
+		return nil, fmt.Errorf("empty Merkle path")
+	}
+	if len(u.InsertPath) != len(u.Path) {
+		return nil, fmt.Errorf("insertion path has length %d, expected %d", len(u.InsertPath), len(u.Path))
+	}
+
+	// the spent note is in the tree, and is marked spent
+	api.AssertIsEqual(u.Path[0], Commit(h, u.Note))
+	proof := merkle.MerkleProof{RootHash: oldRoot, Path: u.Path}
+	spentRoot := proof.ReplaceLeaf(api, h, u.Index, nf)
+
+	// the output note is inserted in a free leaf of the tree with the spent
+	// note marked. The leaf of the spent note holds the nullifier, it is not
+	// free.
+	api.AssertIsEqual(u.InsertPath[0], 0)
+	insertion := merkle.MerkleProof{RootHash: spentRoot, Path: u.InsertPath}
+	newRoot := insertion.ReplaceLeaf(api, h, u.InsertIndex, outputCommitment)
+
+	if err := assertAuthorized(curve, h, nf, outputCommitment, u.Spend); err != nil {
+		return nil, err
+	}
+	return newRoot, nil
+}
+
+// assertAuthorized asserts that nf is the nullifier of the spent note,
+// outputCommitment the commitment of the output note of the same value, and
+// that the spend is signed by the owner of the spent note.
+func assertAuthorized(curve twistededwards.Curve, h hash.FieldHasher, nf, outputCommitment frontend.Variable, s Spend) error {
+	api := curve.API()
+
+	// the nullifier is bound to the position of the note, so that two notes
+	// with the same secret have different nullifiers
+	if err := nullifier.AssertNullifier(api, s.Note.Secret, s.Index, nf); err != nil {
+		return fmt.Errorf("nullifier: %w", err)
+	}
+
+	api.AssertIsEqual(s.Output.Value, s.Note.Value)
+	api.AssertIsEqual(Commit(h, s.Output), outputCommitment)
+
+	h.Reset()
+	h.Write(nf, outputCommitment)
+	msg := h.Sum()
+	h.Reset()
+	if err := eddsa.Verify(curve, s.Signature, msg, s.Note.Owner, h); err != nil {
+		return fmt.Errorf("signature: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package utxo
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptoeddsa "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards/eddsa"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/nullifier"
+	"github.com/consensys/gnark/test"
+)
+
+const depth = 2
+
+type spendCircuit struct {
+	Root             frontend.Variable `gnark:",public"`
+	Nullifier        frontend.Variable `gnark:",public"`
+	OutputCommitment frontend.Variable `gnark:",public"`
+	Spend            Spend
+}
+
+func (c *spendCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return AssertSpend(curve, &h, c.Root, c.Nullifier, c.OutputCommitment, c.Spend)
+}
+
+type note struct {
+	value, secret *big.Int
+	owner         *cryptoeddsa.PrivateKey
+}
+
+func (n note) commitment() []byte {
+	h := hash.MIMC_BN254.New()
+	pk := n.owner.PublicKey.A
+	x, y := pk.X.Bytes(), pk.Y.Bytes()
+	h.Write(n.value.FillBytes(make([]byte, 32)))
+	h.Write(x[:])
+	h.Write(y[:])
+	h.Write(n.secret.FillBytes(make([]byte, 32)))
+	return h.Sum(nil)
+}
+
+func (n note) assign(a *Note) {
+	a.Value = n.value
+	a.Owner.Assign(tedwards.BN254, n.owner.Public().Bytes())
+	a.Secret = n.secret
+}
+
+func TestAssertSpend(t *testing.T) {
+	assert := test.NewAssert(t)
+	randomness := rand.New(rand.NewSource(42)) //#nosec G404 -- test only
+
+	var notes [1 << depth]note
+	for i := range notes {
+		owner, err := cryptoeddsa.GenerateKey(randomness)
+		assert.NoError(err)
+		notes[i] = note{value: big.NewInt(int64(100 * (i + 1))), secret: big.NewInt(randomness.Int63()), owner: owner}
+	}
+	recipient, err := cryptoeddsa.GenerateKey(randomness)
+	assert.NoError(err)
+
+	// spend returns the assignment of the spend of the note at index to a new
+	// note of the given value, signed by signer
+	spend := func(index uint64, value int64, signer *cryptoeddsa.PrivateKey) *spendCircuit {
+		tree := merkletree.New(hash.MIMC_BN254.New())
+		assert.NoError(tree.SetIndex(index))
+		for i := range notes {
+			tree.Push(notes[i].commitment())
+		}
+		root, path, _, _ := tree.Prove()
+
+		in := notes[index]
+		out := note{value: big.NewInt(value), secret: big.NewInt(randomness.Int63()), owner: recipient}
+		nf, err := nullifier.Compute(ecc.BN254, in.secret, new(big.Int).SetUint64(index))
+		assert.NoError(err)
+		outCm := out.commitment()
+
+		h := hash.MIMC_BN254.New()
+		h.Write(nf.FillBytes(make([]byte, 32)))
+		h.Write(outCm)
+		sig, err := signer.Sign(h.Sum(nil), hash.MIMC_BN254.New())
+		assert.NoError(err)
+
+		c := &spendCircuit{Root: root, Nullifier: nf, OutputCommitment: outCm}
+		in.assign(&c.Spend.Note)
+		out.assign(&c.Spend.Output)
+		c.Spend.Index = index
+		c.Spend.Path = make([]frontend.Variable, len(path))
+		for i := range path {
+			c.Spend.Path[i] = path[i]
+		}
+		c.Spend.Signature.Assign(tedwards.BN254, sig)
+		return c
+	}
+
+	wrongNullifier := spend(1, 200, notes[1].owner)
+	wrongNullifier.Nullifier = spend(2, 300, notes[2].owner).Nullifier
+	wrongIndex := spend(1, 200, notes[1].owner)
+	wrongIndex.Spend.Index = 3
+	wrongOutput := spend(1, 200, notes[1].owner)
+	wrongOutput.OutputCommitment = spend(1, 200, notes[1].owner).OutputCommitment
+
+	circuit := spendCircuit{Spend: Spend{Path: make([]frontend.Variable, depth+1)}}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(spend(1, 200, notes[1].owner)),
+		test.WithValidAssignment(spend(3, 400, notes[3].owner)),
+		// the value is not preserved
+		test.WithInvalidAssignment(spend(1, 201, notes[1].owner)),
+		// the spend is not signed by the owner of the note
+		test.WithInvalidAssignment(spend(1, 200, notes[2].owner)),
+		test.WithInvalidAssignment(wrongNullifier),
+		test.WithInvalidAssignment(wrongIndex),
+		test.WithInvalidAssignment(wrongOutput),
+		test.WithCurves(ecc.BN254))
+}
+
+type updateCircuit struct {
+	OldRoot          frontend.Variable `gnark:",public"`
+	NewRoot          frontend.Variable `gnark:",public"`
+	Nullifier        frontend.Variable `gnark:",public"`
+	OutputCommitment frontend.Variable `gnark:",public"`
+	Update           Update
+}
+
+func (c *updateCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	newRoot, err := AssertUpdate(curve, &h, c.OldRoot, c.Nullifier, c.OutputCommitment, c.Update)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(newRoot, c.NewRoot)
+	return nil
+}
+
+func TestAssertUpdate(t *testing.T) {
+	assert := test.NewAssert(t)
+	randomness := rand.New(rand.NewSource(42)) //#nosec G404 -- test only
+
+	// 4 notes followed by 4 free leaves
+	const depth, spent, free = 3, 2, 5
+	var notes [4]note
+	leaves := make([][]byte, 1<<depth)
+	for i := range leaves {
+		leaves[i] = make([]byte, 32)
+	}
+	for i := range notes {
+		owner, err := cryptoeddsa.GenerateKey(randomness)
+		assert.NoError(err)
+		notes[i] = note{value: big.NewInt(int64(100 * (i + 1))), secret: big.NewInt(randomness.Int63()), owner: owner}
+		leaves[i] = notes[i].commitment()
+	}
+	recipient, err := cryptoeddsa.GenerateKey(randomness)
+	assert.NoError(err)
+	prove := func(index uint64) ([]byte, [][]byte) {
+		tree := merkletree.New(hash.MIMC_BN254.New())
+		assert.NoError(tree.SetIndex(index))
+		for _, l := range leaves {
+			tree.Push(l)
+		}
+		root, path, _, _ := tree.Prove()
+		return root, path
+	}
+	toVariables := func(path [][]byte) []frontend.Variable {
+		res := make([]frontend.Variable, len(path))
+		for i := range path {
+			res[i] = path[i]
+		}
+		return res
+	}
+
+	in := notes[spent]
+	out := note{value: in.value, secret: big.NewInt(randomness.Int63()), owner: recipient}
+	nf, err := nullifier.Compute(ecc.BN254, in.secret, big.NewInt(spent))
+	assert.NoError(err)
+	outCm := out.commitment()
+	h := hash.MIMC_BN254.New()
+	h.Write(nf.FillBytes(make([]byte, 32)))
+	h.Write(outCm)
+	sig, err := in.owner.Sign(h.Sum(nil), hash.MIMC_BN254.New())
+	assert.NoError(err)
+
+	// the update, off-circuit
+	oldRoot, spendPath := prove(spent)
+	leaves[spent] = nf.FillBytes(make([]byte, 32))
+	_, insertPath := prove(free)
+	leaves[free] = outCm
+	newRoot, _ := prove(0)
+
+	valid := updateCircuit{OldRoot: oldRoot, NewRoot: newRoot, Nullifier: nf, OutputCommitment: outCm}
+	in.assign(&valid.Update.Note)
+	out.assign(&valid.Update.Output)
+	valid.Update.Index = spent
+	valid.Update.Path = toVariables(spendPath)
+	valid.Update.Signature.Assign(tedwards.BN254, sig)
+	valid.Update.InsertIndex = free
+	valid.Update.InsertPath = toVariables(insertPath)
+
+	wrongNewRoot := valid
+	wrongNewRoot.NewRoot = oldRoot
+	// the output note can not overwrite the note of another owner
+	occupied := valid
+	_, occupiedPath := prove(1)
+	occupied.Update.InsertIndex = 1
+	occupied.Update.InsertPath = toVariables(occupiedPath)
+
+	circuit := updateCircuit{Update: Update{
+		Spend:      Spend{Path: make([]frontend.Variable, depth+1)},
+		InsertPath: make([]frontend.Variable, depth+1),
+	}}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&valid),
+		test.WithInvalidAssignment(&wrongNewRoot),
+		test.WithInvalidAssignment(&occupied),
+		test.WithCurves(ecc.BN254))
+}
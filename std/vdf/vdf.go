@@ -0,0 +1,80 @@
+// Package vdf implements the verification of the repeated squaring verifiable
+// delay function.
+//
+// Given an input x and a number of iterations t = 2ᵏ, the output of the VDF is
+// y = x^(2ᵗ) in the group of integers modulo an RSA modulus N of unknown
+// factorization. Computing y requires t sequential squarings, but the
+// verification with a Pietrzak proof [Pietrzak] only costs O(k) exponentiations
+// by λ-bit challenges, which makes the in-circuit work logarithmic in t.
+//
+// The modulus N is given by the emulated field parameters T, which do not need
+// to be prime. Like in the original construction, the caller should ensure the
+// inputs lie in a subgroup without elements of low order (for example by
+// squaring them).
+//
+// [Pietrzak]: https://eprint.iacr.org/2018/627
+package vdf
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Proof is a Pietrzak proof that y = x^(2ᵗ) for t = 2^len(Mu). Mu[i] is the
+// midpoint x_i^(2^(t/2^(i+1))) of the i-th halving round.
+type Proof[T emulated.FieldParams] struct {
+	Mu []emulated.Element[T]
+}
+
+// Verify asserts that y = x^(2ᵗ) modulo N for t = 2^len(proof.Mu).
+//
+// At every halving round the challenge r is derived by hashing the canonical
+// limbs of (x_i, y_i, μ_i) with h and keeping the nbChallengeBits least
+// significant bits of the digest. The instance is then folded into
+// x_{i+1} = x_i^r * μ_i and y_{i+1} = μ_i^r * y_i. After the last round it
+// asserts y_k = x_k².
+func Verify[T emulated.FieldParams](api frontend.API, h hash.FieldHasher, x, y *emulated.Element[T], proof Proof[T], nbChallengeBits int) error {
+	if nbChallengeBits <= 0 || nbChallengeBits >= api.Compiler().FieldBitLen() {
+		return fmt.Errorf("invalid number of challenge bits %d", nbChallengeBits)
+	}
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return fmt.Errorf("new field: %w", err)
+	}
+	xi, yi := x, y
+	for i := range proof.Mu {
+		mu := &proof.Mu[i]
+		r := challenge(api, f, h, nbChallengeBits, xi, yi, mu)
+		xi = f.Mul(exp(f, xi, r), mu)
+		yi = f.Mul(exp(f, mu, r), yi)
+	}
+	f.AssertIsEqual(f.Mul(xi, xi), yi)
+	return nil
+}
+
+// challenge returns the little-endian bits of the folding challenge.
+func challenge[T emulated.FieldParams](api frontend.API, f *emulated.Field[T], h hash.FieldHasher, nbBits int, elems ...*emulated.Element[T]) []frontend.Variable {
+	h.Reset()
+	for _, e := range elems {
+		// hash the canonical representation, so that the challenge is uniquely
+		// defined by the values.
+		c := f.Reduce(e)
+		f.AssertIsInRange(c)
+		h.Write(c.Limbs...)
+	}
+	return bits.ToBinary(api, h.Sum())[:nbBits]
+}
+
+// exp returns x^e where e is given by its little-endian bits.
+func exp[T emulated.FieldParams](f *emulated.Field[T], x *emulated.Element[T], e []frontend.Variable) *emulated.Element[T] {
+	res := f.One()
+	for i := len(e) - 1; i >= 0; i-- {
+		res = f.Mul(res, res)
+		res = f.Select(e[i], f.Mul(res, x), res)
+	}
+	return res
+}
@@ -0,0 +1,118 @@
+package vdf
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/frontend"
+	gmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+const nbChallengeBits = 16
+
+// rsaModulus is a toy RSA modulus, product of two 60-bit primes.
+var rsaModulus = func() *big.Int {
+	nextPrime := func(v *big.Int) *big.Int {
+		for !v.ProbablyPrime(20) {
+			v.Add(v, big.NewInt(1))
+		}
+		return v
+	}
+	p := nextPrime(new(big.Int).Lsh(big.NewInt(3), 58))
+	q := nextPrime(new(big.Int).Lsh(big.NewInt(5), 57))
+	return p.Mul(p, q)
+}()
+
+type toyRSA struct{}
+
+func (toyRSA) NbLimbs() uint     { return 2 }
+func (toyRSA) BitsPerLimb() uint { return 64 }
+func (toyRSA) IsPrime() bool     { return false }
+func (toyRSA) Modulus() *big.Int { return rsaModulus }
+
+type vdfCircuit struct {
+	X, Y  emulated.Element[toyRSA]
+	Proof Proof[toyRSA]
+}
+
+func (c *vdfCircuit) Define(api frontend.API) error {
+	h, err := gmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return Verify(api, &h, &c.X, &c.Y, c.Proof, nbChallengeBits)
+}
+
+// refChallenge computes the folding challenge off-circuit.
+func refChallenge(elems ...*big.Int) *big.Int {
+	h := mimc.NewMiMC()
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(1))
+	for _, e := range elems {
+		for i := 0; i < 2; i++ {
+			var limb fr.Element
+			limb.SetBigInt(new(big.Int).And(new(big.Int).Rsh(e, uint(64*i)), mask))
+			b := limb.Bytes()
+			h.Write(b[:])
+		}
+	}
+	r := new(big.Int).SetBytes(h.Sum(nil))
+	return r.Mod(r, new(big.Int).Lsh(big.NewInt(1), nbChallengeBits))
+}
+
+// refProve evaluates the VDF with t = 2^logT squarings and computes the
+// Pietrzak proof.
+func refProve(x *big.Int, logT int) (y *big.Int, mu []*big.Int) {
+	square := func(v *big.Int, n int) *big.Int {
+		res := new(big.Int).Set(v)
+		for i := 0; i < n; i++ {
+			res.Mul(res, res).Mod(res, rsaModulus)
+		}
+		return res
+	}
+	y = square(x, 1<<logT)
+	xi, yi := new(big.Int).Set(x), new(big.Int).Set(y)
+	for i := 0; i < logT; i++ {
+		m := square(xi, 1<<(logT-i-1))
+		mu = append(mu, m)
+		r := refChallenge(xi, yi, m)
+		xi.Exp(xi, r, rsaModulus).Mul(xi, m).Mod(xi, rsaModulus)
+		yi.Mul(new(big.Int).Exp(m, r, rsaModulus), yi).Mod(yi, rsaModulus)
+	}
+	return y, mu
+}
+
+func TestVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	const logT = 4
+
+	x := big.NewInt(0x1234567)
+	x.Mul(x, x) // ensure x is a quadratic residue
+	y, mu := refProve(x, logT)
+
+	assign := func(x, y *big.Int, mu []*big.Int) *vdfCircuit {
+		res := &vdfCircuit{
+			X: emulated.ValueOf[toyRSA](x),
+			Y: emulated.ValueOf[toyRSA](y),
+		}
+		for i := range mu {
+			res.Proof.Mu = append(res.Proof.Mu, emulated.ValueOf[toyRSA](mu[i]))
+		}
+		return res
+	}
+
+	wrongY := new(big.Int).Add(y, big.NewInt(1))
+	wrongMu := append([]*big.Int{}, mu...)
+	wrongMu[1] = new(big.Int).Add(mu[1], big.NewInt(1))
+
+	circuit := &vdfCircuit{Proof: Proof[toyRSA]{Mu: make([]emulated.Element[toyRSA], logT)}}
+	assert.CheckCircuit(circuit,
+		test.WithValidAssignment(assign(x, y, mu)),
+		test.WithInvalidAssignment(assign(x, wrongY, mu)),
+		test.WithInvalidAssignment(assign(x, y, wrongMu)),
+		test.WithCurves(ecc.BN254))
+}
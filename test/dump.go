@@ -0,0 +1,116 @@
+package test
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// DumpWitnessCSV compiles the circuit to R1CS over the scalar field of curveID,
+// solves it with the assignment and writes one CSV row per wire of the
+// solution to w, with the columns
+//
+//	wire,name,visibility,value
+//
+// The wires are in the order of the constraint system: the constant wire, the
+// public inputs, the secret inputs and then the internal wires. Inputs are
+// named after the circuit fields, internal wires have no name. It is meant as a
+// debugging aid, for example to diff the solutions of two runs.
+func DumpWitnessCSV(w io.Writer, circuit, assignment frontend.Circuit, curveID ecc.ID) error {
+	field := curveID.ScalarField()
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, circuit)
+	if err != nil {
+		return fmt.Errorf("compile: %w", err)
+	}
+	fullWitness, err := frontend.NewWitness(assignment, field)
+	if err != nil {
+		return fmt.Errorf("new witness: %w", err)
+	}
+	solution, err := ccs.Solve(fullWitness)
+	if err != nil {
+		return fmt.Errorf("solve: %w", err)
+	}
+
+	var values []*big.Int
+	switch sol := solution.(type) {
+	case *cs_bls12377.R1CSSolution:
+		values = toBigInts(sol.W)
+	case *cs_bls12381.R1CSSolution:
+		values = toBigInts(sol.W)
+	case *cs_bls24315.R1CSSolution:
+		values = toBigInts(sol.W)
+	case *cs_bls24317.R1CSSolution:
+		values = toBigInts(sol.W)
+	case *cs_bn254.R1CSSolution:
+		values = toBigInts(sol.W)
+	case *cs_bw6633.R1CSSolution:
+		values = toBigInts(sol.W)
+	case *cs_bw6761.R1CSSolution:
+		values = toBigInts(sol.W)
+	default:
+		return fmt.Errorf("unsupported solution type %T", solution)
+	}
+
+	// names of the inputs, in the order of the witness.
+	var public, secret []string
+	_, err = schema.Walk(assignment, tVariable, func(leaf schema.LeafInfo, _ reflect.Value) error {
+		if leaf.Visibility == schema.Public {
+			public = append(public, leaf.FullName())
+		} else if leaf.Visibility == schema.Secret {
+			secret = append(secret, leaf.FullName())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"wire", "name", "visibility", "value"}); err != nil {
+		return err
+	}
+	for i := range values {
+		var name, visibility string
+		switch {
+		case i == 0:
+			name, visibility = "one", schema.Public.String()
+		case i <= len(public):
+			name, visibility = public[i-1], schema.Public.String()
+		case i <= len(public)+len(secret):
+			name, visibility = secret[i-1-len(public)], schema.Secret.String()
+		default:
+			visibility = schema.Internal.String()
+		}
+		if err := cw.Write([]string{strconv.Itoa(i), name, visibility, values[i].String()}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func toBigInts[E any, PE interface {
+	*E
+	BigInt(*big.Int) *big.Int
+}](v []E) []*big.Int {
+	res := make([]*big.Int, len(v))
+	for i := range v {
+		res[i] = PE(&v[i]).BigInt(new(big.Int))
+	}
+	return res
+}
@@ -0,0 +1,41 @@
+package test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+func TestDumpWitnessCSV(t *testing.T) {
+	var assignment simulateCircuit
+	assignment.X, assignment.Y, assignment.Z.W = 3, 9, 4
+	var buf bytes.Buffer
+	if err := DumpWitnessCSV(&buf, &simulateCircuit{}, &assignment, ecc.BN254); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// header, constant wire, 2 public and 1 secret inputs, and the internal
+	// wire of the multiplication
+	expected := [][]string{
+		{"wire", "name", "visibility", "value"},
+		{"0", "one", "public", "1"},
+		{"1", "Y", "public", "9"},
+		{"2", "Z_W", "public", "4"},
+		{"3", "X", "secret", "3"},
+		{"4", "", "internal", "9"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d rows, got %d: %v", len(expected), len(records), records)
+	}
+	for i := range expected {
+		if strings.Join(records[i], ",") != strings.Join(expected[i], ",") {
+			t.Fatalf("row %d: expected %v, got %v", i, expected[i], records[i])
+		}
+	}
+}
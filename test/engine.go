@@ -136,6 +136,10 @@ func IsSolved(circuit, witness frontend.Circuit, field *big.Int, opts ...TestEng
 		}
 	}
 
+	// the constraints of the inputs tagged with range and boolean options, as
+	// emitted by the compiler
+	assertTagged(e, c)
+
 	if err = c.Define(e); err != nil {
 		return fmt.Errorf("define: %w", err)
 	}
@@ -589,6 +593,19 @@ func (e *engine) MarkBoolean(v frontend.Variable) {
 	}
 }
 
+// AssumeInRange checks that the value of v has bit-length at most bits. Unlike
+// the constraint system builders, the test engine knows the values and
+// verifies the assumptions.
+func (e *engine) AssumeInRange(v frontend.Variable, bits int) error {
+	if bits <= 0 {
+		return fmt.Errorf("range assumption: invalid bit-length %d", bits)
+	}
+	if n := e.toBigInt(v).BitLen(); n > bits {
+		return fmt.Errorf("range assumption does not hold: value has %d bits, more than %d", n, bits)
+	}
+	return nil
+}
+
 func (e *engine) toBigInt(i1 frontend.Variable) *big.Int {
 	switch vv := i1.(type) {
 	case *big.Int:
@@ -664,6 +681,20 @@ func copyWitness(to, from frontend.Circuit) {
 
 }
 
+// assertTagged asserts that the inputs of the circuit tagged with
+// [schema.TagOptRange] or [schema.TagOptBoolean] are in range and booleans.
+func assertTagged(api frontend.API, c frontend.Circuit) {
+	_, _ = schema.Walk(c, tVariable, func(f schema.LeafInfo, tInput reflect.Value) error {
+		if f.Boolean {
+			api.AssertIsBoolean(tInput.Interface())
+		}
+		if f.Range > 0 {
+			api.ToBinary(tInput.Interface(), f.Range)
+		}
+		return nil
+	})
+}
+
 func (e *engine) Field() *big.Int {
 	return e.q
 }
@@ -672,6 +703,9 @@ func (e *engine) Compiler() frontend.Compiler {
 	return e
 }
 
+// GnarkAPI implements [schema.CircuitAPI].
+func (e *engine) GnarkAPI() {}
+
 func (e *engine) Commit(v ...frontend.Variable) (frontend.Variable, error) {
 	nb := (e.FieldBitLen() + 7) / 8
 	buf := make([]byte, nb)
@@ -0,0 +1,249 @@
+package test
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/kvstore"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// Gradients are the values and the derivatives of the equality assertions of
+// a circuit, see [Gradient].
+type Gradients struct {
+	// Residuals[i] is a - b for the i-th call to api.AssertIsEqual(a, b).
+	Residuals []*big.Int
+	// Partials[i][j] is the derivative of Residuals[i] with respect to the
+	// j-th input of wrt.
+	Partials [][]*big.Int
+}
+
+// Gradient executes the circuit with the assignment in the test engine, as
+// [IsSolved], and returns the derivatives of its outputs with respect to the
+// inputs with the full names wrt (for example "X" or "A_B_2").
+//
+// The outputs are the residuals a - b of the calls to api.AssertIsEqual(a, b):
+// for a circuit asserting api.AssertIsEqual(f(X), Y), their derivative with
+// respect to X is f'(X). The equality assertions are not checked, so that the
+// gradients can be computed for any assignment, the other assertions are.
+//
+// The derivatives are the formal derivatives over the field, propagated with
+// dual numbers through the arithmetic operations: Add, Sub, Neg, Mul, MulAcc,
+// Div, DivUnchecked and Inverse. Select and Lookup2 propagate the derivative
+// of the selected value. The other operations (binary decomposition,
+// comparisons, hints, commitments...) are piecewise constant: their results
+// have a zero derivative.
+//
+// This is an experimental feature.
+func Gradient(circuit, assignment frontend.Circuit, field *big.Int, wrt []string, opts ...TestEngineOption) (g *Gradients, err error) {
+	e := &gradientEngine{
+		engine: &engine{
+			curveID: utils.FieldToCurve(field),
+			q:       new(big.Int).Set(field),
+			Store:   kvstore.New(),
+		},
+		derivatives: make(map[*big.Int][]*big.Int),
+		nbWrt:       len(wrt),
+	}
+	for _, opt := range opts {
+		if err := opt(e.engine); err != nil {
+			return nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+
+	c := shallowClone(circuit)
+	copyWitness(c, assignment)
+
+	// the inputs are set to distinct values, the ones in wrt are the seeds of
+	// the derivatives
+	index := make(map[string]int, len(wrt))
+	for j, name := range wrt {
+		index[name] = j
+	}
+	found := make([]bool, len(wrt))
+	if _, err := schema.Walk(c, tVariable, func(leaf schema.LeafInfo, tValue reflect.Value) error {
+		v := new(big.Int).Set(e.toBigInt(tValue.Interface()))
+		v.Mod(v, e.modulus())
+		tValue.Set(reflect.ValueOf(v))
+		if j, ok := index[leaf.FullName()]; ok {
+			e.derivatives[v] = e.unit(j)
+			found[j] = true
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for j, ok := range found {
+		if !ok {
+			return nil, fmt.Errorf("unknown input %s", wrt[j])
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			g, err = nil, fmt.Errorf("%v\n%s", r, string(debug.Stack()))
+		}
+	}()
+	if err = c.Define(e); err != nil {
+		return nil, fmt.Errorf("define: %w", err)
+	}
+	if err = callDeferred(e.engine); err != nil {
+		return nil, fmt.Errorf("deferred: %w", err)
+	}
+	return &e.gradients, nil
+}
+
+// gradientEngine is the test engine, with the derivatives of the values
+// returned by the arithmetic operations. The values with no derivative have a
+// zero one.
+type gradientEngine struct {
+	*engine
+	derivatives map[*big.Int][]*big.Int
+	nbWrt       int
+	gradients   Gradients
+}
+
+func (e *gradientEngine) unit(j int) []*big.Int {
+	d := e.zero()
+	d[j].SetUint64(1)
+	return d
+}
+
+func (e *gradientEngine) zero() []*big.Int {
+	d := make([]*big.Int, e.nbWrt)
+	for j := range d {
+		d[j] = new(big.Int)
+	}
+	return d
+}
+
+// derivative returns the derivative of v, nil if it is zero.
+func (e *gradientEngine) derivative(v frontend.Variable) []*big.Int {
+	if b, ok := v.(*big.Int); ok {
+		return e.derivatives[b]
+	}
+	return nil
+}
+
+// set returns a copy of res with the derivative d. The engine may return one
+// of the operands (e.g. x*1), whose derivative must not be overwritten.
+func (e *gradientEngine) set(res frontend.Variable, d []*big.Int) frontend.Variable {
+	if d == nil {
+		return res
+	}
+	b := new(big.Int).Set(e.toBigInt(res))
+	for j := range d {
+		d[j].Mod(d[j], e.modulus())
+	}
+	e.derivatives[b] = d
+	return b
+}
+
+// linear returns Σ c_i * d_i, nil if all the d_i are nil.
+func (e *gradientEngine) linear(c []*big.Int, d [][]*big.Int) []*big.Int {
+	var res []*big.Int
+	var t big.Int
+	for i := range d {
+		if d[i] == nil {
+			continue
+		}
+		if res == nil {
+			res = e.zero()
+		}
+		for j := range res {
+			res[j].Add(res[j], t.Mul(c[i], d[i][j]))
+		}
+	}
+	return res
+}
+
+var (
+	one      = big.NewInt(1)
+	minusOne = big.NewInt(-1)
+)
+
+func (e *gradientEngine) Add(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
+	vs := append([]frontend.Variable{i1, i2}, in...)
+	c := make([]*big.Int, len(vs))
+	d := make([][]*big.Int, len(vs))
+	for i := range vs {
+		c[i], d[i] = one, e.derivative(vs[i])
+	}
+	return e.set(e.engine.Add(i1, i2, in...), e.linear(c, d))
+}
+
+func (e *gradientEngine) Sub(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
+	vs := append([]frontend.Variable{i1, i2}, in...)
+	c := make([]*big.Int, len(vs))
+	d := make([][]*big.Int, len(vs))
+	for i := range vs {
+		c[i], d[i] = minusOne, e.derivative(vs[i])
+	}
+	c[0] = one
+	return e.set(e.engine.Sub(i1, i2, in...), e.linear(c, d))
+}
+
+func (e *gradientEngine) Neg(i1 frontend.Variable) frontend.Variable {
+	return e.set(e.engine.Neg(i1), e.linear([]*big.Int{minusOne}, [][]*big.Int{e.derivative(i1)}))
+}
+
+func (e *gradientEngine) Mul(i1, i2 frontend.Variable, in ...frontend.Variable) frontend.Variable {
+	// (a*b)' = a'*b + a*b', for each factor
+	res := i1
+	for _, v := range append([]frontend.Variable{i2}, in...) {
+		d := e.linear([]*big.Int{e.toBigInt(v), e.toBigInt(res)}, [][]*big.Int{e.derivative(res), e.derivative(v)})
+		res = e.set(e.engine.Mul(res, v), d)
+	}
+	return res
+}
+
+func (e *gradientEngine) MulAcc(a, b, c frontend.Variable) frontend.Variable {
+	d := e.linear([]*big.Int{one, e.toBigInt(c), e.toBigInt(b)}, [][]*big.Int{e.derivative(a), e.derivative(b), e.derivative(c)})
+	return e.set(e.engine.MulAcc(a, b, c), d)
+}
+
+// quotient returns the derivative of i1/i2 = res: (i1' - res*i2') / i2.
+func (e *gradientEngine) quotient(res, i1, i2 frontend.Variable) []*big.Int {
+	d := e.linear([]*big.Int{one, new(big.Int).Neg(e.toBigInt(res))}, [][]*big.Int{e.derivative(i1), e.derivative(i2)})
+	if d == nil {
+		return nil
+	}
+	inv := new(big.Int).ModInverse(e.toBigInt(i2), e.modulus())
+	for j := range d {
+		d[j].Mul(d[j], inv)
+	}
+	return d
+}
+
+func (e *gradientEngine) Div(i1, i2 frontend.Variable) frontend.Variable {
+	res := e.engine.Div(i1, i2)
+	return e.set(res, e.quotient(res, i1, i2))
+}
+
+func (e *gradientEngine) DivUnchecked(i1, i2 frontend.Variable) frontend.Variable {
+	res := e.engine.DivUnchecked(i1, i2)
+	if e.toBigInt(i2).Sign() == 0 {
+		// 0/0 is defined as 0
+		return res
+	}
+	return e.set(res, e.quotient(res, i1, i2))
+}
+
+func (e *gradientEngine) Inverse(i1 frontend.Variable) frontend.Variable {
+	res := e.engine.Inverse(i1)
+	return e.set(res, e.quotient(res, 1, i1))
+}
+
+func (e *gradientEngine) AssertIsEqual(i1, i2 frontend.Variable) {
+	r := e.Sub(i1, i2)
+	d := e.derivative(r)
+	if d == nil {
+		d = e.zero()
+	}
+	e.gradients.Residuals = append(e.gradients.Residuals, new(big.Int).Set(e.toBigInt(r)))
+	e.gradients.Partials = append(e.gradients.Partials, d)
+}
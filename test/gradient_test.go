@@ -0,0 +1,109 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+type gradientPolyCircuit struct {
+	X, Y, Z frontend.Variable
+	Out     frontend.Variable `gnark:",public"`
+}
+
+func (c *gradientPolyCircuit) Define(api frontend.API) error {
+	// f = X²Y + 3XZ - Z + 7
+	f := api.Add(api.Mul(c.X, c.X, c.Y), api.Mul(3, c.X, c.Z), api.Neg(c.Z), 7)
+	api.AssertIsEqual(f, c.Out)
+	return nil
+}
+
+func TestGradientFiniteDifferences(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	point := map[string]int64{"X": 5, "Y": -3, "Z": 11}
+	assignment := func(p map[string]int64) *gradientPolyCircuit {
+		return &gradientPolyCircuit{X: p["X"], Y: p["Y"], Z: p["Z"], Out: 0}
+	}
+	wrt := []string{"X", "Y", "Z"}
+	g, err := Gradient(&gradientPolyCircuit{}, assignment(point), field, wrt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Residuals) != 1 || len(g.Partials[0]) != len(wrt) {
+		t.Fatalf("unexpected gradients shape")
+	}
+
+	// f has degree at most 2 in each input, the central differences
+	// (f(x+h) - f(x-h)) / 2h are exact
+	residual := func(p map[string]int64) *big.Int {
+		g, err := Gradient(&gradientPolyCircuit{}, assignment(p), field, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g.Residuals[0]
+	}
+	for j, name := range wrt {
+		for _, h := range []int64{1, 4} {
+			plus, minus := make(map[string]int64), make(map[string]int64)
+			for k, v := range point {
+				plus[k], minus[k] = v, v
+			}
+			plus[name] += h
+			minus[name] -= h
+			diff := new(big.Int).Sub(residual(plus), residual(minus))
+			inv := new(big.Int).ModInverse(big.NewInt(2*h), field)
+			diff.Mul(diff, inv).Mod(diff, field)
+			if diff.Cmp(g.Partials[0][j]) != 0 {
+				t.Fatalf("d/d%s: finite difference %s, gradient %s", name, diff, g.Partials[0][j])
+			}
+		}
+	}
+
+	if _, err := Gradient(&gradientPolyCircuit{}, assignment(point), field, []string{"W"}); err == nil {
+		t.Fatal("expected error for an unknown input")
+	}
+}
+
+type gradientRationalCircuit struct {
+	X, Y, B frontend.Variable
+}
+
+func (c *gradientRationalCircuit) Define(api frontend.API) error {
+	// g = X/Y + 1/X + (X + XY) + (B ? X² : Y)
+	g := api.Add(api.Div(c.X, c.Y), api.Inverse(c.X), api.MulAcc(c.X, c.X, c.Y), api.Select(c.B, api.Mul(c.X, c.X), c.Y))
+	api.AssertIsEqual(g, 0)
+	// the binary decomposition is piecewise constant
+	api.AssertIsEqual(api.FromBinary(api.ToBinary(c.X, 8)...), 0)
+	return nil
+}
+
+func TestGradientRational(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	x, y := big.NewInt(6), big.NewInt(7)
+	g, err := Gradient(&gradientRationalCircuit{}, &gradientRationalCircuit{X: x, Y: y, B: 1}, field, []string{"X", "Y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv := func(v *big.Int) *big.Int { return new(big.Int).ModInverse(v, field) }
+	mod := func(v *big.Int) *big.Int { return v.Mod(v, field) }
+
+	// dg/dX = 1/Y - 1/X² + 1 + Y + 2X
+	dX := new(big.Int).Sub(inv(y), inv(new(big.Int).Mul(x, x)))
+	dX.Add(dX, new(big.Int).Add(big.NewInt(1), y))
+	dX.Add(dX, new(big.Int).Lsh(x, 1))
+	// dg/dY = -X/Y² + X
+	dY := new(big.Int).Mul(x, inv(new(big.Int).Mul(y, y)))
+	dY.Sub(x, dY)
+	for j, expected := range []*big.Int{mod(dX), mod(dY)} {
+		if g.Partials[0][j].Cmp(expected) != 0 {
+			t.Fatalf("partial %d: expected %s, got %s", j, expected, g.Partials[0][j])
+		}
+	}
+	for j := range g.Partials[1] {
+		if g.Partials[1][j].Sign() != 0 {
+			t.Fatalf("expected zero derivative of the binary decomposition")
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// Simulate compiles the circuit to R1CS over the scalar field of curveID and
+// solves it with the assignment, checking all the constraints. Unlike a full
+// proving run, it does not perform the setup nor compute a proof, which makes
+// it orders of magnitude faster when iterating on a circuit.
+//
+// It returns the values of the public inputs of the assignment, indexed by
+// their full name, or an error if the assignment does not satisfy the circuit.
+func Simulate(circuit, assignment frontend.Circuit, curveID ecc.ID) (map[string]*big.Int, error) {
+	field := curveID.ScalarField()
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, circuit)
+	if err != nil {
+		return nil, fmt.Errorf("compile: %w", err)
+	}
+	w, err := frontend.NewWitness(assignment, field)
+	if err != nil {
+		return nil, fmt.Errorf("new witness: %w", err)
+	}
+	if err := ccs.IsSolved(w); err != nil {
+		return nil, err
+	}
+
+	publicOutputs := make(map[string]*big.Int)
+	_, err = schema.Walk(assignment, tVariable, func(leaf schema.LeafInfo, tValue reflect.Value) error {
+		if leaf.Visibility == schema.Public {
+			v := utils.FromInterface(tValue.Interface())
+			publicOutputs[leaf.FullName()] = v.Mod(&v, field)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return publicOutputs, nil
+}
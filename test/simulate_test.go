@@ -0,0 +1,40 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+type simulateCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+	Z struct {
+		W frontend.Variable `gnark:",public"`
+	}
+}
+
+func (c *simulateCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	api.AssertIsEqual(api.Add(c.X, 1), c.Z.W)
+	return nil
+}
+
+func TestSimulate(t *testing.T) {
+	var valid simulateCircuit
+	valid.X, valid.Y, valid.Z.W = 3, 9, 4
+	outputs, err := Simulate(&simulateCircuit{}, &valid, ecc.BN254)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 2 || outputs["Y"].Uint64() != 9 || outputs["Z_W"].Uint64() != 4 {
+		t.Fatalf("unexpected public outputs %v", outputs)
+	}
+
+	invalid := valid
+	invalid.Y = 10
+	if _, err := Simulate(&simulateCircuit{}, &invalid, ecc.BN254); err == nil {
+		t.Fatal("expected error for invalid assignment")
+	}
+}
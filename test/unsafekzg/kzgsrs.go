@@ -71,6 +71,25 @@ func NewSRS(ccs constraint.ConstraintSystem, opts ...Option) (canonical kzg.SRS,
 
 	curveID := utils.FieldToCurve(ccs.Field())
 
+	return cachedSRS(curveID, sizeCanonical, opts...)
+}
+
+// NewSRSWithSize returns a kzg.SRS in canonical form which can be used for
+// all PLONK constraint systems over curveID with at most maxConstraints
+// constraints, including the placeholder constraints for the public inputs.
+// The SRS is circuit independent and can be given to plonk.SetupWithSRS for
+// different circuits. Default options use a memory cache, see Option for more
+// details & options.
+func NewSRSWithSize(curveID ecc.ID, maxConstraints int, opts ...Option) (kzg.SRS, error) {
+	if maxConstraints < 1 {
+		return nil, fmt.Errorf("invalid number of constraints %d", maxConstraints)
+	}
+	sizeCanonical := ecc.NextPowerOfTwo(uint64(maxConstraints)) + 3
+	canonical, _, err := cachedSRS(curveID, sizeCanonical, opts...)
+	return canonical, err
+}
+
+func cachedSRS(curveID ecc.ID, sizeCanonical uint64, opts ...Option) (canonical kzg.SRS, lagrange kzg.SRS, err error) {
 	log := logger.Logger().With().Str("package", "kzgsrs").Int("size", int(sizeCanonical)).Str("curve", curveID.String()).Logger()
 
 	cfg, err := options(opts...)